@@ -0,0 +1,155 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2024 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package ar
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	magic      = "!<arch>\n"
+	fileMagic  = "`\n"
+	headerSize = 60
+
+	nameBSD     = "#1/"
+	nameGNU     = "//"
+	nameSymbols = "/"
+	nameSym64   = "/SYM64/"
+)
+
+var ErrInvalidHeader = errors.New("invalid ar header")
+
+type header struct {
+	name  string
+	mtime int64
+	uid   int
+	gid   int
+	mode  uint32
+	size  int64
+}
+
+func field(b []byte) string {
+	return strings.TrimRight(string(b), " ")
+}
+
+func readHeader(r io.Reader) (*header, error) {
+	var b = make([]byte, headerSize)
+
+	if _, e := io.ReadFull(r, b); e != nil {
+		return nil, e
+	} else if string(b[58:60]) != fileMagic {
+		return nil, ErrInvalidHeader
+	}
+
+	var (
+		e error
+		h = &header{
+			name: field(b[0:16]),
+		}
+	)
+
+	if v := field(b[16:28]); len(v) > 0 {
+		if h.mtime, e = strconv.ParseInt(v, 10, 64); e != nil {
+			return nil, ErrInvalidHeader
+		}
+	}
+
+	if v := field(b[28:34]); len(v) > 0 {
+		if h.uid, e = strconv.Atoi(v); e != nil {
+			return nil, ErrInvalidHeader
+		}
+	}
+
+	if v := field(b[34:40]); len(v) > 0 {
+		if h.gid, e = strconv.Atoi(v); e != nil {
+			return nil, ErrInvalidHeader
+		}
+	}
+
+	if v := field(b[40:48]); len(v) > 0 {
+		if m, er := strconv.ParseUint(v, 8, 32); er != nil {
+			return nil, ErrInvalidHeader
+		} else {
+			h.mode = uint32(m)
+		}
+	}
+
+	if h.size, e = strconv.ParseInt(field(b[48:58]), 10, 64); e != nil || h.size < 0 {
+		return nil, ErrInvalidHeader
+	}
+
+	return h, nil
+}
+
+func (h *header) write(w io.Writer) error {
+	s := fmt.Sprintf("%-16s%-12d%-6d%-6d%-8o%-10d%s", h.name, h.mtime, h.uid, h.gid, h.mode, h.size, fileMagic)
+
+	if len(s) != headerSize {
+		return ErrInvalidHeader
+	}
+
+	_, e := io.WriteString(w, s)
+	return e
+}
+
+func (h *header) FileInfo() fs.FileInfo {
+	return &info{h: h}
+}
+
+type info struct {
+	h *header
+}
+
+func (i *info) Name() string {
+	return path.Base(i.h.name)
+}
+
+func (i *info) Size() int64 {
+	return i.h.size
+}
+
+func (i *info) Mode() fs.FileMode {
+	return fs.FileMode(i.h.mode & 0777)
+}
+
+func (i *info) ModTime() time.Time {
+	return time.Unix(i.h.mtime, 0)
+}
+
+func (i *info) IsDir() bool {
+	return false
+}
+
+func (i *info) Sys() any {
+	return nil
+}
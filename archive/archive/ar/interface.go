@@ -0,0 +1,48 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2024 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package ar
+
+import (
+	"io"
+
+	arctps "github.com/nabbar/golib/archive/archive/types"
+)
+
+// NewReader returns a reader of unix ar archive, like debian packages.
+// Both GNU and BSD variants of long file names are supported.
+func NewReader(r io.ReadCloser) (arctps.Reader, error) {
+	return &rdr{
+		r: r,
+	}, nil
+}
+
+// NewWriter returns a writer of unix ar archive. Only regular files can be added.
+// File names longer than 16 characters are stored with the BSD variant.
+func NewWriter(w io.WriteCloser) (arctps.Writer, error) {
+	return &wrt{
+		w: w,
+	}, nil
+}
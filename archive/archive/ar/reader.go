@@ -0,0 +1,240 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2024 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package ar
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	arctps "github.com/nabbar/golib/archive/archive/types"
+)
+
+type reset interface {
+	Reset() bool
+}
+
+type rdr struct {
+	r io.ReadCloser
+	c io.Reader // content of current entry
+	p int64     // padding after current entry
+	n []byte    // GNU long names table
+	s bool      // started
+	e error     // error of the last walk
+}
+
+func (o *rdr) Reset() bool {
+	if r, k := o.r.(reset); k {
+		return r.Reset()
+	}
+
+	return false
+}
+
+func (o *rdr) Close() error {
+	return o.r.Close()
+}
+
+func (o *rdr) restart() error {
+	if o.Reset() || !o.s {
+		o.s = true
+		o.c = nil
+		o.p = 0
+
+		var b = make([]byte, len(magic))
+		if _, e := io.ReadFull(o.r, b); e != nil {
+			return e
+		} else if string(b) != magic {
+			return ErrInvalidHeader
+		}
+	}
+
+	return nil
+}
+
+// next skips the end of the current entry and returns the header of the next file.
+// Special entries like symbol table or long names table are not returned.
+func (o *rdr) next() (*header, error) {
+	for {
+		if o.c != nil {
+			if _, e := io.Copy(io.Discard, o.c); e != nil {
+				return nil, e
+			} else if l, k := o.c.(*io.LimitedReader); k && l.N > 0 {
+				// content of current entry is truncated
+				return nil, io.ErrUnexpectedEOF
+			}
+		}
+
+		if o.p > 0 {
+			if _, e := io.CopyN(io.Discard, o.r, o.p); e == io.EOF {
+				return nil, io.ErrUnexpectedEOF
+			} else if e != nil {
+				return nil, e
+			}
+		}
+
+		h, e := readHeader(o.r)
+		if e != nil {
+			return nil, e
+		}
+
+		o.c = io.LimitReader(o.r, h.size)
+		o.p = h.size % 2
+
+		switch {
+		case h.name == nameSymbols || h.name == nameSym64 || strings.HasPrefix(h.name, "__.SYMDEF"):
+			continue
+		case h.name == nameGNU:
+			if o.n, e = io.ReadAll(o.c); e != nil {
+				return nil, e
+			}
+			continue
+		case strings.HasPrefix(h.name, nameBSD):
+			// BSD long name is stored at the beginning of the content
+			l, er := strconv.Atoi(strings.TrimPrefix(h.name, nameBSD))
+			if er != nil || int64(l) > h.size {
+				return nil, ErrInvalidHeader
+			}
+
+			var b = make([]byte, l)
+			if _, er = io.ReadFull(o.c, b); er != nil {
+				return nil, er
+			}
+
+			h.name = string(bytes.TrimRight(b, "\x00"))
+			h.size -= int64(l)
+		case strings.HasPrefix(h.name, "/") && len(o.n) > 0:
+			// GNU long name is an offset into the long names table
+			i, er := strconv.Atoi(strings.TrimPrefix(h.name, "/"))
+			if er != nil || i >= len(o.n) {
+				return nil, ErrInvalidHeader
+			}
+
+			n := o.n[i:]
+			if j := bytes.Index(n, []byte("/\n")); j >= 0 {
+				n = n[:j]
+			}
+
+			h.name = string(n)
+		default:
+			h.name = strings.TrimSuffix(h.name, "/")
+		}
+
+		return h, nil
+	}
+}
+
+func (o *rdr) List() ([]string, error) {
+	var l = make([]string, 0)
+
+	if e := o.restart(); e != nil {
+		return nil, e
+	}
+
+	for {
+		if h, e := o.next(); e == io.EOF {
+			return l, nil
+		} else if e != nil {
+			return nil, e
+		} else {
+			l = append(l, h.name)
+		}
+	}
+}
+
+func (o *rdr) Info(s string) (fs.FileInfo, error) {
+	if e := o.restart(); e != nil {
+		return nil, e
+	}
+
+	for {
+		if h, e := o.next(); e == io.EOF {
+			return nil, fs.ErrNotExist
+		} else if e != nil {
+			return nil, e
+		} else if h.name == s {
+			return h.FileInfo(), nil
+		}
+	}
+}
+
+func (o *rdr) Get(s string) (io.ReadCloser, error) {
+	if e := o.restart(); e != nil {
+		return nil, e
+	}
+
+	for {
+		if h, e := o.next(); e == io.EOF {
+			return nil, fs.ErrNotExist
+		} else if e != nil {
+			return nil, e
+		} else if h.name == s {
+			return io.NopCloser(o.c), nil
+		}
+	}
+}
+
+func (o *rdr) Has(s string) bool {
+	if e := o.restart(); e != nil {
+		return false
+	}
+
+	for {
+		if h, e := o.next(); e != nil {
+			break
+		} else if h.name == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o *rdr) Walk(fct arctps.FuncExtract) {
+	if o.e = o.restart(); o.e != nil {
+		return
+	}
+
+	for {
+		h, e := o.next()
+		if e == io.EOF {
+			return
+		} else if e != nil {
+			o.e = e
+			return
+		}
+
+		if !fct(h.FileInfo(), io.NopCloser(o.c), h.name, "") {
+			return
+		}
+	}
+}
+
+func (o *rdr) WalkErr() error {
+	return o.e
+}
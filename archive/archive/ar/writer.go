@@ -0,0 +1,171 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2024 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package ar
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	arctps "github.com/nabbar/golib/archive/archive/types"
+)
+
+type wrt struct {
+	w io.WriteCloser
+	s bool // magic written
+}
+
+func (o *wrt) start() error {
+	if o.s {
+		return nil
+	}
+
+	o.s = true
+	_, e := io.WriteString(o.w, magic)
+	return e
+}
+
+func (o *wrt) Close() error {
+	if e := o.start(); e != nil {
+		return e
+	}
+
+	return o.w.Close()
+}
+
+// Add adds a regular file to the ar archive.
+//
+// It takes in the file information, the file reader, and the path to use into the archive.
+// It returns an error if the file is not a regular file or if any operation fails.
+func (o *wrt) Add(i fs.FileInfo, r io.ReadCloser, forcePath, notUse string) error {
+	defer func() {
+		if r != nil {
+			_ = r.Close()
+		}
+	}()
+
+	if i == nil || !i.Mode().IsRegular() || r == nil {
+		return fs.ErrInvalid
+	} else if e := o.start(); e != nil {
+		return e
+	}
+
+	var (
+		n = filepath.ToSlash(forcePath)
+		h = &header{
+			mtime: i.ModTime().Unix(),
+			mode:  uint32(i.Mode().Perm()) | 0100000,
+			size:  i.Size(),
+		}
+	)
+
+	if len(n) < 1 {
+		n = i.Name()
+	}
+
+	if len(n) > 16 || strings.ContainsAny(n, " ") || strings.HasPrefix(n, nameBSD) {
+		h.name = nameBSD + strconv.Itoa(len(n))
+		h.size += int64(len(n))
+	} else {
+		h.name = n
+	}
+
+	if e := h.write(o.w); e != nil {
+		return e
+	}
+
+	if strings.HasPrefix(h.name, nameBSD) {
+		if _, e := io.WriteString(o.w, n); e != nil {
+			return e
+		}
+	}
+
+	if _, e := io.CopyN(o.w, r, i.Size()); e != nil {
+		return e
+	} else if h.size%2 != 0 {
+		if _, e = o.w.Write([]byte{'\n'}); e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+func (o *wrt) FromPath(source string, filter string, fct arctps.ReplaceName) error {
+	if i, e := os.Stat(source); e == nil && !i.IsDir() {
+		return o.addFiltering(source, filter, fct, i)
+	}
+
+	return filepath.Walk(source, func(path string, info fs.FileInfo, e error) error {
+		if e != nil {
+			return e
+		}
+
+		return o.addFiltering(path, filter, fct, info)
+	})
+}
+
+func (o *wrt) addFiltering(source string, filter string, fct arctps.ReplaceName, info fs.FileInfo) error {
+	var (
+		ok  bool
+		err error
+		hdf *os.File
+	)
+
+	if len(filter) < 1 {
+		filter = "*"
+	}
+
+	if fct == nil {
+		fct = func(source string) string {
+			return source
+		}
+	}
+
+	if ok, err = filepath.Match(filter, source); err != nil {
+		return err
+	} else if !ok {
+		return nil
+	}
+
+	if info == nil {
+		return fs.ErrInvalid
+	} else if !info.Mode().IsRegular() {
+		// ar archive only store regular files
+		return nil
+	} else if hdf, err = os.Open(source); err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = hdf.Close()
+	}()
+
+	return o.Add(info, hdf, fct(source), "")
+}
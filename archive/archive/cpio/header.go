@@ -0,0 +1,228 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2024 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package cpio
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"time"
+)
+
+const (
+	magicNewc = "070701"
+	magicCRC  = "070702"
+	trailer   = "TRAILER!!!"
+
+	headerSize = 110
+)
+
+// unix file type and permissions bits
+const (
+	modeTypeMask = 0170000
+	modeSocket   = 0140000
+	modeSymlink  = 0120000
+	modeRegular  = 0100000
+	modeBlock    = 0060000
+	modeDir      = 0040000
+	modeChar     = 0020000
+	modeFifo     = 0010000
+	modeSetuid   = 0004000
+	modeSetgid   = 0002000
+	modeSticky   = 0001000
+)
+
+var ErrInvalidHeader = errors.New("invalid cpio header")
+
+type header struct {
+	ino   uint32
+	mode  uint32
+	uid   uint32
+	gid   uint32
+	nlink uint32
+	mtime uint32
+	size  uint32
+	name  string
+}
+
+func pad4(n int64) int64 {
+	return (4 - n%4) % 4
+}
+
+func readHeader(r io.Reader) (*header, error) {
+	var b = make([]byte, headerSize)
+
+	if _, e := io.ReadFull(r, b); e != nil {
+		return nil, e
+	} else if m := string(b[0:6]); m != magicNewc && m != magicCRC {
+		return nil, ErrInvalidHeader
+	}
+
+	var f [13]uint32
+
+	for i := range f {
+		if v, e := strconv.ParseUint(string(b[6+i*8:14+i*8]), 16, 32); e != nil {
+			return nil, ErrInvalidHeader
+		} else {
+			f[i] = uint32(v)
+		}
+	}
+
+	h := &header{
+		ino:   f[0],
+		mode:  f[1],
+		uid:   f[2],
+		gid:   f[3],
+		nlink: f[4],
+		mtime: f[5],
+		size:  f[6],
+	}
+
+	n := int64(f[11])
+	if n < 1 {
+		return nil, ErrInvalidHeader
+	}
+
+	var p = make([]byte, n+pad4(headerSize+n))
+	if _, e := io.ReadFull(r, p); e != nil {
+		return nil, e
+	}
+
+	h.name = string(p[:n-1])
+	return h, nil
+}
+
+func (h *header) write(w io.Writer) error {
+	var n = int64(len(h.name) + 1)
+
+	s := fmt.Sprintf("%s%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X",
+		magicNewc, h.ino, h.mode, h.uid, h.gid, h.nlink, h.mtime, h.size, 0, 0, 0, 0, n, 0)
+
+	p := append([]byte(s), []byte(h.name)...)
+	p = append(p, make([]byte, 1+pad4(headerSize+n))...)
+
+	_, e := w.Write(p)
+	return e
+}
+
+func (h *header) FileInfo() fs.FileInfo {
+	return &info{h: h}
+}
+
+type info struct {
+	h *header
+}
+
+func (i *info) Name() string {
+	return path.Base(i.h.name)
+}
+
+func (i *info) Size() int64 {
+	return int64(i.h.size)
+}
+
+func (i *info) Mode() fs.FileMode {
+	return fileMode(i.h.mode)
+}
+
+func (i *info) ModTime() time.Time {
+	return time.Unix(int64(i.h.mtime), 0)
+}
+
+func (i *info) IsDir() bool {
+	return i.Mode().IsDir()
+}
+
+func (i *info) Sys() any {
+	return nil
+}
+
+func fileMode(m uint32) fs.FileMode {
+	var r = fs.FileMode(m & 0777)
+
+	switch m & modeTypeMask {
+	case modeDir:
+		r |= fs.ModeDir
+	case modeSymlink:
+		r |= fs.ModeSymlink
+	case modeBlock:
+		r |= fs.ModeDevice
+	case modeChar:
+		r |= fs.ModeDevice | fs.ModeCharDevice
+	case modeFifo:
+		r |= fs.ModeNamedPipe
+	case modeSocket:
+		r |= fs.ModeSocket
+	}
+
+	if m&modeSetuid != 0 {
+		r |= fs.ModeSetuid
+	}
+	if m&modeSetgid != 0 {
+		r |= fs.ModeSetgid
+	}
+	if m&modeSticky != 0 {
+		r |= fs.ModeSticky
+	}
+
+	return r
+}
+
+func unixMode(m fs.FileMode) uint32 {
+	var r = uint32(m.Perm())
+
+	switch {
+	case m.IsDir():
+		r |= modeDir
+	case m&fs.ModeSymlink != 0:
+		r |= modeSymlink
+	case m&fs.ModeCharDevice != 0:
+		r |= modeChar
+	case m&fs.ModeDevice != 0:
+		r |= modeBlock
+	case m&fs.ModeNamedPipe != 0:
+		r |= modeFifo
+	case m&fs.ModeSocket != 0:
+		r |= modeSocket
+	default:
+		r |= modeRegular
+	}
+
+	if m&fs.ModeSetuid != 0 {
+		r |= modeSetuid
+	}
+	if m&fs.ModeSetgid != 0 {
+		r |= modeSetgid
+	}
+	if m&fs.ModeSticky != 0 {
+		r |= modeSticky
+	}
+
+	return r
+}
@@ -0,0 +1,195 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2024 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package cpio
+
+import (
+	"io"
+	"io/fs"
+
+	arctps "github.com/nabbar/golib/archive/archive/types"
+)
+
+type reset interface {
+	Reset() bool
+}
+
+type rdr struct {
+	r io.ReadCloser
+	c io.Reader // content of current entry
+	p int64     // padding after current entry
+	s bool      // started
+	e error     // error of the last walk
+}
+
+func (o *rdr) Reset() bool {
+	if r, k := o.r.(reset); k {
+		return r.Reset()
+	}
+
+	return false
+}
+
+func (o *rdr) Close() error {
+	return o.r.Close()
+}
+
+func (o *rdr) restart() {
+	if o.Reset() || !o.s {
+		o.c = nil
+		o.p = 0
+	}
+
+	o.s = true
+}
+
+// next skips the end of the current entry and returns the header of the next entry.
+func (o *rdr) next() (*header, error) {
+	if o.c != nil {
+		if _, e := io.Copy(io.Discard, o.c); e != nil {
+			return nil, e
+		} else if l, k := o.c.(*io.LimitedReader); k && l.N > 0 {
+			// content of current entry is truncated
+			return nil, io.ErrUnexpectedEOF
+		}
+	}
+
+	if o.p > 0 {
+		if _, e := io.CopyN(io.Discard, o.r, o.p); e == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		} else if e != nil {
+			return nil, e
+		}
+	}
+
+	h, e := readHeader(o.r)
+	if e != nil {
+		return nil, e
+	} else if h.name == trailer {
+		return nil, io.EOF
+	}
+
+	o.c = io.LimitReader(o.r, int64(h.size))
+	o.p = pad4(int64(h.size))
+
+	return h, nil
+}
+
+func (o *rdr) target(h *header) (string, error) {
+	if h.mode&modeTypeMask != modeSymlink {
+		return "", nil
+	} else if p, e := io.ReadAll(o.c); e != nil {
+		return "", e
+	} else {
+		return string(p), nil
+	}
+}
+
+func (o *rdr) List() ([]string, error) {
+	var l = make([]string, 0)
+
+	o.restart()
+
+	for {
+		if h, e := o.next(); e == io.EOF {
+			return l, nil
+		} else if e != nil {
+			return nil, e
+		} else {
+			l = append(l, h.name)
+		}
+	}
+}
+
+func (o *rdr) Info(s string) (fs.FileInfo, error) {
+	o.restart()
+
+	for {
+		if h, e := o.next(); e == io.EOF {
+			return nil, fs.ErrNotExist
+		} else if e != nil {
+			return nil, e
+		} else if h.name == s {
+			return h.FileInfo(), nil
+		}
+	}
+}
+
+func (o *rdr) Get(s string) (io.ReadCloser, error) {
+	o.restart()
+
+	for {
+		if h, e := o.next(); e == io.EOF {
+			return nil, fs.ErrNotExist
+		} else if e != nil {
+			return nil, e
+		} else if h.name == s {
+			return io.NopCloser(o.c), nil
+		}
+	}
+}
+
+func (o *rdr) Has(s string) bool {
+	o.restart()
+
+	for {
+		if h, e := o.next(); e != nil {
+			break
+		} else if h.name == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o *rdr) Walk(fct arctps.FuncExtract) {
+	o.e = nil
+	o.restart()
+
+	for {
+		h, e := o.next()
+		if e == io.EOF {
+			return
+		} else if e != nil {
+			o.e = e
+			return
+		}
+
+		t, e := o.target(h)
+		if e != nil {
+			o.e = e
+			return
+		}
+
+		if !fct(h.FileInfo(), io.NopCloser(o.c), h.name, t) {
+			return
+		}
+	}
+}
+
+func (o *rdr) WalkErr() error {
+	return o.e
+}
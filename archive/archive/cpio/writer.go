@@ -0,0 +1,188 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2024 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package cpio
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	arctps "github.com/nabbar/golib/archive/archive/types"
+)
+
+const blockSize = 512
+
+type wrt struct {
+	w io.WriteCloser
+	n int64  // written bytes
+	i uint32 // last inode number given
+}
+
+func (o *wrt) Write(p []byte) (int, error) {
+	n, e := o.w.Write(p)
+	o.n += int64(n)
+	return n, e
+}
+
+func (o *wrt) Close() error {
+	h := &header{
+		nlink: 1,
+		name:  trailer,
+	}
+
+	if e := h.write(o); e != nil {
+		return e
+	}
+
+	// cpio tools expect an archive padded with block of 512 bytes
+	if p := (blockSize - o.n%blockSize) % blockSize; p > 0 {
+		if _, e := o.Write(make([]byte, p)); e != nil {
+			return e
+		}
+	}
+
+	return o.w.Close()
+}
+
+// Add adds a file to the cpio archive.
+//
+// It takes in the file information, the file reader, and the target path if the new file is a link.
+// It returns an error if any operation fails.
+func (o *wrt) Add(i fs.FileInfo, r io.ReadCloser, forcePath, target string) error {
+	defer func() {
+		if r != nil {
+			_ = r.Close()
+		}
+	}()
+
+	if i == nil {
+		return fs.ErrInvalid
+	}
+
+	o.i++
+
+	h := &header{
+		ino:   o.i,
+		mode:  unixMode(i.Mode()),
+		nlink: 1,
+		mtime: uint32(i.ModTime().Unix()),
+		name:  filepath.ToSlash(forcePath),
+	}
+
+	if len(h.name) < 1 {
+		h.name = i.Name()
+	}
+
+	var s io.Reader = r
+
+	if i.Mode()&os.ModeSymlink != 0 {
+		h.size = uint32(len(target))
+		s = strings.NewReader(target)
+	} else if i.Mode().IsRegular() {
+		h.size = uint32(i.Size())
+	} else {
+		s = nil
+	}
+
+	if e := h.write(o); e != nil {
+		return e
+	} else if h.size < 1 {
+		return nil
+	} else if s == nil {
+		return fs.ErrInvalid
+	} else if _, e = io.CopyN(o, s, int64(h.size)); e != nil {
+		return e
+	} else if p := pad4(int64(h.size)); p > 0 {
+		if _, e = o.Write(make([]byte, p)); e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+func (o *wrt) FromPath(source string, filter string, fct arctps.ReplaceName) error {
+	if i, e := os.Stat(source); e == nil && !i.IsDir() {
+		return o.addFiltering(source, filter, fct, i)
+	}
+
+	return filepath.Walk(source, func(path string, info fs.FileInfo, e error) error {
+		if e != nil {
+			return e
+		}
+
+		return o.addFiltering(path, filter, fct, info)
+	})
+}
+
+func (o *wrt) addFiltering(source string, filter string, fct arctps.ReplaceName, info fs.FileInfo) error {
+	var (
+		ok     bool
+		err    error
+		hdf    *os.File
+		target string
+	)
+
+	if len(filter) < 1 {
+		filter = "*"
+	}
+
+	if fct == nil {
+		fct = func(source string) string {
+			return source
+		}
+	}
+
+	if ok, err = filepath.Match(filter, source); err != nil {
+		return err
+	} else if !ok {
+		return nil
+	}
+
+	if info == nil {
+		return fs.ErrInvalid
+	} else if info.IsDir() {
+		return o.Add(info, nil, fct(source), "")
+	} else if info.Mode()&os.ModeSymlink != 0 { // SymLink
+		if target, err = os.Readlink(source); err != nil {
+			return err
+		}
+	} else if info.Mode().IsRegular() {
+		if hdf, err = os.Open(source); err != nil {
+			return err
+		} else {
+			defer func() {
+				_ = hdf.Close()
+			}()
+		}
+	} else {
+		return fs.ErrInvalid
+	}
+
+	return o.Add(info, hdf, fct(source), target)
+}
@@ -47,6 +47,12 @@ func (a *Algorithm) UnmarshalText(b []byte) error {
 		*a = Tar
 	case strings.EqualFold(s, Zip.String()):
 		*a = Zip
+	case strings.EqualFold(s, Cpio.String()):
+		*a = Cpio
+	case strings.EqualFold(s, Ar.String()), strings.EqualFold(s, "deb"):
+		*a = Ar
+	case strings.EqualFold(s, SevenZip.String()), strings.EqualFold(s, "7zip"), strings.EqualFold(s, "sevenzip"):
+		*a = SevenZip
 	default:
 		*a = None
 	}
@@ -27,6 +27,7 @@ package archive
 
 import (
 	"bufio"
+	"errors"
 	"io"
 
 	arctps "github.com/nabbar/golib/archive/archive/types"
@@ -68,7 +69,8 @@ func Detect(r io.ReadCloser) (Algorithm, arctps.Reader, io.ReadCloser, error) {
 		}
 	)
 
-	if buf, err = bfr.Peek(265); err != nil {
+	// archive smaller than the peek size are still detected by their magic number
+	if buf, err = bfr.Peek(265); err != nil && !errors.Is(err, io.EOF) {
 		return None, nil, nil, err
 	}
 
@@ -88,6 +90,28 @@ func Detect(r io.ReadCloser) (Algorithm, arctps.Reader, io.ReadCloser, error) {
 			return Zip, z, r, nil
 		}
 
+	case SevenZip.DetectHeader(buf): // 7z
+		bfr.b = nil // do not use buffer (using ReaderAt)
+		if z, e := SevenZip.Reader(bfr); e != nil {
+			return None, nil, nil, e
+		} else {
+			return SevenZip, z, r, nil
+		}
+
+	case Cpio.DetectHeader(buf): // cpio
+		if c, e := Cpio.Reader(bfr); e != nil {
+			return None, nil, nil, e
+		} else {
+			return Cpio, c, bfr, nil
+		}
+
+	case Ar.DetectHeader(buf): // ar
+		if a, e := Ar.Reader(bfr); e != nil {
+			return None, nil, nil, e
+		} else {
+			return Ar, a, bfr, nil
+		}
+
 	default:
 		return None, nil, bfr, nil
 	}
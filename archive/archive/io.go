@@ -29,6 +29,9 @@ import (
 	"errors"
 	"io"
 
+	arcarr "github.com/nabbar/golib/archive/archive/ar"
+	arccpi "github.com/nabbar/golib/archive/archive/cpio"
+	arcsvz "github.com/nabbar/golib/archive/archive/sevenzip"
 	arctar "github.com/nabbar/golib/archive/archive/tar"
 	arctps "github.com/nabbar/golib/archive/archive/types"
 	arczip "github.com/nabbar/golib/archive/archive/zip"
@@ -44,6 +47,12 @@ func (a Algorithm) Reader(r io.ReadCloser) (arctps.Reader, error) {
 		return arctar.NewReader(r)
 	case Zip:
 		return arczip.NewReader(r)
+	case Cpio:
+		return arccpi.NewReader(r)
+	case Ar:
+		return arcarr.NewReader(r)
+	case SevenZip:
+		return arcsvz.NewReader(r)
 	default:
 		return nil, ErrInvalidAlgorithm
 	}
@@ -55,6 +64,12 @@ func (a Algorithm) Writer(w io.WriteCloser) (arctps.Writer, error) {
 		return arctar.NewWriter(w)
 	case Zip:
 		return arczip.NewWriter(w)
+	case Cpio:
+		return arccpi.NewWriter(w)
+	case Ar:
+		return arcarr.NewWriter(w)
+	case SevenZip:
+		return arcsvz.NewWriter(w)
 	default:
 		return nil, ErrInvalidAlgorithm
 	}
@@ -0,0 +1,104 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2024 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package sevenzip
+
+import (
+	"io"
+	"io/fs"
+
+	libsvz "github.com/bodgit/sevenzip"
+	arctps "github.com/nabbar/golib/archive/archive/types"
+)
+
+type rdr struct {
+	r io.ReadCloser
+	z *libsvz.Reader
+	e error // error of the last walk
+}
+
+func (o *rdr) Close() error {
+	return o.r.Close()
+}
+
+func (o *rdr) List() ([]string, error) {
+	var res = make([]string, 0, len(o.z.File))
+
+	for _, f := range o.z.File {
+		res = append(res, f.Name)
+	}
+
+	return res, nil
+}
+
+func (o *rdr) Info(s string) (fs.FileInfo, error) {
+	for _, f := range o.z.File {
+		if f.Name == s {
+			return f.FileInfo(), nil
+		}
+	}
+
+	return nil, fs.ErrNotExist
+}
+
+func (o *rdr) Get(s string) (io.ReadCloser, error) {
+	for _, f := range o.z.File {
+		if f.Name == s {
+			return f.Open()
+		}
+	}
+
+	return nil, fs.ErrNotExist
+}
+
+func (o *rdr) Has(s string) bool {
+	for _, f := range o.z.File {
+		if f.Name == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o *rdr) Walk(fct arctps.FuncExtract) {
+	o.e = nil
+
+	for _, f := range o.z.File {
+		r, e := f.Open()
+		if e != nil {
+			o.e = e
+			return
+		}
+
+		if !fct(f.FileInfo(), r, f.Name, "") {
+			return
+		}
+	}
+}
+
+func (o *rdr) WalkErr() error {
+	return o.e
+}
@@ -33,6 +33,9 @@ const (
 	None Algorithm = iota
 	Tar
 	Zip
+	Cpio
+	Ar
+	SevenZip
 )
 
 func (a Algorithm) IsNone() bool {
@@ -45,6 +48,12 @@ func (a Algorithm) String() string {
 		return "tar"
 	case Zip:
 		return "zip"
+	case Cpio:
+		return "cpio"
+	case Ar:
+		return "ar"
+	case SevenZip:
+		return "7z"
 	default:
 		return "none"
 	}
@@ -56,24 +65,38 @@ func (a Algorithm) Extension() string {
 		return ".tar"
 	case Zip:
 		return ".zip"
+	case Cpio:
+		return ".cpio"
+	case Ar:
+		return ".ar"
+	case SevenZip:
+		return ".7z"
 	default:
 		return ""
 	}
 }
 
 func (a Algorithm) DetectHeader(h []byte) bool {
-	if len(h) < 263 {
-		return false
-	}
-
 	switch a {
 	case Tar:
+		if len(h) < 263 {
+			return false
+		}
 		exp := append([]byte("ustar"), 0x00)
 		val := h[257:263]
 		return bytes.Equal(val, exp)
 	case Zip:
 		exp := []byte{0x50, 0x4b, 0x03, 0x04}
-		return bytes.Equal(h[0:4], exp)
+		return len(h) >= 4 && bytes.Equal(h[0:4], exp)
+	case Cpio:
+		// newc format, without or with crc
+		return len(h) >= 6 && (bytes.Equal(h[0:6], []byte("070701")) || bytes.Equal(h[0:6], []byte("070702")))
+	case Ar:
+		exp := []byte("!<arch>\n")
+		return len(h) >= 8 && bytes.Equal(h[0:8], exp)
+	case SevenZip:
+		exp := []byte{'7', 'z', 0xBC, 0xAF, 0x27, 0x1C}
+		return len(h) >= 6 && bytes.Equal(h[0:6], exp)
 	default:
 		return false
 	}
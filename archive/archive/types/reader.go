@@ -75,3 +75,10 @@ type Reader interface {
 	// - string: the link target of the embedded file if it is a link or a symlink.
 	Walk(FuncExtract)
 }
+
+// WalkError is implemented by the readers able to report the error which has stopped their last walk.
+type WalkError interface {
+	// WalkErr returns the error which has stopped the last call of Walk.
+	// It returns nil if the walk reached the end of the archive or was stopped by the walk function.
+	WalkErr() error
+}
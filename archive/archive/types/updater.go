@@ -0,0 +1,63 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2024 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package types
+
+import (
+	"io"
+	"io/fs"
+)
+
+type Updater interface {
+	// Writer allow to add new entries into the archive. The Add function will
+	// return fs.ErrExist if the path is already existing into the archive.
+	// The Close function will finalize the archive: all changes are written
+	// into a temporary file next to the original archive and then renamed to
+	// replace the original archive atomically.
+	Writer
+
+	// List returns the list of path into the archive, including pending changes.
+	List() ([]string, error)
+
+	// Has will check if the archive contains the given path, including pending changes.
+	Has(string) bool
+
+	// Delete will remove the given path from the archive.
+	//
+	// Parameter(s):
+	//   - string: the path of the embedded file to remove.
+	// Returns fs.ErrNotExist if the path is not found into the archive.
+	Delete(string) error
+
+	// Replace will replace the given path into the archive, or add it if not existing.
+	// The replaced entry will be stored at the end of the archive.
+	//
+	// Parameter(s): same as Add function.
+	// Return type: error
+	Replace(fs.FileInfo, io.ReadCloser, string, string) error
+
+	// Abort will discard all pending changes and let the original archive unchanged.
+	Abort() error
+}
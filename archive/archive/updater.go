@@ -0,0 +1,498 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2024 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package archive
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	arctps "github.com/nabbar/golib/archive/archive/types"
+)
+
+type updEntry struct {
+	i fs.FileInfo // file info of the new entry
+	p string      // path into the archive
+	t string      // link target
+	o int64       // offset of content into staging file
+	s int64       // size of content into staging file
+}
+
+type upd struct {
+	a Algorithm
+	p string          // path of the archive file
+	m fs.FileMode     // permission of the archive file
+	e []string        // path of entries already into the archive
+	d map[string]bool // path of existing entries to remove
+	n []updEntry      // pending new entries
+	s *os.File        // staging file storing content of pending new entries
+	z int64           // current size of staging file
+	c bool            // updater closed or aborted
+}
+
+// struct to sync the temporary archive file before closing it
+type updFile struct {
+	*os.File
+}
+
+func (o *updFile) Close() error {
+	if e := o.File.Sync(); e != nil {
+		_ = o.File.Close()
+		return e
+	}
+
+	return o.File.Close()
+}
+
+// Updater returns an arctps.Updater for the archive file of the given path.
+// If the file is not existing or is empty, a new archive will be created on close.
+// If the file is existing, it must be an archive of the current algorithm.
+// The 7z algorithm could not be updated as no writer exists for it.
+func (a Algorithm) Updater(path string) (arctps.Updater, error) {
+	if a == None || a == SevenZip {
+		return nil, ErrInvalidAlgorithm
+	}
+
+	var o = &upd{
+		a: a,
+		p: path,
+		m: 0644,
+		e: make([]string, 0),
+		d: make(map[string]bool),
+		n: make([]updEntry, 0),
+	}
+
+	if i, e := os.Stat(path); errors.Is(e, fs.ErrNotExist) {
+		return o, nil
+	} else if e != nil {
+		return nil, e
+	} else if i.IsDir() {
+		return nil, fs.ErrInvalid
+	} else if o.m = i.Mode().Perm(); i.Size() < 1 {
+		return o, nil
+	}
+
+	if l, e := o.list(); e != nil {
+		return nil, e
+	} else {
+		o.e = l
+	}
+
+	return o, nil
+}
+
+// NewUpdater will detect the algorithm of the existing archive file of the given
+// path and returns the algorithm and an arctps.Updater for this file.
+func NewUpdater(path string) (Algorithm, arctps.Updater, error) {
+	var (
+		e error
+		h *os.File
+		a Algorithm
+	)
+
+	if h, e = os.Open(path); e != nil {
+		return None, nil, e
+	}
+
+	a, _, _, e = Detect(h)
+	_ = h.Close()
+
+	if e != nil {
+		return None, nil, e
+	} else if a == None {
+		return None, nil, ErrInvalidAlgorithm
+	} else if u, err := a.Updater(path); err != nil {
+		return None, nil, err
+	} else {
+		return a, u, nil
+	}
+}
+
+func (o *upd) open() (*os.File, arctps.Reader, error) {
+	if h, e := os.Open(o.p); e != nil {
+		return nil, nil, e
+	} else if a, r, _, err := Detect(h); err != nil {
+		_ = h.Close()
+		return nil, nil, err
+	} else if a != o.a {
+		_ = h.Close()
+		return nil, nil, ErrInvalidAlgorithm
+	} else {
+		return h, r, nil
+	}
+}
+
+func (o *upd) list() ([]string, error) {
+	h, r, e := o.open()
+
+	if e != nil {
+		return nil, e
+	}
+
+	defer func() {
+		_ = h.Close()
+	}()
+
+	return r.List()
+}
+
+func (o *upd) exists(s string) bool {
+	for _, n := range o.n {
+		if n.p == s {
+			return true
+		}
+	}
+
+	if o.d[s] {
+		return false
+	}
+
+	for _, p := range o.e {
+		if p == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o *upd) List() ([]string, error) {
+	if o.c {
+		return nil, fs.ErrClosed
+	}
+
+	var res = make([]string, 0, len(o.e)+len(o.n))
+
+	for _, p := range o.e {
+		if !o.d[p] {
+			res = append(res, p)
+		}
+	}
+
+	for _, n := range o.n {
+		res = append(res, n.p)
+	}
+
+	return res, nil
+}
+
+func (o *upd) Has(s string) bool {
+	if o.c {
+		return false
+	}
+
+	return o.exists(s)
+}
+
+func (o *upd) Delete(s string) error {
+	if o.c {
+		return fs.ErrClosed
+	}
+
+	for k, n := range o.n {
+		if n.p == s {
+			o.n = append(o.n[:k], o.n[k+1:]...)
+			return nil
+		}
+	}
+
+	if !o.exists(s) {
+		return fs.ErrNotExist
+	}
+
+	o.d[s] = true
+	return nil
+}
+
+func (o *upd) Add(i fs.FileInfo, r io.ReadCloser, forcePath, target string) error {
+	defer func() {
+		if r != nil {
+			_ = r.Close()
+		}
+	}()
+
+	if o.c {
+		return fs.ErrClosed
+	} else if i == nil {
+		return fs.ErrInvalid
+	}
+
+	var n = updEntry{
+		i: i,
+		p: filepath.ToSlash(forcePath),
+		t: target,
+	}
+
+	if len(n.p) < 1 {
+		n.p = i.Name()
+	}
+
+	if o.exists(n.p) {
+		return fs.ErrExist
+	}
+
+	if i.Mode().IsRegular() && r != nil {
+		if e := o.stage(&n, r); e != nil {
+			return e
+		}
+	}
+
+	o.n = append(o.n, n)
+	return nil
+}
+
+// stage copies the content of the new entry into the staging file,
+// to allow the caller to close or modify its source before the archive is finalized.
+func (o *upd) stage(n *updEntry, r io.Reader) error {
+	if o.s == nil {
+		if h, e := os.CreateTemp("", "archive-update-*"); e != nil {
+			return e
+		} else {
+			o.s = h
+		}
+	}
+
+	if _, e := o.s.Seek(o.z, io.SeekStart); e != nil {
+		return e
+	} else if s, err := io.CopyN(o.s, r, n.i.Size()); err != nil {
+		return err
+	} else {
+		n.o = o.z
+		n.s = s
+		o.z += s
+	}
+
+	return nil
+}
+
+func (o *upd) Replace(i fs.FileInfo, r io.ReadCloser, forcePath, target string) error {
+	var p = filepath.ToSlash(forcePath)
+
+	if len(p) < 1 && i != nil {
+		p = i.Name()
+	}
+
+	if e := o.Delete(p); e != nil && !errors.Is(e, fs.ErrNotExist) {
+		if r != nil {
+			_ = r.Close()
+		}
+		return e
+	}
+
+	return o.Add(i, r, forcePath, target)
+}
+
+func (o *upd) FromPath(source string, filter string, fct arctps.ReplaceName) error {
+	if i, e := os.Stat(source); e == nil && !i.IsDir() {
+		return o.addFiltering(source, filter, fct, i)
+	}
+
+	return filepath.Walk(source, func(path string, info fs.FileInfo, e error) error {
+		if e != nil {
+			return e
+		}
+
+		return o.addFiltering(path, filter, fct, info)
+	})
+}
+
+func (o *upd) addFiltering(source string, filter string, fct arctps.ReplaceName, info fs.FileInfo) error {
+	var (
+		ok     bool
+		err    error
+		hdf    *os.File
+		target string
+	)
+
+	if len(filter) < 1 {
+		filter = "*"
+	}
+
+	if fct == nil {
+		fct = func(source string) string {
+			return source
+		}
+	}
+
+	if ok, err = filepath.Match(filter, source); err != nil {
+		return err
+	} else if !ok {
+		return nil
+	}
+
+	if info == nil {
+		return fs.ErrInvalid
+	} else if info.IsDir() {
+		return nil
+	} else if info.Mode()&os.ModeSymlink != 0 {
+		if target, err = os.Readlink(source); err != nil {
+			return err
+		}
+	} else if info.Mode().IsRegular() {
+		if hdf, err = os.Open(source); err != nil {
+			return err
+		}
+	} else {
+		return fs.ErrInvalid
+	}
+
+	if hdf == nil {
+		return o.Add(info, nil, fct(source), target)
+	}
+
+	return o.Add(info, hdf, fct(source), target)
+}
+
+func (o *upd) Abort() error {
+	if o.c {
+		return nil
+	}
+
+	o.c = true
+	return o.clean()
+}
+
+func (o *upd) clean() error {
+	if o.s == nil {
+		return nil
+	}
+
+	var n = o.s.Name()
+	_ = o.s.Close()
+	o.s = nil
+
+	return os.Remove(n)
+}
+
+// Close finalizes the archive: existing entries not deleted are copied into a
+// temporary file next to the archive, followed by the pending new entries.
+// The temporary file is then renamed to replace the original archive.
+// If nothing has been changed, the original archive is left untouched.
+func (o *upd) Close() error {
+	if o.c {
+		return fs.ErrClosed
+	}
+
+	o.c = true
+
+	defer func() {
+		_ = o.clean()
+	}()
+
+	if len(o.d) < 1 && len(o.n) < 1 {
+		return nil
+	}
+
+	return o.commit()
+}
+
+func (o *upd) commit() error {
+	var (
+		e error
+		t *os.File
+		w arctps.Writer
+	)
+
+	if t, e = os.CreateTemp(filepath.Dir(o.p), "."+filepath.Base(o.p)+".*"); e != nil {
+		return e
+	}
+
+	defer func() {
+		if e != nil {
+			_ = t.Close()
+			_ = os.Remove(t.Name())
+		}
+	}()
+
+	if w, e = o.a.Writer(&updFile{File: t}); e != nil {
+		return e
+	} else if e = o.copy(w); e != nil {
+		return e
+	}
+
+	for _, n := range o.n {
+		var r io.ReadCloser
+
+		if n.i.Mode().IsRegular() && o.s != nil {
+			r = io.NopCloser(io.NewSectionReader(o.s, n.o, n.s))
+		}
+
+		if e = w.Add(n.i, r, n.p, n.t); e != nil {
+			return e
+		}
+	}
+
+	if e = w.Close(); e != nil {
+		return e
+	} else if e = os.Chmod(t.Name(), o.m); e != nil {
+		return e
+	} else if e = os.Rename(t.Name(), o.p); e != nil {
+		return e
+	}
+
+	return nil
+}
+
+// copy writes all existing entries not deleted into the given writer.
+func (o *upd) copy(w arctps.Writer) error {
+	if len(o.e) < 1 {
+		return nil
+	}
+
+	h, r, e := o.open()
+
+	if e != nil {
+		return e
+	}
+
+	defer func() {
+		_ = h.Close()
+	}()
+
+	r.Walk(func(i fs.FileInfo, c io.ReadCloser, p string, t string) bool {
+		if o.d[p] {
+			if c != nil {
+				_ = c.Close()
+			}
+			return true
+		} else if c == nil && i.Mode().IsRegular() && i.Size() > 0 {
+			e = fs.ErrInvalid
+			return false
+		}
+
+		e = w.Add(i, c, p, t)
+		return e == nil
+	})
+
+	// a truncated archive stops the walk without error from the callback
+	if x, k := r.(arctps.WalkError); k && e == nil {
+		e = x.WalkErr()
+	}
+
+	return e
+}
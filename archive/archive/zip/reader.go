@@ -36,6 +36,7 @@ import (
 type rdr struct {
 	r io.ReadCloser
 	z *zip.Reader
+	e error // error of the last walk
 }
 
 func (o *rdr) Close() error {
@@ -83,10 +84,21 @@ func (o *rdr) Has(s string) bool {
 }
 
 func (o *rdr) Walk(fct arctps.FuncExtract) {
+	o.e = nil
+
 	for _, f := range o.z.File {
-		r, _ := f.Open()
+		r, e := f.Open()
+		if e != nil {
+			o.e = e
+			return
+		}
+
 		if !fct(f.FileInfo(), r, f.Name, "") {
 			return
 		}
 	}
 }
+
+func (o *rdr) WalkErr() error {
+	return o.e
+}
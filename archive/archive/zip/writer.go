@@ -31,6 +31,7 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 
 	arctps "github.com/nabbar/golib/archive/archive/types"
 )
@@ -52,14 +53,17 @@ func (o *wrt) Close() error {
 	return nil
 }
 
-func (o *wrt) Add(i fs.FileInfo, r io.ReadCloser, forcePath, notUse string) error {
+func (o *wrt) Add(i fs.FileInfo, r io.ReadCloser, forcePath, target string) error {
 	var (
 		e error
 		h *zip.FileHeader
 		w io.Writer
 	)
 
-	if r == nil {
+	if r == nil && len(target) > 0 && i.Mode()&os.ModeSymlink != 0 {
+		// zip archive store the target of symlink as content
+		r = io.NopCloser(strings.NewReader(target))
+	} else if r == nil {
 		return nil
 	}
 
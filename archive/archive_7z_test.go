@@ -0,0 +1,142 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2020 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package archive_test
+
+import (
+	"io"
+	"io/fs"
+	"os"
+
+	arcarc "github.com/nabbar/golib/archive/archive"
+	arctps "github.com/nabbar/golib/archive/archive/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// sevenZipSample is a 7z archive built with bsdtar holding "a.txt" and "d/b.txt".
+var sevenZipSample = []byte{
+	0x37, 0x7a, 0xbc, 0xaf, 0x27, 0x1c, 0x00, 0x03, 0x84, 0xb5, 0xaf, 0xfa,
+	0x90, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x21, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0xe0, 0x98, 0x2f, 0xf1, 0x00, 0x34, 0x19, 0x49,
+	0xee, 0x8d, 0xe9, 0x06, 0x12, 0xec, 0x82, 0x86, 0xda, 0xdc, 0x1a, 0x29,
+	0xe2, 0x3a, 0xee, 0x19, 0x28, 0x83, 0xe1, 0x75, 0x75, 0x53, 0xfa, 0xb7,
+	0x75, 0x6a, 0xbf, 0xfe, 0x8d, 0x84, 0x00, 0x00, 0x00, 0x81, 0x33, 0x07,
+	0xae, 0x0f, 0xd0, 0x3c, 0x16, 0xfc, 0x9f, 0x3f, 0x47, 0x41, 0x62, 0xb2,
+	0x15, 0xbb, 0x3d, 0xde, 0x8d, 0xed, 0xf7, 0x85, 0x8f, 0x2d, 0x50, 0x43,
+	0x21, 0xd7, 0xab, 0x5d, 0x36, 0xe0, 0x89, 0x05, 0x92, 0x95, 0x7b, 0x0e,
+	0x1d, 0xd4, 0x91, 0x9c, 0x05, 0x1e, 0x75, 0x78, 0xd4, 0x0a, 0x2c, 0x31,
+	0x67, 0x33, 0x81, 0x42, 0x44, 0x1f, 0x39, 0x42, 0x48, 0x38, 0x8d, 0x3e,
+	0x36, 0xb8, 0x8a, 0x29, 0x63, 0x3d, 0x49, 0xca, 0x05, 0x02, 0xd3, 0x5e,
+	0x99, 0xca, 0x0f, 0x32, 0x16, 0xb3, 0xd5, 0x5f, 0x50, 0xd7, 0xac, 0xe4,
+	0x52, 0x57, 0xbb, 0x24, 0x1a, 0x63, 0x51, 0x8f, 0x09, 0x29, 0x75, 0x24,
+	0xfc, 0x04, 0x7f, 0xff, 0xfd, 0xbb, 0xf0, 0x00, 0x17, 0x06, 0x23, 0x01,
+	0x09, 0x6d, 0x00, 0x07, 0x0b, 0x01, 0x00, 0x01, 0x23, 0x03, 0x01, 0x01,
+	0x05, 0x5d, 0x00, 0x00, 0x80, 0x00, 0x0c, 0x80, 0x96, 0x0a, 0x01, 0xdb,
+	0x54, 0x0d, 0xf5, 0x00, 0x00,
+}
+
+var sevenZipContent = map[string]string{
+	"a.txt":   "hello 7z\n",
+	"d/b.txt": "lorem ipsum dolor\n",
+}
+
+var _ = Describe("archive/archive/sevenzip", func() {
+	Context("Read a 7z archive file", func() {
+		It("Writing a 7z archive must fail", func() {
+			_, err = arcarc.SevenZip.Writer(nopWriteCloser{io.Discard})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Detect and Extract a 7z archive must succeed", func() {
+			var (
+				hdf *os.File
+				alg arcarc.Algorithm
+				rdr arctps.Reader
+				fnd []string
+			)
+
+			defer func() {
+				if hdf != nil {
+					_ = hdf.Close()
+				}
+			}()
+
+			arc[arcarc.SevenZip.String()] = "sample" + arcarc.SevenZip.Extension()
+
+			err = os.WriteFile(arc[arcarc.SevenZip.String()], sevenZipSample, 0644)
+			Expect(err).ToNot(HaveOccurred())
+
+			hdf, err = os.Open(arc[arcarc.SevenZip.String()])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hdf).ToNot(BeNil())
+
+			alg, rdr, _, err = arcarc.Detect(hdf)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rdr).ToNot(BeNil())
+			Expect(alg).To(Equal(arcarc.SevenZip))
+
+			fnd, err = rdr.List()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fnd).To(ConsistOf("a.txt", "d/b.txt"))
+
+			for f, c := range sevenZipContent {
+				var (
+					i fs.FileInfo
+					r io.ReadCloser
+					b []byte
+				)
+
+				Expect(rdr.Has(f)).To(BeTrue())
+
+				i, err = rdr.Info(f)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(i.Size()).To(BeEquivalentTo(len(c)))
+
+				r, err = rdr.Get(f)
+				Expect(err).ToNot(HaveOccurred())
+
+				b, err = io.ReadAll(r)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(b)).To(Equal(c))
+
+				err = r.Close()
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			n := 0
+			rdr.Walk(func(i fs.FileInfo, r io.ReadCloser, f, t string) bool {
+				_, ok := sevenZipContent[f]
+				Expect(ok).To(BeTrue())
+				n++
+				return true
+			})
+			Expect(n).To(Equal(len(sevenZipContent)))
+
+			err = rdr.Close()
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+})
@@ -91,11 +91,29 @@ var _ = Describe("archive/archive/algorithm", func() {
 		It("xz must succeed", func() {
 			testingCompress(arccmp.XZ, "xz", ".xz")
 		})
+		It("zstd must succeed", func() {
+			testingCompress(arccmp.Zstd, "zstd", ".zst")
+		})
+		It("brotli must succeed", func() {
+			testingCompress(arccmp.Brotli, "brotli", ".br")
+		})
+		It("snappy must succeed", func() {
+			testingCompress(arccmp.Snappy, "snappy", ".sz")
+		})
 		It("tar must succeed", func() {
 			testingArchive(arcarc.Tar, "tar", ".tar")
 		})
 		It("zip must succeed", func() {
 			testingArchive(arcarc.Zip, "zip", ".zip")
 		})
+		It("cpio must succeed", func() {
+			testingArchive(arcarc.Cpio, "cpio", ".cpio")
+		})
+		It("ar must succeed", func() {
+			testingArchive(arcarc.Ar, "ar", ".ar")
+		})
+		It("7z must succeed", func() {
+			testingArchive(arcarc.SevenZip, "7z", ".7z")
+		})
 	})
 })
@@ -0,0 +1,216 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2024 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package archive_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	libarc "github.com/nabbar/golib/archive"
+	arccmp "github.com/nabbar/golib/archive/compress"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type tarEntry struct {
+	name string
+	link string
+	flag byte
+	data []byte
+}
+
+func buildTar(alg arccmp.Algorithm, lst ...tarEntry) io.ReadCloser {
+	var (
+		buf = bytes.NewBuffer(make([]byte, 0))
+		cmp io.WriteCloser
+		wrt *tar.Writer
+	)
+
+	cmp, err = alg.Writer(nopWriteCloser{Writer: buf})
+	Expect(err).ToNot(HaveOccurred())
+
+	wrt = tar.NewWriter(cmp)
+
+	for _, e := range lst {
+		err = wrt.WriteHeader(&tar.Header{
+			Typeflag: e.flag,
+			Name:     e.name,
+			Linkname: e.link,
+			Size:     int64(len(e.data)),
+			Mode:     0600,
+			ModTime:  time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = wrt.Write(e.data)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	Expect(wrt.Close()).ToNot(HaveOccurred())
+	Expect(cmp.Close()).ToNot(HaveOccurred())
+
+	return io.NopCloser(bytes.NewReader(buf.Bytes()))
+}
+
+func extractError(r io.ReadCloser, opt libarc.ExtractOptions, target error) string {
+	var (
+		out = GinkgoT().TempDir()
+		exe *libarc.ExtractError
+	)
+
+	err = libarc.ExtractAllWithOptions(r, "test.tar", out, opt)
+
+	if target == nil {
+		Expect(err).ToNot(HaveOccurred())
+	} else {
+		Expect(err).To(HaveOccurred())
+		Expect(errors.Is(err, target)).To(BeTrue())
+		Expect(errors.As(err, &exe)).To(BeTrue())
+	}
+
+	return out
+}
+
+var _ = Describe("archive/extract with options", func() {
+	var file = tarEntry{name: "dir/file.txt", flag: tar.TypeReg, data: []byte(loremIpsum)}
+
+	Context("Extract an archive violating the extract options", func() {
+		It("Entry outside the destination must fail", func() {
+			r := buildTar(arccmp.None, tarEntry{name: "../evil.txt", flag: tar.TypeReg, data: []byte("evil")})
+			extractError(r, libarc.ExtractOptions{}, libarc.ErrPathTraversal)
+		})
+		It("Symlink outside the destination with safe policy must fail", func() {
+			r := buildTar(arccmp.None, tarEntry{name: "link", link: "../../etc/passwd", flag: tar.TypeSymlink})
+			extractError(r, libarc.ExtractOptions{SymLink: libarc.LinkSafe}, libarc.ErrLinkOutside)
+		})
+		It("Symlink with deny policy must fail", func() {
+			r := buildTar(arccmp.None, file, tarEntry{name: "link", link: "dir/file.txt", flag: tar.TypeSymlink})
+			extractError(r, libarc.ExtractOptions{SymLink: libarc.LinkDeny}, libarc.ErrLinkDenied)
+		})
+		It("Symlink with skip policy must be ignored", func() {
+			r := buildTar(arccmp.None, file, tarEntry{name: "link", link: "/etc/passwd", flag: tar.TypeSymlink})
+			out := extractError(r, libarc.ExtractOptions{SymLink: libarc.LinkSkip}, nil)
+			_, err = os.Lstat(filepath.Join(out, "link"))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+		It("Hardlink outside the destination must fail", func() {
+			r := buildTar(arccmp.None, tarEntry{name: "link", link: "../secret", flag: tar.TypeLink})
+			extractError(r, libarc.ExtractOptions{}, libarc.ErrLinkOutside)
+		})
+		It("Chained symlinks escaping the destination with safe policy must fail", func() {
+			r := buildTar(arccmp.None,
+				tarEntry{name: "p", link: ".", flag: tar.TypeSymlink},
+				tarEntry{name: "p/l", link: "..", flag: tar.TypeSymlink},
+				tarEntry{name: "p/l/evil.txt", flag: tar.TypeReg, data: []byte("evil")},
+			)
+			out := extractError(r, libarc.ExtractOptions{SymLink: libarc.LinkSafe}, libarc.ErrLinkOutside)
+			_, err = os.Lstat(filepath.Join(filepath.Dir(out), "evil.txt"))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+		It("Chained symlinks escaping the destination with allow policy must not be written through", func() {
+			r := buildTar(arccmp.None,
+				tarEntry{name: "p", link: ".", flag: tar.TypeSymlink},
+				tarEntry{name: "p/l", link: "..", flag: tar.TypeSymlink},
+				tarEntry{name: "p/l/evil.txt", flag: tar.TypeReg, data: []byte("evil")},
+			)
+			out := extractError(r, libarc.ExtractOptions{}, libarc.ErrPathTraversal)
+			_, err = os.Lstat(filepath.Join(filepath.Dir(out), "evil.txt"))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+		It("Absolute symlink with allow policy must not be written through", func() {
+			ext := GinkgoT().TempDir()
+			r := buildTar(arccmp.None,
+				tarEntry{name: "x", link: ext, flag: tar.TypeSymlink},
+				tarEntry{name: "x/passwd", flag: tar.TypeReg, data: []byte("evil")},
+			)
+			extractError(r, libarc.ExtractOptions{}, libarc.ErrPathTraversal)
+			_, err = os.Lstat(filepath.Join(ext, "passwd"))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+		It("File over an existing symlink must fail", func() {
+			ext := filepath.Join(GinkgoT().TempDir(), "passwd")
+			r := buildTar(arccmp.None,
+				tarEntry{name: "x", link: ext, flag: tar.TypeSymlink},
+				tarEntry{name: "x", flag: tar.TypeReg, data: []byte("evil")},
+			)
+			extractError(r, libarc.ExtractOptions{}, libarc.ErrLinkFollow)
+			_, err = os.Lstat(ext)
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+		It("Too many entries must fail", func() {
+			r := buildTar(arccmp.None, file, tarEntry{name: "other.txt", flag: tar.TypeReg, data: []byte("other")})
+			extractError(r, libarc.ExtractOptions{MaxFiles: 1}, libarc.ErrMaxFiles)
+		})
+		It("Too large content must fail", func() {
+			r := buildTar(arccmp.Gzip, file)
+			extractError(r, libarc.ExtractOptions{MaxSize: 16}, libarc.ErrMaxSize)
+		})
+		It("Too high compression ratio must fail", func() {
+			r := buildTar(arccmp.Gzip, tarEntry{name: "zero", flag: tar.TypeReg, data: make([]byte, 8*1024*1024)})
+			extractError(r, libarc.ExtractOptions{MaxRatio: 50}, libarc.ErrMaxRatio)
+		})
+	})
+
+	Context("Extract an archive within the extract options", func() {
+		It("Symlink inside the destination with safe policy must succeed", func() {
+			r := buildTar(arccmp.None, file, tarEntry{name: "link", link: "dir/file.txt", flag: tar.TypeSymlink})
+			out := extractError(r, libarc.ExtractOptions{SymLink: libarc.LinkSafe}, nil)
+
+			var p []byte
+			p, err = os.ReadFile(filepath.Join(out, "link"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(p)).To(Equal(loremIpsum))
+		})
+		It("Entries through a symlink inside the destination must succeed", func() {
+			r := buildTar(arccmp.None,
+				file,
+				tarEntry{name: "p", link: "dir", flag: tar.TypeSymlink},
+				tarEntry{name: "p/other.txt", flag: tar.TypeReg, data: []byte("other")},
+			)
+			out := extractError(r, libarc.ExtractOptions{SymLink: libarc.LinkSafe}, nil)
+
+			var p []byte
+			p, err = os.ReadFile(filepath.Join(out, "dir", "other.txt"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(p)).To(Equal("other"))
+		})
+		It("Override permissions and preserve time must succeed", func() {
+			r := buildTar(arccmp.Gzip, file)
+			out := extractError(r, libarc.ExtractOptions{FileMode: 0640, PreserveTime: true, MaxRatio: 50}, nil)
+
+			var i os.FileInfo
+			i, err = os.Stat(filepath.Join(out, "dir", "file.txt"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(i.Mode().Perm()).To(Equal(os.FileMode(0640)))
+			Expect(i.ModTime().UTC()).To(Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)))
+		})
+	})
+})
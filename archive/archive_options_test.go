@@ -0,0 +1,116 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2024 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package archive_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	arccmp "github.com/nabbar/golib/archive/compress"
+	libsiz "github.com/nabbar/golib/size"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (n nopWriteCloser) Close() error {
+	return nil
+}
+
+func testingOptions(alg arccmp.Algorithm, opt arccmp.Options) {
+	var (
+		buf = bytes.NewBuffer(make([]byte, 0))
+		src = strings.Repeat(loremIpsum, 20)
+		wrt io.WriteCloser
+		rdr io.ReadCloser
+		res []byte
+		fnd arccmp.Algorithm
+	)
+
+	wrt, err = alg.WriterWithOptions(nopWriteCloser{Writer: buf}, opt)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(wrt).ToNot(BeNil())
+
+	_, err = io.Copy(wrt, strings.NewReader(src))
+	Expect(err).ToNot(HaveOccurred())
+
+	err = wrt.Close()
+	Expect(err).ToNot(HaveOccurred())
+
+	fnd, rdr, err = arccmp.Detect(buf)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(fnd).To(Equal(alg))
+
+	res, err = io.ReadAll(rdr)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(string(res)).To(Equal(src))
+
+	err = rdr.Close()
+	Expect(err).ToNot(HaveOccurred())
+}
+
+var _ = Describe("archive/compress/options", func() {
+	Context("Write/Read with compression options", func() {
+		It("gzip with parallel workers must succeed", func() {
+			testingOptions(arccmp.Gzip, arccmp.Options{Level: arccmp.LevelFastest, Block: 4 * libsiz.SizeKilo, Workers: 4})
+		})
+		It("bzip2 with parallel workers must succeed", func() {
+			testingOptions(arccmp.Bzip2, arccmp.Options{Level: arccmp.LevelBest, Block: 4 * libsiz.SizeKilo, Workers: 4})
+		})
+		It("xz with parallel workers and window must succeed", func() {
+			testingOptions(arccmp.XZ, arccmp.Options{Window: libsiz.SizeMega, Block: 4 * libsiz.SizeKilo, Workers: 4})
+		})
+		It("zstd with workers and window must succeed", func() {
+			testingOptions(arccmp.Zstd, arccmp.Options{Level: arccmp.LevelBetter, Window: 64 * libsiz.SizeKilo, Workers: 2})
+		})
+		It("lz4 with block size and workers must succeed", func() {
+			testingOptions(arccmp.LZ4, arccmp.Options{Level: arccmp.LevelBest, Block: 64 * libsiz.SizeKilo, Workers: 2})
+		})
+		It("gzip with parallel workers closed twice must succeed and refuse writes", func() {
+			var buf = bytes.NewBuffer(make([]byte, 0))
+
+			wrt, e := arccmp.Gzip.WriterWithOptions(nopWriteCloser{Writer: buf}, arccmp.Options{Block: 4 * libsiz.SizeKilo, Workers: 4})
+			Expect(e).ToNot(HaveOccurred())
+
+			_, e = io.Copy(wrt, strings.NewReader(loremIpsum))
+			Expect(e).ToNot(HaveOccurred())
+			Expect(wrt.Close()).ToNot(HaveOccurred())
+			Expect(wrt.Close()).ToNot(HaveOccurred())
+
+			_, e = wrt.Write([]byte(loremIpsum))
+			Expect(e).To(MatchError(io.ErrClosedPipe))
+		})
+		It("parsing level must succeed", func() {
+			Expect(arccmp.ParseLevel("best")).To(Equal(arccmp.LevelBest))
+			Expect(arccmp.ParseLevel("fastest")).To(Equal(arccmp.LevelFastest))
+			Expect(arccmp.ParseLevel("unknown")).To(Equal(arccmp.LevelDefault))
+		})
+	})
+})
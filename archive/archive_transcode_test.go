@@ -0,0 +1,204 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2024 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package archive_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"strings"
+
+	libarc "github.com/nabbar/golib/archive"
+	arcarc "github.com/nabbar/golib/archive/archive"
+	arctps "github.com/nabbar/golib/archive/archive/types"
+	arccmp "github.com/nabbar/golib/archive/compress"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type memReadCloser struct {
+	*bytes.Reader
+}
+
+func (memReadCloser) Close() error {
+	return nil
+}
+
+func transcodeContent(rdr arctps.Reader) map[string]string {
+	var res = make(map[string]string)
+
+	rdr.Walk(func(i fs.FileInfo, r io.ReadCloser, p, t string) bool {
+		if i.Mode()&fs.ModeSymlink != 0 && len(t) > 0 {
+			res[p] = "->" + t
+		} else if b, e := io.ReadAll(r); e == nil {
+			res[p] = string(b)
+		}
+
+		if r != nil {
+			_ = r.Close()
+		}
+
+		return true
+	})
+
+	return res
+}
+
+var _ = Describe("archive/transcode", func() {
+	Context("Transcode an archive to another archive", func() {
+		It("tar to zip with filter and progress must succeed", func() {
+			var (
+				alg arcarc.Algorithm
+				src arctps.Reader
+				dst arctps.Writer
+				rdr arctps.Reader
+				buf = bytes.NewBuffer(make([]byte, 0))
+				inc int64
+				rst int
+				eof int
+			)
+
+			src, err = arcarc.Tar.Reader(buildTar(arccmp.None,
+				tarEntry{name: "a.txt", flag: tar.TypeReg, data: []byte("first file")},
+				tarEntry{name: "dir/b.txt", flag: tar.TypeReg, data: []byte("second file")},
+				tarEntry{name: "skip.txt", flag: tar.TypeReg, data: []byte("skipped file")},
+				tarEntry{name: "link", flag: tar.TypeSymlink, link: "a.txt"},
+			))
+			Expect(err).ToNot(HaveOccurred())
+
+			dst, err = arcarc.Zip.Writer(libarc.NopWriteCloser(buf))
+			Expect(err).ToNot(HaveOccurred())
+
+			err = libarc.TranscodeWithProgress(src, dst, func(i fs.FileInfo, p string) (string, bool) {
+				if p == "skip.txt" {
+					return "", false
+				}
+				return strings.TrimPrefix(p, "dir/"), true
+			}, libarc.TranscodeProgress{
+				Reset: func(size, current int64) {
+					rst++
+				},
+				Increment: func(size int64) {
+					inc += size
+				},
+				EOF: func() {
+					eof++
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dst.Close()).ToNot(HaveOccurred())
+
+			Expect(rst).To(Equal(2))
+			Expect(eof).To(Equal(2))
+			Expect(inc).To(BeEquivalentTo(len("first file") + len("second file")))
+
+			alg, rdr, _, err = arcarc.Detect(memReadCloser{bytes.NewReader(buf.Bytes())})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(alg).To(Equal(arcarc.Zip))
+			Expect(transcodeContent(rdr)).To(Equal(map[string]string{
+				"a.txt": "first file",
+				"b.txt": "second file",
+				"link":  "a.txt",
+			}))
+		})
+
+		It("zip to tar.zst must succeed", func() {
+			var (
+				alg arcarc.Algorithm
+				cmp arccmp.Algorithm
+				src arctps.Reader
+				dst arctps.Writer
+				rdr arctps.Reader
+				zcw io.WriteCloser
+				zrd io.ReadCloser
+				zip = bytes.NewBuffer(make([]byte, 0))
+				out = bytes.NewBuffer(make([]byte, 0))
+			)
+
+			dst, err = arcarc.Zip.Writer(libarc.NopWriteCloser(zip))
+			Expect(err).ToNot(HaveOccurred())
+
+			src, err = arcarc.Tar.Reader(buildTar(arccmp.None,
+				tarEntry{name: "a.txt", flag: tar.TypeReg, data: []byte("first file")},
+				tarEntry{name: "link", flag: tar.TypeSymlink, link: "a.txt"},
+			))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(libarc.Transcode(src, dst, nil)).ToNot(HaveOccurred())
+			Expect(dst.Close()).ToNot(HaveOccurred())
+
+			_, src, _, err = arcarc.Detect(memReadCloser{bytes.NewReader(zip.Bytes())})
+			Expect(err).ToNot(HaveOccurred())
+
+			zcw, err = arccmp.Zstd.Writer(libarc.NopWriteCloser(out))
+			Expect(err).ToNot(HaveOccurred())
+
+			dst, err = arcarc.Tar.Writer(zcw)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(libarc.Transcode(src, dst, nil)).ToNot(HaveOccurred())
+			Expect(dst.Close()).ToNot(HaveOccurred())
+
+			cmp, zrd, err = libarc.DetectCompression(bytes.NewReader(out.Bytes()))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cmp).To(Equal(arccmp.Zstd))
+
+			alg, rdr, _, err = arcarc.Detect(zrd)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(alg).To(Equal(arcarc.Tar))
+			Expect(transcodeContent(rdr)).To(Equal(map[string]string{
+				"a.txt": "first file",
+				"link":  "->a.txt",
+			}))
+		})
+
+		It("Transcode an entry not supported by the destination must fail with a transcode error", func() {
+			var (
+				src arctps.Reader
+				dst arctps.Writer
+				out = bytes.NewBuffer(make([]byte, 0))
+				tce *libarc.TranscodeError
+				exe *libarc.ExtractError
+			)
+
+			dst, err = arcarc.Ar.Writer(libarc.NopWriteCloser(out))
+			Expect(err).ToNot(HaveOccurred())
+
+			src, err = arcarc.Tar.Reader(buildTar(arccmp.None,
+				tarEntry{name: "a.txt", flag: tar.TypeReg, data: []byte("first file")},
+				tarEntry{name: "link", flag: tar.TypeSymlink, link: "a.txt"},
+			))
+			Expect(err).ToNot(HaveOccurred())
+
+			err = libarc.Transcode(src, dst, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(errors.As(err, &tce)).To(BeTrue())
+			Expect(tce.Path).To(Equal("link"))
+			Expect(errors.Is(err, fs.ErrInvalid)).To(BeTrue())
+			Expect(errors.As(err, &exe)).To(BeFalse())
+		})
+	})
+})
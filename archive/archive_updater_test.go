@@ -0,0 +1,201 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2024 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package archive_test
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+
+	arcarc "github.com/nabbar/golib/archive/archive"
+	arctps "github.com/nabbar/golib/archive/archive/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func updaterContent(path, name string) string {
+	hdf, e := os.Open(path)
+	Expect(e).ToNot(HaveOccurred())
+
+	defer func() {
+		_ = hdf.Close()
+	}()
+
+	_, rdr, _, e := arcarc.Detect(hdf)
+	Expect(e).ToNot(HaveOccurred())
+
+	r, e := rdr.Get(name)
+	Expect(e).ToNot(HaveOccurred())
+
+	b, e := io.ReadAll(r)
+	Expect(e).ToNot(HaveOccurred())
+	_ = r.Close()
+
+	return string(b)
+}
+
+func updaterList(path string) []string {
+	hdf, e := os.Open(path)
+	Expect(e).ToNot(HaveOccurred())
+
+	defer func() {
+		_ = hdf.Close()
+	}()
+
+	_, rdr, _, e := arcarc.Detect(hdf)
+	Expect(e).ToNot(HaveOccurred())
+
+	l, e := rdr.List()
+	Expect(e).ToNot(HaveOccurred())
+	sort.Strings(l)
+
+	return l
+}
+
+func updaterAdd(upd arctps.Updater, name, content string, replace bool) error {
+	var (
+		e error
+		i fs.FileInfo
+		h *os.File
+	)
+
+	arc["updater_"+name] = "updater_" + name
+
+	e = os.WriteFile(arc["updater_"+name], []byte(content), 0644)
+	Expect(e).ToNot(HaveOccurred())
+
+	i, e = os.Stat(arc["updater_"+name])
+	Expect(e).ToNot(HaveOccurred())
+
+	h, e = os.Open(arc["updater_"+name])
+	Expect(e).ToNot(HaveOccurred())
+
+	if replace {
+		return upd.Replace(i, h, name, "")
+	}
+
+	return upd.Add(i, h, name, "")
+}
+
+func testingUpdater(alg arcarc.Algorithm) {
+	var (
+		upd arctps.Updater
+		fnd arcarc.Algorithm
+		pth = "updater" + alg.Extension()
+	)
+
+	arc["updater"+alg.String()] = pth
+	_ = os.Remove(pth)
+
+	// create a new archive
+	upd, err = alg.Updater(pth)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(updaterAdd(upd, "a.txt", "first file", false)).ToNot(HaveOccurred())
+	Expect(updaterAdd(upd, "b.txt", "second file", false)).ToNot(HaveOccurred())
+	Expect(upd.Close()).ToNot(HaveOccurred())
+	Expect(updaterList(pth)).To(Equal([]string{"a.txt", "b.txt"}))
+
+	// append, replace and delete into the existing archive
+	fnd, upd, err = arcarc.NewUpdater(pth)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(fnd).To(Equal(alg))
+	Expect(upd.Has("a.txt")).To(BeTrue())
+
+	Expect(updaterAdd(upd, "a.txt", "duplicate", false)).To(MatchError(fs.ErrExist))
+	Expect(updaterAdd(upd, "c.txt", "third file", false)).ToNot(HaveOccurred())
+	Expect(updaterAdd(upd, "b.txt", "second file replaced", true)).ToNot(HaveOccurred())
+	Expect(upd.Delete("a.txt")).ToNot(HaveOccurred())
+	Expect(upd.Delete("z.txt")).To(MatchError(fs.ErrNotExist))
+	Expect(upd.Has("a.txt")).To(BeFalse())
+	Expect(upd.List()).To(ConsistOf("b.txt", "c.txt"))
+
+	// the original archive must not change before the updater is closed
+	Expect(updaterList(pth)).To(Equal([]string{"a.txt", "b.txt"}))
+	Expect(upd.Close()).ToNot(HaveOccurred())
+
+	Expect(updaterList(pth)).To(Equal([]string{"b.txt", "c.txt"}))
+	Expect(updaterContent(pth, "b.txt")).To(Equal("second file replaced"))
+	Expect(updaterContent(pth, "c.txt")).To(Equal("third file"))
+
+	// aborted changes must not be applied
+	_, upd, err = arcarc.NewUpdater(pth)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(upd.Delete("b.txt")).ToNot(HaveOccurred())
+	Expect(upd.Abort()).ToNot(HaveOccurred())
+	Expect(upd.Close()).To(MatchError(fs.ErrClosed))
+	Expect(updaterList(pth)).To(Equal([]string{"b.txt", "c.txt"}))
+}
+
+var _ = Describe("archive/archive/updater", func() {
+	Context("Update an existing archive", func() {
+		It("tar archive must succeed", func() {
+			testingUpdater(arcarc.Tar)
+		})
+		It("zip archive must succeed", func() {
+			testingUpdater(arcarc.Zip)
+		})
+		It("cpio archive must succeed", func() {
+			testingUpdater(arcarc.Cpio)
+		})
+		It("ar archive must succeed", func() {
+			testingUpdater(arcarc.Ar)
+		})
+		It("truncated ar archive must fail and stay unchanged", func() {
+			var (
+				upd arctps.Updater
+				buf []byte
+				pth = "updater_truncated" + arcarc.Ar.Extension()
+			)
+
+			arc["updater_truncated"] = pth
+			_ = os.Remove(pth)
+
+			upd, err = arcarc.Ar.Updater(pth)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updaterAdd(upd, "a.txt", "first file", false)).ToNot(HaveOccurred())
+			Expect(updaterAdd(upd, "b.txt", "second file", false)).ToNot(HaveOccurred())
+			Expect(upd.Close()).ToNot(HaveOccurred())
+
+			_, upd, err = arcarc.NewUpdater(pth)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updaterAdd(upd, "c.txt", "third file", false)).ToNot(HaveOccurred())
+
+			// the archive is truncated into the header of its last entry before the changes are applied
+			buf, err = os.ReadFile(pth)
+			Expect(err).ToNot(HaveOccurred())
+			buf = buf[:len(buf)-40]
+			Expect(os.WriteFile(pth, buf, 0644)).ToNot(HaveOccurred())
+
+			Expect(upd.Close()).To(MatchError(io.ErrUnexpectedEOF))
+			Expect(os.ReadFile(pth)).To(Equal(buf))
+		})
+		It("7z archive must fail", func() {
+			_, err = arcarc.SevenZip.Updater("updater.7z")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
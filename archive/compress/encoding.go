@@ -51,6 +51,12 @@ func (a *Algorithm) UnmarshalText(b []byte) error {
 		*a = LZ4
 	case strings.EqualFold(s, XZ.String()):
 		*a = XZ
+	case strings.EqualFold(s, Zstd.String()), strings.EqualFold(s, "zst"):
+		*a = Zstd
+	case strings.EqualFold(s, Brotli.String()), strings.EqualFold(s, "br"):
+		*a = Brotli
+	case strings.EqualFold(s, Snappy.String()), strings.EqualFold(s, "sz"):
+		*a = Snappy
 	default:
 		*a = None
 	}
@@ -62,6 +62,10 @@ func Detect(r io.Reader) (Algorithm, io.ReadCloser, error) {
 		alg = LZ4
 	case XZ.DetectHeader(buf): // xz
 		alg = XZ
+	case Zstd.DetectHeader(buf): // zstd
+		alg = Zstd
+	case Snappy.DetectHeader(buf): // snappy framed
+		alg = Snappy
 	default:
 		alg = None
 	}
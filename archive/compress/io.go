@@ -29,8 +29,13 @@ import (
 	"compress/bzip2"
 	"compress/gzip"
 	"io"
+	"math/bits"
 
+	"github.com/andybalholm/brotli"
 	bz2 "github.com/dsnet/compress/bzip2"
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	libsiz "github.com/nabbar/golib/size"
 	"github.com/pierrec/lz4/v4"
 	"github.com/ulikunitz/xz"
 )
@@ -46,22 +51,197 @@ func (a Algorithm) Reader(r io.Reader) (io.ReadCloser, error) {
 	case XZ:
 		c, e := xz.NewReader(r)
 		return io.NopCloser(c), e
+	case Zstd:
+		if c, e := zstd.NewReader(r); e != nil {
+			return nil, e
+		} else {
+			return c.IOReadCloser(), nil
+		}
+	case Brotli:
+		return io.NopCloser(brotli.NewReader(r)), nil
+	case Snappy:
+		return io.NopCloser(snappy.NewReader(r)), nil
 	default:
 		return io.NopCloser(r), nil
 	}
 }
 
 func (a Algorithm) Writer(w io.WriteCloser) (io.WriteCloser, error) {
+	return a.WriterLevel(w, LevelDefault)
+}
+
+// WriterLevel returns a compression writer using the given compression level.
+// The level is ignored by algorithms without level setting, like Snappy.
+func (a Algorithm) WriterLevel(w io.WriteCloser, lvl Level) (io.WriteCloser, error) {
+	return a.WriterWithOptions(w, Options{Level: lvl})
+}
+
+// WriterWithOptions returns a compression writer using the given options.
+func (a Algorithm) WriterWithOptions(w io.WriteCloser, opt Options) (io.WriteCloser, error) {
+	switch a {
+	case Bzip2, Gzip, XZ:
+		if opt.Workers > 1 {
+			return newParallel(w, func(o io.Writer) (io.WriteCloser, error) {
+				return a.writerMember(o, opt)
+			}, opt.Workers, opt.blockSize()), nil
+		}
+		return a.writerMember(w, opt)
+	case LZ4:
+		c := lz4.NewWriter(w)
+		o := []lz4.Option{lz4.CompressionLevelOption(levelLZ4(opt.Level))}
+		if opt.Block > 0 {
+			o = append(o, lz4.BlockSizeOption(blockLZ4(opt.Block)))
+		}
+		if opt.Workers > 1 {
+			o = append(o, lz4.ConcurrencyOption(opt.Workers))
+		}
+		return c, c.Apply(o...)
+	case Zstd:
+		o := []zstd.EOption{zstd.WithEncoderLevel(levelZstd(opt.Level))}
+		if opt.Window > 0 {
+			o = append(o, zstd.WithWindowSize(windowZstd(opt.Window)))
+		}
+		if opt.Workers > 0 {
+			o = append(o, zstd.WithEncoderConcurrency(opt.Workers))
+		}
+		return zstd.NewWriter(w, o...)
+	case Brotli:
+		return brotli.NewWriterOptions(w, brotli.WriterOptions{
+			Quality: levelBrotli(opt.Level),
+			LGWin:   windowBrotli(opt.Window),
+		}), nil
+	case Snappy:
+		return snappy.NewBufferedWriter(w), nil
+	default:
+		return w, nil
+	}
+}
+
+func (a Algorithm) writerMember(w io.Writer, opt Options) (io.WriteCloser, error) {
 	switch a {
 	case Bzip2:
-		return bz2.NewWriter(w, nil)
+		return bz2.NewWriter(w, &bz2.WriterConfig{Level: levelBzip2(opt.Level)})
 	case Gzip:
-		return gzip.NewWriter(w), nil
-	case LZ4:
-		return lz4.NewWriter(w), nil
+		return gzip.NewWriterLevel(w, levelGzip(opt.Level))
 	case XZ:
-		return xz.NewWriter(w)
+		if opt.Window > 0 {
+			return xz.WriterConfig{DictCap: int(opt.Window)}.NewWriter(w)
+		}
+		return xz.WriterConfig{DictCap: levelXZ(opt.Level)}.NewWriter(w)
 	default:
-		return w, nil
+		return nil, ErrInvalidAlgorithm
+	}
+}
+
+func blockLZ4(s libsiz.Size) lz4.BlockSize {
+	switch {
+	case s <= 64*libsiz.SizeKilo:
+		return lz4.Block64Kb
+	case s <= 256*libsiz.SizeKilo:
+		return lz4.Block256Kb
+	case s <= libsiz.SizeMega:
+		return lz4.Block1Mb
+	default:
+		return lz4.Block4Mb
+	}
+}
+
+// windowZstd returns the nearest power of two in the range allowed by zstd.
+func windowZstd(s libsiz.Size) int {
+	if s < zstd.MinWindowSize {
+		return zstd.MinWindowSize
+	} else if s > zstd.MaxWindowSize {
+		return zstd.MaxWindowSize
+	}
+
+	return 1 << (bits.Len64(uint64(s) - 1))
+}
+
+// windowBrotli returns the base 2 logarithm of the window size in the range allowed by brotli, or 0 for default.
+func windowBrotli(s libsiz.Size) int {
+	if s == 0 {
+		return 0
+	} else if l := bits.Len64(uint64(s) - 1); l < 10 {
+		return 10
+	} else if l > 24 {
+		return 24
+	} else {
+		return l
+	}
+}
+
+func levelBzip2(l Level) int {
+	switch l {
+	case LevelFastest:
+		return bz2.BestSpeed
+	case LevelBetter:
+		return 7
+	case LevelBest:
+		return bz2.BestCompression
+	default:
+		return bz2.DefaultCompression
+	}
+}
+
+func levelGzip(l Level) int {
+	switch l {
+	case LevelFastest:
+		return gzip.BestSpeed
+	case LevelBetter:
+		return 7
+	case LevelBest:
+		return gzip.BestCompression
+	default:
+		return gzip.DefaultCompression
+	}
+}
+
+func levelLZ4(l Level) lz4.CompressionLevel {
+	switch l {
+	case LevelBetter:
+		return lz4.Level5
+	case LevelBest:
+		return lz4.Level9
+	default:
+		return lz4.Fast
+	}
+}
+
+func levelXZ(l Level) int {
+	switch l {
+	case LevelFastest:
+		return 1 << 20
+	case LevelBetter:
+		return 1 << 25
+	case LevelBest:
+		return 1 << 26
+	default:
+		return 1 << 23
+	}
+}
+
+func levelZstd(l Level) zstd.EncoderLevel {
+	switch l {
+	case LevelFastest:
+		return zstd.SpeedFastest
+	case LevelBetter:
+		return zstd.SpeedBetterCompression
+	case LevelBest:
+		return zstd.SpeedBestCompression
+	default:
+		return zstd.SpeedDefault
+	}
+}
+
+func levelBrotli(l Level) int {
+	switch l {
+	case LevelFastest:
+		return brotli.BestSpeed
+	case LevelBetter:
+		return 9
+	case LevelBest:
+		return brotli.BestCompression
+	default:
+		return brotli.DefaultCompression
 	}
 }
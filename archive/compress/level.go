@@ -0,0 +1,85 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2024 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package compress
+
+import (
+	"strings"
+)
+
+// Level is a compression level, mapped to the nearest setting of each algorithm.
+type Level uint8
+
+const (
+	LevelDefault Level = iota
+	LevelFastest
+	LevelBetter
+	LevelBest
+)
+
+func ParseLevel(s string) Level {
+	var lvl = LevelDefault
+	if e := lvl.UnmarshalText([]byte(s)); e != nil {
+		return LevelDefault
+	} else {
+		return lvl
+	}
+}
+
+func (l Level) String() string {
+	switch l {
+	case LevelFastest:
+		return "fastest"
+	case LevelBetter:
+		return "better"
+	case LevelBest:
+		return "best"
+	default:
+		return "default"
+	}
+}
+
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+func (l *Level) UnmarshalText(b []byte) error {
+	s := strings.Trim(string(b), "\"")
+	s = strings.Trim(s, "'")
+	s = strings.TrimSpace(s)
+
+	switch {
+	case strings.EqualFold(s, LevelFastest.String()), strings.EqualFold(s, "fast"), strings.EqualFold(s, "speed"):
+		*l = LevelFastest
+	case strings.EqualFold(s, LevelBetter.String()):
+		*l = LevelBetter
+	case strings.EqualFold(s, LevelBest.String()), strings.EqualFold(s, "max"):
+		*l = LevelBest
+	default:
+		*l = LevelDefault
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2024 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package compress
+
+import (
+	"reflect"
+
+	libmap "github.com/mitchellh/mapstructure"
+	libsiz "github.com/nabbar/golib/size"
+)
+
+// Options define the settings of a compression writer.
+// Settings not supported by an algorithm are ignored.
+type Options struct {
+	// Level define the compression level : default, fastest, better or best.
+	Level Level `json:"level,omitempty" yaml:"level,omitempty" toml:"level,omitempty" mapstructure:"level,omitempty"`
+
+	// Window define the window or dictionary size used by xz, zstd and brotli.
+	Window libsiz.Size `json:"window,omitempty" yaml:"window,omitempty" toml:"window,omitempty" mapstructure:"window,omitempty"`
+
+	// Block define the size of block compressed independently, used by lz4 and by parallel writers.
+	Block libsiz.Size `json:"block,omitempty" yaml:"block,omitempty" toml:"block,omitempty" mapstructure:"block,omitempty"`
+
+	// Workers define the number of goroutines compressing in parallel. Zero or one means no concurrency.
+	// Gzip, bzip2 and xz are compressed in parallel as a stream of concatenated members, one per block.
+	Workers int `json:"workers,omitempty" yaml:"workers,omitempty" toml:"workers,omitempty" mapstructure:"workers,omitempty"`
+}
+
+const defaultBlockParallel = 4 * libsiz.SizeMega
+
+func (o Options) blockSize() int {
+	if o.Block > 0 {
+		return int(o.Block)
+	}
+
+	return int(defaultBlockParallel)
+}
+
+// ViperDecoderHook allow to decode Algorithm and Level from string in configuration.
+func ViperDecoderHook() libmap.DecodeHookFuncType {
+	return func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+		var (
+			a = None
+			l = LevelDefault
+			t string
+			k bool
+		)
+
+		// Check if the data type matches the expected one
+		if from.Kind() != reflect.String {
+			return data, nil
+		} else if t, k = data.(string); !k {
+			return data, nil
+		}
+
+		// Check if the target type matches the expected one and parse the data
+		switch to {
+		case reflect.TypeOf(a):
+			if e := a.UnmarshalText([]byte(t)); e != nil {
+				return nil, e
+			}
+			return a, nil
+		case reflect.TypeOf(l):
+			if e := l.UnmarshalText([]byte(t)); e != nil {
+				return nil, e
+			}
+			return l, nil
+		default:
+			return data, nil
+		}
+	}
+}
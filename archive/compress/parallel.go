@@ -0,0 +1,178 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2024 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package compress
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+type member func(w io.Writer) (io.WriteCloser, error)
+
+type block struct {
+	i []byte
+	o bytes.Buffer
+	e error
+	d chan struct{}
+}
+
+// parallel compresses each block as an independent member of a multi-member stream.
+// Members are written in order, so the result is readable by any reader supporting
+// concatenated streams, like gzip, bzip2 and xz readers.
+type parallel struct {
+	m sync.Mutex
+	w io.Writer
+	f member
+	n int
+	b []byte
+	c int // count of dispatched blocks
+	q chan *block
+	s chan struct{}
+	d chan struct{}
+	e error
+	x bool // closed
+}
+
+func newParallel(w io.Writer, f member, workers, size int) io.WriteCloser {
+	p := &parallel{
+		w: w,
+		f: f,
+		n: size,
+		b: make([]byte, 0, size),
+		q: make(chan *block, workers),
+		s: make(chan struct{}, workers),
+		d: make(chan struct{}),
+	}
+
+	go p.flush()
+
+	return p
+}
+
+func (p *parallel) flush() {
+	defer close(p.d)
+
+	for b := range p.q {
+		<-b.d
+
+		if p.err() != nil {
+			continue
+		} else if b.e != nil {
+			p.setErr(b.e)
+		} else if _, e := p.w.Write(b.o.Bytes()); e != nil {
+			p.setErr(e)
+		}
+	}
+}
+
+func (p *parallel) err() error {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.e
+}
+
+func (p *parallel) setErr(e error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if p.e == nil {
+		p.e = e
+	}
+}
+
+func (p *parallel) dispatch() {
+	b := &block{
+		i: p.b,
+		d: make(chan struct{}),
+	}
+
+	p.b = make([]byte, 0, p.n)
+	p.c++
+	p.s <- struct{}{}
+	p.q <- b
+
+	go func() {
+		defer func() {
+			<-p.s
+			close(b.d)
+		}()
+
+		if w, e := p.f(&b.o); e != nil {
+			b.e = e
+		} else if _, e = w.Write(b.i); e != nil {
+			b.e = e
+		} else if e = w.Close(); e != nil {
+			b.e = e
+		}
+	}()
+}
+
+func (p *parallel) Write(d []byte) (int, error) {
+	var n int
+
+	if p.x {
+		return 0, io.ErrClosedPipe
+	}
+
+	for len(d) > 0 {
+		if e := p.err(); e != nil {
+			return n, e
+		}
+
+		l := p.n - len(p.b)
+		if l > len(d) {
+			l = len(d)
+		}
+
+		p.b = append(p.b, d[:l]...)
+		d = d[l:]
+		n += l
+
+		if len(p.b) >= p.n {
+			p.dispatch()
+		}
+	}
+
+	return n, nil
+}
+
+func (p *parallel) Close() error {
+	if p.x {
+		return p.err()
+	}
+
+	p.x = true
+
+	if len(p.b) > 0 || p.c == 0 {
+		p.dispatch()
+	}
+
+	close(p.q)
+	<-p.d
+
+	return p.err()
+}
@@ -35,6 +35,9 @@ const (
 	Gzip
 	LZ4
 	XZ
+	Zstd
+	Brotli
+	Snappy
 )
 
 func (a Algorithm) IsNone() bool {
@@ -51,6 +54,12 @@ func (a Algorithm) String() string {
 		return "lz4"
 	case XZ:
 		return "xz"
+	case Zstd:
+		return "zstd"
+	case Brotli:
+		return "brotli"
+	case Snappy:
+		return "snappy"
 	default:
 		return "none"
 	}
@@ -66,6 +75,12 @@ func (a Algorithm) Extension() string {
 		return ".lz4"
 	case XZ:
 		return ".xz"
+	case Zstd:
+		return ".zst"
+	case Brotli:
+		return ".br"
+	case Snappy:
+		return ".sz"
 	default:
 		return ""
 	}
@@ -90,6 +105,16 @@ func (a Algorithm) DetectHeader(h []byte) bool {
 		exp := []byte{0xFD, 0x37, 0x7A, 0x58, 0x5A, 0x00}
 		alt := []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
 		return bytes.Equal(h[0:6], exp) || bytes.Equal(h[0:6], alt)
+	case Zstd:
+		exp := []byte{0x28, 0xB5, 0x2F, 0xFD}
+		return bytes.Equal(h[0:4], exp)
+	case Snappy:
+		// stream identifier chunk of the framed format : 0xFF 0x06 0x00 0x00 "sNaPpY"
+		exp := []byte{0xFF, 0x06, 0x00, 0x00, 's', 'N'}
+		return bytes.Equal(h[0:6], exp)
+	case Brotli:
+		// brotli stream has no magic number, so it must be explicitly selected
+		return false
 	default:
 		return false
 	}
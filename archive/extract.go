@@ -26,18 +26,75 @@
 package archive
 
 import (
+	"archive/tar"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	arcarc "github.com/nabbar/golib/archive/archive"
 	arctps "github.com/nabbar/golib/archive/archive/types"
 	arccmp "github.com/nabbar/golib/archive/compress"
 )
 
+type extract struct {
+	o ExtractOptions
+	d string        // destination
+	r string        // destination with symlinks resolved
+	i atomic.Int64  // read bytes
+	w atomic.Int64  // written bytes
+	n atomic.Uint64 // entries count
+	t []dirTime     // time of directories applied at the end
+}
+
+type dirTime struct {
+	p string
+	t time.Time
+}
+
 func ExtractAll(r io.ReadCloser, archiveName, destination string) error {
+	return ExtractAllWithOptions(r, archiveName, destination, ExtractOptions{})
+}
+
+// ExtractAllWithOptions extracts all entries of the given archive into the destination
+// and stops at the first entry violating the given options with an *ExtractError.
+func ExtractAllWithOptions(r io.ReadCloser, archiveName, destination string, opt ExtractOptions) error {
+	if r == nil {
+		return fs.ErrInvalid
+	}
+
+	x := &extract{
+		o: opt,
+		d: filepath.Clean(destination),
+	}
+
+	if p, e := filepath.Abs(x.d); e != nil {
+		return e
+	} else if x.r, e = resolve(p); e != nil {
+		return e
+	}
+
+	if opt.MaxRatio > 0 {
+		r = &counter{r: r, n: &x.i}
+	}
+
+	if e := x.extract(r, archiveName); e != nil {
+		return e
+	}
+
+	if opt.PreserveTime {
+		for i := len(x.t) - 1; i >= 0; i-- {
+			_ = os.Chtimes(x.t[i].p, x.t[i].t, x.t[i].t)
+		}
+	}
+
+	return nil
+}
+
+func (x *extract) extract(r io.ReadCloser, archiveName string) error {
 	var (
 		e error
 		n string
@@ -45,10 +102,6 @@ func ExtractAll(r io.ReadCloser, archiveName, destination string) error {
 		o io.ReadCloser
 	)
 
-	if r == nil {
-		return fs.ErrInvalid
-	}
-
 	for e == nil {
 		a, o, e = DetectCompression(r)
 
@@ -57,7 +110,7 @@ func ExtractAll(r io.ReadCloser, archiveName, destination string) error {
 		}
 
 		n = strings.TrimSuffix(filepath.Base(archiveName), a.Extension())
-		return ExtractAll(o, n, destination)
+		return x.extract(o, n)
 	}
 
 	var (
@@ -68,7 +121,7 @@ func ExtractAll(r io.ReadCloser, archiveName, destination string) error {
 	if b, z, r, e = DetectArchive(o); e != nil {
 		return e
 	} else if b.IsNone() {
-		return writeFile(archiveName, destination, r, nil)
+		return x.writeFile(filepath.Base(archiveName), r, nil)
 	} else if z == nil {
 		return fs.ErrInvalid
 	} else {
@@ -81,43 +134,211 @@ func ExtractAll(r io.ReadCloser, archiveName, destination string) error {
 				}
 			}()
 
-			if info.IsDir() {
-				if e = createPath(filepath.Join(destination, cleanPath(dst)), info.Mode()); e != nil {
-					err = e
-					return false
-				}
-			} else if info.Mode()&os.ModeSymlink != 0 {
-				if e = writeSymLink(true, dst, target, destination); e != nil {
-					err = e
-					return false
-				}
-			} else if info.Mode()&os.ModeDevice != 0 {
-				if e = writeSymLink(false, dst, target, destination); e != nil {
-					err = e
-					return false
-				}
-			} else if info.Mode().IsRegular() {
-				if e = writeFile(dst, destination, closer, info); e != nil {
-					err = e
-					return false
-				}
+			if err = x.entry(info, closer, dst, target); err != nil {
+				return false
 			}
 
 			// prevent file cursor not at EOF of current file for TAPE Archive
-			_, _ = io.Copy(io.Discard, closer)
+			if closer != nil {
+				_, _ = io.Copy(io.Discard, closer)
+			}
+
 			return true
 		})
 
+		if w, k := z.(arctps.WalkError); k && err == nil {
+			err = w.WalkErr()
+		}
+
 		return err
 	}
 }
 
-func cleanPath(path string) string {
-	for strings.Contains(path, ".."+string(filepath.Separator)) {
-		path = filepath.Clean(path)
+func (x *extract) entry(info fs.FileInfo, r io.ReadCloser, name, target string) error {
+	if m := x.o.MaxFiles; m > 0 && x.n.Add(1) > m {
+		return &ExtractError{Path: name, Err: ErrMaxFiles}
+	}
+
+	if info.IsDir() {
+		dst, e := x.realPath(name, true)
+		if e != nil {
+			return e
+		} else if e = createPath(dst, x.dirMode(info.Mode())); e != nil {
+			return e
+		} else if x.o.PreserveTime {
+			x.t = append(x.t, dirTime{p: dst, t: info.ModTime()})
+		}
+		return nil
+	} else if info.Mode()&os.ModeSymlink != 0 {
+		if target == "" && r != nil {
+			// zip archive store the target of symlink as content
+			if p, e := io.ReadAll(io.LimitReader(r, 4096)); e != nil {
+				return e
+			} else {
+				target = string(p)
+			}
+		}
+		return x.writeLink(true, name, target)
+	} else if isHardLink(info) {
+		return x.writeLink(false, name, target)
+	} else if info.Mode().IsRegular() {
+		return x.writeFile(name, r, info)
+	}
+
+	// devices, fifo and others special files are not extracted
+	return nil
+}
+
+func isHardLink(info fs.FileInfo) bool {
+	if h, k := info.Sys().(*tar.Header); k {
+		return h.Typeflag == tar.TypeLink
+	}
+
+	return false
+}
+
+// localPath returns the path of the entry relative to the destination or an error if the entry goes outside.
+func localPath(name string) (string, error) {
+	p := filepath.FromSlash(name)
+	p = strings.TrimLeft(p, string(filepath.Separator))
+
+	if p == "" || p == "." {
+		return ".", nil
+	} else if !filepath.IsLocal(p) {
+		return "", &ExtractError{Path: name, Err: ErrPathTraversal}
+	}
+
+	return filepath.Clean(p), nil
+}
+
+// realPath returns the path of the entry into the destination with all symlinks already extracted resolved.
+// The entry is refused if its real path goes outside the destination.
+// The last element of the path is only resolved if follow is true.
+func (x *extract) realPath(name string, follow bool) (string, error) {
+	p, e := localPath(name)
+
+	if e != nil {
+		return "", e
+	} else if p == "." {
+		return x.r, nil
+	}
+
+	if follow {
+		p, e = resolve(filepath.Join(x.r, p))
+	} else if d, e2 := resolve(filepath.Join(x.r, filepath.Dir(p))); e2 != nil {
+		e = e2
+	} else {
+		p = filepath.Join(d, filepath.Base(p))
+	}
+
+	if e != nil || !x.within(p) {
+		return "", &ExtractError{Path: name, Err: ErrPathTraversal}
+	}
+
+	return p, nil
+}
+
+// within checks the given real path is into the real destination.
+func (x *extract) within(path string) bool {
+	if r, e := filepath.Rel(x.r, path); e != nil {
+		return false
+	} else {
+		return r == "." || filepath.IsLocal(r)
+	}
+}
+
+// maxLinks is the maximum count of symlinks followed to resolve a path, like the limit of the kernel.
+const maxLinks = 255
+
+// resolve returns the given absolute path with all existing symlinks resolved.
+// Unlike filepath.EvalSymlinks, the path does not need to exist : the missing
+// elements at the end of the path are kept as is.
+func resolve(path string) (string, error) {
+	var (
+		vol = filepath.VolumeName(path)
+		cur = vol + string(filepath.Separator)
+		lst = splitPath(path[len(vol):])
+		lnk int
+	)
+
+	for len(lst) > 0 {
+		c := lst[0]
+		lst = lst[1:]
+
+		switch c {
+		case "", ".":
+			continue
+		case "..":
+			cur = filepath.Dir(cur)
+			continue
+		}
+
+		n := filepath.Join(cur, c)
+		i, e := os.Lstat(n)
+
+		if e != nil && os.IsNotExist(e) {
+			// nothing exists after, so no more symlink to resolve
+			return filepath.Join(append([]string{n}, lst...)...), nil
+		} else if e != nil {
+			return "", e
+		} else if i.Mode()&os.ModeSymlink == 0 {
+			cur = n
+			continue
+		}
+
+		if lnk++; lnk > maxLinks {
+			return "", &fs.PathError{Op: "resolve", Path: path, Err: fs.ErrInvalid}
+		}
+
+		t, e := os.Readlink(n)
+		if e != nil {
+			return "", e
+		}
+
+		if filepath.IsAbs(t) {
+			v := filepath.VolumeName(t)
+			cur = v + string(filepath.Separator)
+			t = t[len(v):]
+		}
+
+		lst = append(splitPath(t), lst...)
+	}
+
+	return cur, nil
+}
+
+func splitPath(p string) []string {
+	return strings.Split(filepath.ToSlash(p), "/")
+}
+
+func (x *extract) dirMode(m os.FileMode) os.FileMode {
+	if x.o.DirMode != 0 {
+		return x.o.DirMode
+	}
+
+	return m.Perm()
+}
+
+func (x *extract) fileMode(m os.FileMode) os.FileMode {
+	if x.o.FileMode != 0 {
+		return x.o.FileMode
 	}
 
-	return path
+	return m.Perm()
+}
+
+func (x *extract) check(name string, n int64) error {
+	w := x.w.Add(n)
+
+	if m := x.o.MaxSize; m > 0 && uint64(w) > uint64(m) {
+		return &ExtractError{Path: name, Err: ErrMaxSize}
+	} else if m := x.o.MaxRatio; m > 0 {
+		if i := x.i.Load(); i > 0 && float64(w)/float64(i) > m {
+			return &ExtractError{Path: name, Err: ErrMaxRatio}
+		}
+	}
+
+	return nil
 }
 
 func createPath(dest string, info os.FileMode) error {
@@ -140,10 +361,10 @@ func createPath(dest string, info os.FileMode) error {
 	}
 }
 
-func writeFile(name, dest string, r io.ReadCloser, i fs.FileInfo) error {
+func (x *extract) writeFile(name string, r io.ReadCloser, i fs.FileInfo) error {
 	var (
-		dst = filepath.Join(dest, cleanPath(name))
 		hdf *os.File
+		dst string
 		err error
 	)
 
@@ -154,14 +375,29 @@ func writeFile(name, dest string, r io.ReadCloser, i fs.FileInfo) error {
 		}
 	}()
 
-	if err = createPath(filepath.Dir(dst), 0); err != nil {
+	if dst, err = x.realPath(name, false); err != nil {
+		return err
+	} else if err = createPath(filepath.Dir(dst), x.dirMode(0)); err != nil {
 		return err
-	} else if hdf, err = os.Create(dst); err != nil {
+	} else if hdf, err = x.create(name, dst); err != nil {
 		return err
-	} else if _, err = io.Copy(hdf, r); err != nil {
+	} else if _, err = io.Copy(&guard{w: hdf, x: x, n: name}, r); err != nil {
 		return err
 	} else if i != nil {
-		if err = os.Chmod(dst, i.Mode()); err != nil {
+		if err = os.Chmod(dst, x.fileMode(i.Mode())); err != nil {
+			return err
+		}
+
+		if x.o.PreserveTime {
+			_ = hdf.Close()
+			hdf = nil
+
+			if err = os.Chtimes(dst, i.ModTime(), i.ModTime()); err != nil {
+				return err
+			}
+		}
+	} else if x.o.FileMode != 0 {
+		if err = os.Chmod(dst, x.o.FileMode); err != nil {
 			return err
 		}
 	}
@@ -169,17 +405,61 @@ func writeFile(name, dest string, r io.ReadCloser, i fs.FileInfo) error {
 	return nil
 }
 
-func writeSymLink(isSymLink bool, name, target, dest string) error {
+// create opens the file to write, without following a symlink already extracted at this path.
+func (x *extract) create(name, dst string) (*os.File, error) {
+	if i, e := os.Lstat(dst); e != nil && os.IsNotExist(e) {
+		return os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	} else if e != nil {
+		return nil, e
+	} else if i.Mode()&os.ModeSymlink != 0 {
+		return nil, &ExtractError{Path: name, Err: ErrLinkFollow}
+	} else if !i.Mode().IsRegular() {
+		return nil, os.ErrInvalid
+	}
+
+	return os.OpenFile(dst, os.O_WRONLY|os.O_TRUNC, 0600)
+}
+
+func (x *extract) writeLink(isSymLink bool, name, target string) error {
 	var (
-		dst = filepath.Join(dest, cleanPath(name))
+		dst string
 		err error
+		pol = x.o.HardLink
 	)
 
-	if err = createPath(filepath.Dir(dst), 0); err != nil {
+	if isSymLink {
+		pol = x.o.SymLink
+	}
+
+	switch pol {
+	case LinkSkip:
+		return nil
+	case LinkDeny:
+		return &ExtractError{Path: name, Err: ErrLinkDenied}
+	}
+
+	if dst, err = x.realPath(name, false); err != nil {
 		return err
-	} else if isSymLink {
-		return os.Symlink(target, dst)
-	} else {
+	} else if err = createPath(filepath.Dir(dst), x.dirMode(0)); err != nil {
+		return err
+	}
+
+	if !isSymLink {
+		// hard link target is a path of the archive
+		if target, err = x.realPath(target, false); err != nil {
+			return &ExtractError{Path: name, Err: ErrLinkOutside}
+		}
 		return os.Link(target, dst)
 	}
+
+	if pol == LinkSafe {
+		// the target is checked from the real location of the link
+		if filepath.IsAbs(target) {
+			return &ExtractError{Path: name, Err: ErrLinkOutside}
+		} else if r, e := resolve(filepath.Join(filepath.Dir(dst), target)); e != nil || !x.within(r) {
+			return &ExtractError{Path: name, Err: ErrLinkOutside}
+		}
+	}
+
+	return os.Symlink(target, dst)
 }
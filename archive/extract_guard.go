@@ -0,0 +1,81 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2024 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package archive
+
+import (
+	"io"
+	"io/fs"
+	"sync/atomic"
+)
+
+// counter count the bytes read from the source archive to compute the compression ratio.
+type counter struct {
+	r io.ReadCloser
+	n *atomic.Int64
+}
+
+func (c *counter) Read(p []byte) (int, error) {
+	n, e := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, e
+}
+
+func (c *counter) ReadAt(p []byte, off int64) (int, error) {
+	if r, k := c.r.(io.ReaderAt); k {
+		n, e := r.ReadAt(p, off)
+		c.n.Add(int64(n))
+		return n, e
+	}
+
+	return 0, fs.ErrInvalid
+}
+
+func (c *counter) Seek(offset int64, whence int) (int64, error) {
+	if r, k := c.r.(io.Seeker); k {
+		return r.Seek(offset, whence)
+	}
+
+	return 0, fs.ErrInvalid
+}
+
+func (c *counter) Close() error {
+	return c.r.Close()
+}
+
+// guard checks the limits of extraction at each write.
+type guard struct {
+	w io.Writer
+	x *extract
+	n string
+}
+
+func (g *guard) Write(p []byte) (int, error) {
+	if e := g.x.check(g.n, int64(len(p))); e != nil {
+		return 0, e
+	}
+
+	return g.w.Write(p)
+}
@@ -0,0 +1,98 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2024 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	libsiz "github.com/nabbar/golib/size"
+)
+
+// LinkPolicy define how symbolic and hard links found into an archive are extracted.
+type LinkPolicy uint8
+
+const (
+	// LinkAllow creates the link whatever its target.
+	LinkAllow LinkPolicy = iota
+	// LinkSafe creates the link only if its target stays into the destination path.
+	LinkSafe
+	// LinkSkip ignores the link silently.
+	LinkSkip
+	// LinkDeny stops the extraction with an error.
+	LinkDeny
+)
+
+var (
+	ErrPathTraversal = errors.New("entry path is outside of the destination")
+	ErrLinkDenied    = errors.New("entry link is not allowed")
+	ErrLinkOutside   = errors.New("entry link target is outside of the destination")
+	ErrLinkFollow    = errors.New("entry would be written through an existing link")
+	ErrMaxSize       = errors.New("total uncompressed size exceed the limit")
+	ErrMaxFiles      = errors.New("number of entries exceed the limit")
+	ErrMaxRatio      = errors.New("compression ratio exceed the limit")
+)
+
+// ExtractError is returned when an entry violates the extract options.
+// The Err field is one of the ErrXXX errors of this package.
+type ExtractError struct {
+	Path string
+	Err  error
+}
+
+func (e *ExtractError) Error() string {
+	return fmt.Sprintf("%s: '%s'", e.Err.Error(), e.Path)
+}
+
+func (e *ExtractError) Unwrap() error {
+	return e.Err
+}
+
+// ExtractOptions define the policy applied when extracting an archive.
+// The zero value keeps the default behavior : no limit, links allowed and permissions preserved.
+// Entries with a path outside the destination, directly or through a symlink already
+// extracted, are always refused and an entry is never written through an existing symlink.
+type ExtractOptions struct {
+	// SymLink define the policy for symbolic links.
+	SymLink LinkPolicy
+	// HardLink define the policy for hard links.
+	HardLink LinkPolicy
+
+	// MaxSize define the maximum total size of extracted data (0 means no limit).
+	MaxSize libsiz.Size
+	// MaxFiles define the maximum count of entries in the archive (0 means no limit).
+	MaxFiles uint64
+	// MaxRatio define the maximum ratio between extracted data size and read data size (0 means no limit).
+	MaxRatio float64
+
+	// FileMode override the permissions of extracted files if not zero.
+	FileMode os.FileMode
+	// DirMode override the permissions of extracted directories if not zero.
+	DirMode os.FileMode
+	// PreserveTime apply the modification time of each entry to the extracted file or directory.
+	PreserveTime bool
+}
@@ -0,0 +1,193 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2024 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	arctps "github.com/nabbar/golib/archive/archive/types"
+	libfpg "github.com/nabbar/golib/file/progress"
+)
+
+// FuncTranscode is called for each entry of the source archive before it is written
+// into the destination archive. It returns the path to use into the destination
+// archive (empty to keep the original path) and false to skip the entry.
+type FuncTranscode func(info fs.FileInfo, path string) (newPath string, keep bool)
+
+// TranscodeProgress define the file/progress hooks called while the entries are streamed.
+// Each hook is optional.
+type TranscodeProgress struct {
+	// Reset is called at the beginning of each entry with the entry size and a current position of 0.
+	Reset libfpg.FctReset
+	// Increment is called with the number of bytes read from the current entry.
+	Increment libfpg.FctIncrement
+	// EOF is called when the current entry has been fully streamed.
+	EOF libfpg.FctEOF
+}
+
+// TranscodeError is returned when an entry of the source archive cannot be written into the destination archive.
+// The Err field is the error returned by the source reader, the destination writer or the progress hooks.
+type TranscodeError struct {
+	Path string
+	Err  error
+}
+
+func (e *TranscodeError) Error() string {
+	return fmt.Sprintf("transcoding '%s': %s", e.Path, e.Err.Error())
+}
+
+func (e *TranscodeError) Unwrap() error {
+	return e.Err
+}
+
+// Transcode streams each entry of the src archive into the dst archive, without
+// extracting anything on disk. The filter function allow to rename or skip entries
+// and could be nil to keep all entries unchanged.
+// The dst writer is not closed to allow the caller to add more entries.
+func Transcode(src arctps.Reader, dst arctps.Writer, filter FuncTranscode) error {
+	return TranscodeWithProgress(src, dst, filter, TranscodeProgress{})
+}
+
+// TranscodeWithProgress works as Transcode and calls the given progress hooks for each entry.
+func TranscodeWithProgress(src arctps.Reader, dst arctps.Writer, filter FuncTranscode, prg TranscodeProgress) error {
+	var err error
+
+	if src == nil || dst == nil {
+		return fs.ErrInvalid
+	}
+
+	src.Walk(func(info fs.FileInfo, r io.ReadCloser, path, target string) bool {
+		if err = prg.entry(dst, filter, info, r, path, target); err != nil {
+			err = &TranscodeError{Path: path, Err: err}
+			return false
+		}
+
+		return true
+	})
+
+	if w, k := src.(arctps.WalkError); k && err == nil {
+		err = w.WalkErr()
+	}
+
+	return err
+}
+
+func (p TranscodeProgress) entry(dst arctps.Writer, filter FuncTranscode, info fs.FileInfo, r io.ReadCloser, path, target string) error {
+	var (
+		name = path
+		keep = true
+	)
+
+	if info == nil {
+		if r != nil {
+			_ = r.Close()
+		}
+		return nil
+	}
+
+	if filter != nil {
+		if name, keep = filter(info, path); len(name) < 1 {
+			name = path
+		}
+	}
+
+	if !keep {
+		if r != nil {
+			_ = r.Close()
+		}
+		return nil
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		if target == "" && r != nil {
+			// zip archive store the target of symlink as content
+			if b, e := io.ReadAll(io.LimitReader(r, 4096)); e != nil {
+				_ = r.Close()
+				return e
+			} else {
+				target = string(b)
+			}
+		}
+
+		if r != nil {
+			_ = r.Close()
+		}
+
+		return dst.Add(info, nil, name, target)
+	}
+
+	if r == nil {
+		return dst.Add(info, nil, name, target)
+	}
+
+	if p.Reset != nil {
+		p.Reset(info.Size(), 0)
+	}
+
+	return dst.Add(info, &trcReader{r: r, p: p}, name, target)
+}
+
+// trcReader calls the progress hooks while the entry is read by the destination writer.
+type trcReader struct {
+	r io.ReadCloser
+	p TranscodeProgress
+	e bool
+}
+
+func (o *trcReader) Read(b []byte) (int, error) {
+	n, err := o.r.Read(b)
+
+	if n > 0 && o.p.Increment != nil {
+		o.p.Increment(int64(n))
+	}
+
+	if err != nil && errors.Is(err, io.EOF) {
+		o.finish()
+	}
+
+	return n, err
+}
+
+func (o *trcReader) Close() error {
+	o.finish()
+	return o.r.Close()
+}
+
+func (o *trcReader) finish() {
+	if o.e {
+		return
+	}
+
+	o.e = true
+
+	if o.p.EOF != nil {
+		o.p.EOF()
+	}
+}
@@ -0,0 +1,231 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package cache_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	libcch "github.com/nabbar/golib/cache"
+	libsiz "github.com/nabbar/golib/size"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func has(c libcch.Cache[string], key ...string) []bool {
+	var res = make([]bool, 0, len(key))
+
+	for _, k := range key {
+		var found bool
+
+		c.Walk(func(i any, _ interface{}, _ time.Duration) bool {
+			if i == k {
+				found = true
+				return false
+			}
+			return true
+		})
+
+		res = append(res, found)
+	}
+
+	return res
+}
+
+var _ = Describe("Cache Eviction", func() {
+	var (
+		ctx context.Context
+		cnl context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		ctx, cnl = context.WithCancel(context.Background())
+	})
+
+	AfterEach(func() {
+		cnl()
+	})
+
+	Context("with the LRU policy", func() {
+		It("must evict the least recently used entry", func() {
+			c := libcch.NewWithEviction[string](ctx, time.Minute, libcch.Eviction{Policy: libcch.PolicyLRU, MaxEntries: 3})
+
+			c.Store("a", 1)
+			c.Store("b", 2)
+			c.Store("c", 3)
+
+			_, _, ok := c.Load("a")
+			Expect(ok).To(BeTrue())
+
+			c.Store("d", 4)
+			Expect(has(c, "a", "b", "c", "d")).To(Equal([]bool{true, false, true, true}))
+
+			c.Store("e", 5)
+			Expect(has(c, "a", "c", "d", "e")).To(Equal([]bool{true, false, true, true}))
+
+			s := c.Stats()
+			Expect(s.Evictions).To(Equal(uint64(2)))
+			Expect(s.Entries).To(Equal(uint64(3)))
+		})
+
+		It("must treat a replaced entry as recently used", func() {
+			c := libcch.NewWithEviction[string](ctx, time.Minute, libcch.Eviction{Policy: libcch.PolicyLRU, MaxEntries: 2})
+
+			c.Store("a", 1)
+			c.Store("b", 2)
+			c.Store("a", 10)
+			c.Store("c", 3)
+
+			Expect(has(c, "a", "b", "c")).To(Equal([]bool{true, false, true}))
+			Expect(c.Stats().Entries).To(Equal(uint64(2)))
+		})
+	})
+
+	Context("with the LFU policy", func() {
+		It("must evict the least frequently used entry and the oldest on equality", func() {
+			c := libcch.NewWithEviction[string](ctx, time.Minute, libcch.Eviction{Policy: libcch.PolicyLFU, MaxEntries: 3})
+
+			c.Store("a", 1)
+			c.Store("b", 2)
+			c.Store("c", 3)
+
+			c.Load("a")
+			c.Load("a")
+			c.Load("b")
+
+			c.Store("d", 4)
+			Expect(has(c, "a", "b", "c", "d")).To(Equal([]bool{true, true, false, true}))
+
+			c.Store("e", 5)
+			Expect(has(c, "a", "b", "d", "e")).To(Equal([]bool{true, true, false, true}))
+		})
+
+		It("must not evict the entry just stored when all other entries have been used", func() {
+			c := libcch.NewWithEviction[string](ctx, time.Minute, libcch.Eviction{Policy: libcch.PolicyLFU, MaxEntries: 3})
+
+			c.Store("a", 1)
+			c.Store("b", 2)
+			c.Store("c", 3)
+
+			c.Load("a")
+			c.Load("b")
+			c.Load("c")
+
+			c.Store("d", 4)
+			Expect(has(c, "a", "b", "c", "d")).To(Equal([]bool{false, true, true, true}))
+			Expect(c.Stats().Evictions).To(Equal(uint64(1)))
+		})
+	})
+
+	Context("with a weigher", func() {
+		var ev = libcch.Eviction{
+			Policy:    libcch.PolicyLRU,
+			MaxWeight: 10 * libsiz.SizeUnit,
+			Weigher: func(_ any, val interface{}) libsiz.Size {
+				return libsiz.Size(len(fmt.Sprint(val)))
+			},
+		}
+
+		It("must keep the total weight under the limit", func() {
+			c := libcch.NewWithEviction[string](ctx, time.Minute, ev)
+
+			c.Store("a", "aaaa")
+			c.Store("b", "bbbb")
+			Expect(c.Stats().Weight).To(Equal(libsiz.Size(8)))
+
+			c.Store("c", "cccc")
+			Expect(has(c, "a", "b", "c")).To(Equal([]bool{false, true, true}))
+
+			s := c.Stats()
+			Expect(s.Weight).To(Equal(libsiz.Size(8)))
+			Expect(s.Entries).To(Equal(uint64(2)))
+			Expect(s.Evictions).To(Equal(uint64(1)))
+
+			c.Delete("b")
+			Expect(c.Stats().Weight).To(Equal(libsiz.Size(4)))
+		})
+
+		It("must evict an entry heavier than the limit", func() {
+			c := libcch.NewWithEviction[string](ctx, time.Minute, ev)
+
+			c.Store("a", "aaaaaaaaaaaaaaaaaaaa")
+			Expect(has(c, "a")).To(Equal([]bool{false}))
+
+			s := c.Stats()
+			Expect(s.Weight).To(Equal(libsiz.SizeNul))
+			Expect(s.Entries).To(Equal(uint64(0)))
+		})
+	})
+
+	Context("without policy", func() {
+		It("must ignore the limits", func() {
+			c := libcch.NewWithEviction[string](ctx, time.Minute, libcch.Eviction{MaxEntries: 1})
+
+			c.Store("a", 1)
+			c.Store("b", 2)
+
+			Expect(c.Stats().Entries).To(Equal(uint64(2)))
+			Expect(c.Stats().Evictions).To(BeZero())
+		})
+	})
+
+	Context("with the stats", func() {
+		It("must count hits, misses and entries", func() {
+			c := libcch.New[string](ctx, time.Minute)
+
+			c.Store("a", 1)
+			c.Store("b", 2)
+
+			c.Load("a")
+			c.Load("a")
+			c.Load("b")
+			c.Load("z")
+
+			s := c.Stats()
+			Expect(s.Hits).To(Equal(uint64(3)))
+			Expect(s.Misses).To(Equal(uint64(1)))
+			Expect(s.Entries).To(Equal(uint64(2)))
+			Expect(s.HitRatio()).To(BeNumerically("~", 0.75))
+
+			c.Clean()
+			Expect(c.Stats().Entries).To(BeZero())
+		})
+
+		It("must count an expired entry as a miss", func() {
+			c := libcch.New[string](ctx, 50*time.Millisecond)
+
+			c.Store("a", 1)
+			time.Sleep(100 * time.Millisecond)
+
+			_, _, ok := c.Load("a")
+			Expect(ok).To(BeFalse())
+			Expect(c.Stats().Misses).To(Equal(uint64(1)))
+			Expect(c.Stats().Entries).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,176 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	libcch "github.com/nabbar/golib/cache"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cache Loader", func() {
+	var (
+		ctx context.Context
+		cnl context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		ctx, cnl = context.WithCancel(context.Background())
+	})
+
+	AfterEach(func() {
+		cnl()
+	})
+
+	It("must collapse concurrent loads of a same key", func() {
+		var (
+			c = libcch.New[string](ctx, time.Minute)
+			n atomic.Int32
+			w sync.WaitGroup
+		)
+
+		for i := 0; i < 20; i++ {
+			w.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer w.Done()
+
+				v, _, e := c.GetOrLoad("a", func(key any) (interface{}, error) {
+					n.Add(1)
+					time.Sleep(50 * time.Millisecond)
+					return "val", nil
+				})
+
+				Expect(e).ToNot(HaveOccurred())
+				Expect(v).To(Equal("val"))
+			}()
+		}
+
+		w.Wait()
+		Expect(n.Load()).To(Equal(int32(1)))
+
+		v, _, ok := c.Load("a")
+		Expect(ok).To(BeTrue())
+		Expect(v).To(Equal("val"))
+	})
+
+	It("must not cache a loader error", func() {
+		var (
+			c = libcch.New[string](ctx, time.Minute)
+			r = errors.New("load failed")
+		)
+
+		_, _, e := c.GetOrLoad("a", func(key any) (interface{}, error) {
+			return nil, r
+		})
+		Expect(e).To(MatchError(r))
+
+		_, _, ok := c.Load("a")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("must reject a nil value returned by the loader", func() {
+		c := libcch.New[string](ctx, time.Minute)
+
+		v, _, e := c.GetOrLoad("a", func(key any) (interface{}, error) {
+			return nil, nil
+		})
+
+		Expect(e).To(MatchError(libcch.ErrLoaderNilValue))
+		Expect(v).To(BeNil())
+		Expect(c.Stats().Entries).To(BeZero())
+	})
+
+	It("must return an error without loader", func() {
+		c := libcch.New[string](ctx, time.Minute)
+
+		_, _, e := c.GetOrLoad("a", nil)
+		Expect(e).To(MatchError(libcch.ErrLoaderMissing))
+	})
+
+	It("must expire an entry with its own ttl", func() {
+		c := libcch.New[string](ctx, time.Minute)
+
+		c.StoreWithTTL("a", 1, 50*time.Millisecond)
+		c.Store("b", 2)
+
+		time.Sleep(100 * time.Millisecond)
+
+		_, _, ok := c.Load("a")
+		Expect(ok).To(BeFalse())
+
+		_, _, ok = c.Load("b")
+		Expect(ok).To(BeTrue())
+	})
+
+	It("must refresh ahead only once for concurrent accesses", func() {
+		var (
+			c = libcch.New[string](ctx, 200*time.Millisecond)
+			n atomic.Int32
+			f = func(key any) (interface{}, error) {
+				time.Sleep(50 * time.Millisecond)
+				return n.Add(1), nil
+			}
+		)
+
+		c.SetRefreshAhead(150 * time.Millisecond)
+
+		v, _, e := c.GetOrLoad("a", f)
+		Expect(e).ToNot(HaveOccurred())
+		Expect(v).To(Equal(int32(1)))
+
+		time.Sleep(100 * time.Millisecond)
+
+		var w sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			w.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer w.Done()
+
+				r, _, er := c.GetOrLoad("a", f)
+				Expect(er).ToNot(HaveOccurred())
+				Expect(r).To(Equal(int32(1)))
+			}()
+		}
+
+		w.Wait()
+		time.Sleep(120 * time.Millisecond)
+
+		Expect(n.Load()).To(Equal(int32(2)))
+
+		r, _, ok := c.Load("a")
+		Expect(ok).To(BeTrue())
+		Expect(r).To(Equal(int32(2)))
+	})
+})
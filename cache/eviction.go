@@ -0,0 +1,280 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package cache
+
+import (
+	"container/heap"
+	"sync"
+
+	libsiz "github.com/nabbar/golib/size"
+)
+
+// Policy defines how entries are chosen for eviction when a bounded cache is full.
+type Policy uint8
+
+const (
+	// PolicyNone disables eviction : limits are ignored and entries only leave the cache by expiration.
+	PolicyNone Policy = iota
+	// PolicyLRU evicts first the least recently used entries.
+	PolicyLRU
+	// PolicyLFU evicts first the least frequently used entries, the least recently used on equality.
+	PolicyLFU
+)
+
+// FuncWeigher returns the weight of a cached value used to compute the total weight of the cache.
+type FuncWeigher func(key any, val interface{}) libsiz.Size
+
+// Eviction defines the bounds of a cache and the policy used to keep it within these bounds.
+// A zero value for MaxEntries or MaxWeight means no limit on this bound.
+// MaxWeight is only applied when a Weigher is defined.
+type Eviction struct {
+	Policy     Policy
+	MaxEntries uint64
+	MaxWeight  libsiz.Size
+	Weigher    FuncWeigher
+}
+
+func (p Policy) String() string {
+	switch p {
+	case PolicyLRU:
+		return "lru"
+	case PolicyLFU:
+		return "lfu"
+	default:
+		return "none"
+	}
+}
+
+func (e Eviction) enabled() bool {
+	if e.Policy == PolicyNone {
+		return false
+	}
+
+	return e.MaxEntries > 0 || (e.MaxWeight > 0 && e.Weigher != nil)
+}
+
+func (e Eviction) weight(key any, val interface{}) libsiz.Size {
+	if e.Weigher == nil {
+		return libsiz.SizeNul
+	}
+
+	return e.Weigher(key, val)
+}
+
+func (e Eviction) over(entries uint64, weight libsiz.Size) bool {
+	if e.MaxEntries > 0 && entries > e.MaxEntries {
+		return true
+	} else if e.MaxWeight > 0 && e.Weigher != nil && weight > e.MaxWeight {
+		return true
+	}
+
+	return false
+}
+
+// evictNode is the position of a key into the eviction index.
+// The access sequence is used instead of a timestamp to keep a strict order between entries.
+type evictNode struct {
+	k any
+	a uint64 // sequence of last access
+	f uint64 // number of accesses
+	i int    // index into the heap
+}
+
+// evictIndex keeps the keys of a bounded cache into a min heap ordered by the eviction policy,
+// so the next entry to evict is found without walking the whole cache.
+// A nil index is used when the eviction is disabled and all its methods are no-op.
+type evictIndex struct {
+	m sync.Mutex
+	p Policy
+	n map[any]*evictNode
+	h []*evictNode
+	s uint64
+}
+
+func newEvictIndex(e Eviction) *evictIndex {
+	if !e.enabled() {
+		return nil
+	}
+
+	return &evictIndex{
+		m: sync.Mutex{},
+		p: e.Policy,
+		n: make(map[any]*evictNode),
+		h: make([]*evictNode, 0),
+	}
+}
+
+func (x *evictIndex) Len() int {
+	return len(x.h)
+}
+
+func (x *evictIndex) Less(i, j int) bool {
+	if x.p == PolicyLFU && x.h[i].f != x.h[j].f {
+		return x.h[i].f < x.h[j].f
+	}
+
+	return x.h[i].a < x.h[j].a
+}
+
+func (x *evictIndex) Swap(i, j int) {
+	x.h[i], x.h[j] = x.h[j], x.h[i]
+	x.h[i].i = i
+	x.h[j].i = j
+}
+
+func (x *evictIndex) Push(v any) {
+	n := v.(*evictNode)
+	n.i = len(x.h)
+	x.h = append(x.h, n)
+}
+
+func (x *evictIndex) Pop() any {
+	l := len(x.h)
+	n := x.h[l-1]
+	x.h[l-1] = nil
+	x.h = x.h[:l-1]
+	return n
+}
+
+func (x *evictIndex) lock() {
+	if x != nil {
+		x.m.Lock()
+	}
+}
+
+func (x *evictIndex) unlock() {
+	if x != nil {
+		x.m.Unlock()
+	}
+}
+
+func (x *evictIndex) seq() uint64 {
+	x.s++
+	return x.s
+}
+
+// add registers a new key or marks a replaced key as accessed, the lock must be held.
+// A new key starts with the lowest frequency of the index, so with the LFU policy
+// it is not evicted before older entries that have not been used more than it.
+func (x *evictIndex) add(key any) {
+	if x == nil {
+		return
+	} else if n, ok := x.n[key]; ok {
+		x.use(n)
+		return
+	}
+
+	n := &evictNode{
+		k: key,
+		a: x.seq(),
+	}
+
+	if len(x.h) > 0 {
+		n.f = x.h[0].f
+	}
+
+	x.n[key] = n
+	heap.Push(x, n)
+}
+
+func (x *evictIndex) use(n *evictNode) {
+	n.a = x.seq()
+	n.f++
+	heap.Fix(x, n.i)
+}
+
+// touch marks the key as accessed.
+func (x *evictIndex) touch(key any) {
+	if x == nil {
+		return
+	}
+
+	x.m.Lock()
+	defer x.m.Unlock()
+
+	if n, ok := x.n[key]; ok {
+		x.use(n)
+	}
+}
+
+// remove drops the key from the index, the lock must be held.
+func (x *evictIndex) remove(key any) {
+	if x == nil {
+		return
+	} else if n, ok := x.n[key]; ok {
+		heap.Remove(x, n.i)
+		delete(x.n, key)
+	}
+}
+
+func (x *evictIndex) reset() {
+	if x == nil {
+		return
+	}
+
+	x.m.Lock()
+	defer x.m.Unlock()
+
+	x.n = make(map[any]*evictNode)
+	x.h = make([]*evictNode, 0)
+}
+
+// victim returns the next key to evict. The skip key (the entry being stored)
+// is only returned if it is the last entry of the index.
+func (x *evictIndex) victim(skip any) (any, bool) {
+	x.m.Lock()
+	defer x.m.Unlock()
+
+	if len(x.h) < 1 {
+		return nil, false
+	} else if x.h[0].k != skip || len(x.h) == 1 {
+		return x.h[0].k, true
+	} else if len(x.h) == 2 || x.Less(1, 2) {
+		return x.h[1].k, true
+	}
+
+	return x.h[2].k, true
+}
+
+// evict removes entries following the policy while the cache is over its bounds.
+// The key is the entry just stored, kept unless it is the only entry left.
+func (t *cache[T]) evict(key any) {
+	if t.x == nil {
+		return
+	}
+
+	t.w.RLock()
+	defer t.w.RUnlock()
+
+	for t.v.over(t.s.entries.Load(), libsiz.Size(t.s.weight.Load())) {
+		if k, ok := t.x.victim(key); !ok {
+			return
+		} else if t.del(k) {
+			t.s.evictions.Add(1)
+		}
+	}
+}
@@ -44,13 +44,32 @@ type Cache[T any] interface {
 
 	Load(key any) (val interface{}, exp time.Duration, ok bool)
 	Store(key any, val interface{}) time.Duration
+	// StoreWithTTL stores the value with its own expiration instead of the cache expiration.
+	StoreWithTTL(key any, val interface{}, ttl time.Duration) time.Duration
 	Delete(key any)
 
 	LoadOrStore(key any, val interface{}) (res interface{}, exp time.Duration, loaded bool)
 	LoadAndDelete(key any) (val interface{}, loaded bool)
+
+	// GetOrLoad returns the cached value of the key or call the loader to fetch it.
+	// Concurrent calls for a same missing key are collapsed into only one call of the loader.
+	GetOrLoad(key any, fct FuncLoader) (val interface{}, exp time.Duration, err error)
+	// SetRefreshAhead enables the background reload of entries fetched with GetOrLoad
+	// when they are accessed with a remaining lifetime lower than the given duration.
+	// A zero duration disables the refresh-ahead.
+	SetRefreshAhead(before time.Duration)
+
+	Stats() Stats
 }
 
 func New[T any](ctx context.Context, exp time.Duration) Cache[T] {
+	return NewWithEviction[T](ctx, exp, Eviction{})
+}
+
+// NewWithEviction returns a cache bounded by the given eviction settings.
+// When the number of entries or the total weight exceed the limits, entries
+// are evicted following the eviction policy until the cache fit again in its bounds.
+func NewWithEviction[T any](ctx context.Context, exp time.Duration, ev Eviction) Cache[T] {
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -65,6 +84,8 @@ func New[T any](ctx context.Context, exp time.Duration) Cache[T] {
 		m:       sync.Map{},
 		c:       make(chan struct{}),
 		e:       exp,
+		v:       ev,
+		x:       newEvictIndex(ev),
 	}
 
 	go n.ticker(exp)
@@ -26,24 +26,40 @@
 
 package cache
 
-import "time"
+import (
+	"time"
+
+	libsiz "github.com/nabbar/golib/size"
+)
 
 type cacheItem struct {
 	t time.Time
 	v interface{}
+	e time.Duration
+	l FuncLoader
+	w libsiz.Size
 }
 
-func store(val interface{}) *cacheItem {
+func store(val interface{}, weight libsiz.Size, ttl time.Duration, fct FuncLoader) *cacheItem {
 	return &cacheItem{
 		t: time.Now(),
 		v: val,
+		e: ttl,
+		l: fct,
+		w: weight,
 	}
 }
 
 func parse(v any, exp time.Duration) (interface{}, time.Duration) {
-	if i, ok := v.(*cacheItem); !ok {
+	i, ok := v.(*cacheItem)
+
+	if !ok || i == nil {
 		return nil, 0
-	} else if e := exp - time.Since(i.t); e < time.Microsecond {
+	} else if i.e > 0 {
+		exp = i.e
+	}
+
+	if e := exp - time.Since(i.t); e < time.Microsecond {
 		return nil, 0
 	} else {
 		return i.v, e
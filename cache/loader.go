@@ -0,0 +1,137 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// FuncLoader is called to load the value of a key missing in the cache.
+type FuncLoader func(key any) (val interface{}, err error)
+
+type call struct {
+	w sync.WaitGroup
+	v interface{}
+	e time.Duration
+	r error
+}
+
+type group struct {
+	m sync.Mutex
+	c map[any]*call
+}
+
+// begin registers a new call for the key and returns true,
+// or returns the call already running for the key and false.
+func (g *group) begin(key any) (*call, bool) {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	if g.c == nil {
+		g.c = make(map[any]*call)
+	}
+
+	if c, ok := g.c[key]; ok {
+		return c, false
+	}
+
+	c := &call{}
+	c.w.Add(1)
+	g.c[key] = c
+
+	return c, true
+}
+
+func (g *group) end(key any, c *call) {
+	g.m.Lock()
+	delete(g.c, key)
+	g.m.Unlock()
+	c.w.Done()
+}
+
+func (g *group) do(key any, fct func() (interface{}, time.Duration, error)) (interface{}, time.Duration, error) {
+	c, ok := g.begin(key)
+
+	if !ok {
+		c.w.Wait()
+		return c.v, c.e, c.r
+	}
+
+	defer g.end(key, c)
+
+	c.v, c.e, c.r = fct()
+	return c.v, c.e, c.r
+}
+
+func (t *cache[T]) fetch(key any, fct FuncLoader) (interface{}, time.Duration, error) {
+	if v, err := fct(key); err != nil {
+		return nil, 0, err
+	} else if v == nil {
+		return nil, 0, ErrLoaderNilValue
+	} else {
+		return v, t.store(key, v, 0, fct), nil
+	}
+}
+
+func (t *cache[T]) load(key any, fct FuncLoader) (interface{}, time.Duration, error) {
+	return t.g.do(key, func() (interface{}, time.Duration, error) {
+		return t.fetch(key, fct)
+	})
+}
+
+func (t *cache[T]) refresh(key any, i *cacheItem, exp time.Duration) {
+	if i.l == nil || t.Err() != nil {
+		return
+	} else if r := time.Duration(t.r.Load()); r < time.Microsecond || exp > r {
+		return
+	}
+
+	// the call is registered before starting the goroutine to not run two refresh of a same key
+	if c, ok := t.g.begin(key); ok {
+		go func() {
+			defer t.g.end(key, c)
+			c.v, c.e, c.r = t.fetch(key, i.l)
+		}()
+	}
+}
+
+func (t *cache[T]) GetOrLoad(key any, fct FuncLoader) (val interface{}, exp time.Duration, err error) {
+	var ok bool
+
+	if val, exp, ok = t.Load(key); ok {
+		return val, exp, nil
+	} else if fct == nil {
+		return nil, 0, ErrLoaderMissing
+	}
+
+	return t.load(key, fct)
+}
+
+func (t *cache[T]) SetRefreshAhead(before time.Duration) {
+	t.r.Store(int64(before))
+}
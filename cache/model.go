@@ -29,6 +29,7 @@ package cache
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -39,6 +40,11 @@ type cache[T any] struct {
 	m sync.Map
 	c chan struct{}
 	e time.Duration
+	v Eviction
+	x *evictIndex
+	s stats
+	g group
+	r atomic.Int64
 }
 
 func (t *cache[T]) ticker(exp time.Duration) {
@@ -69,12 +75,41 @@ func (t *cache[T]) expire() {
 
 	t.m.Range(func(key, value any) bool {
 		if v, _ := parse(value, exp); v == nil {
-			t.m.Delete(key)
+			t.del(key)
 		}
 		return true
 	})
 }
 
+func (t *cache[T]) set(key any, i *cacheItem) {
+	t.x.lock()
+	defer t.x.unlock()
+
+	if o, l := t.m.Swap(key, i); l {
+		if p, k := o.(*cacheItem); k {
+			t.s.sub(p.w)
+		}
+	}
+
+	t.s.add(i.w)
+	t.x.add(key)
+}
+
+func (t *cache[T]) del(key any) bool {
+	t.x.lock()
+	defer t.x.unlock()
+
+	t.x.remove(key)
+
+	if o, l := t.m.LoadAndDelete(key); !l {
+		return false
+	} else if p, k := o.(*cacheItem); k {
+		t.s.sub(p.w)
+	}
+
+	return true
+}
+
 func (t *cache[T]) Clone(ctx context.Context, exp time.Duration) Cache[T] {
 	t.w.RLock()
 	defer t.w.RUnlock()
@@ -93,10 +128,16 @@ func (t *cache[T]) Clone(ctx context.Context, exp time.Duration) Cache[T] {
 		m:       sync.Map{},
 		c:       make(chan struct{}),
 		e:       exp,
+		v:       t.v,
+		x:       newEvictIndex(t.v),
 	}
 
+	n.r.Store(t.r.Load())
+
 	t.m.Range(func(key any, val interface{}) bool {
-		n.Store(key, val)
+		if v, e := parse(val, t.e); v != nil {
+			n.store(key, v, e, val.(*cacheItem).l)
+		}
 		return true
 	})
 
@@ -114,6 +155,8 @@ func (t *cache[T]) Clean() {
 	defer t.w.Unlock()
 
 	t.m = sync.Map{}
+	t.s.reset()
+	t.x.reset()
 }
 
 func (t *cache[T]) Merge(c Cache[T]) {
@@ -121,10 +164,7 @@ func (t *cache[T]) Merge(c Cache[T]) {
 	defer t.w.RUnlock()
 
 	c.Walk(func(key any, val interface{}, exp time.Duration) bool {
-		t.m.Store(key, &cacheItem{
-			t: time.Now().Add(-exp),
-			v: nil,
-		})
+		t.set(key, store(val, t.v.weight(key, val), exp, nil))
 		return true
 	})
 }
@@ -137,7 +177,7 @@ func (t *cache[T]) Walk(fct func(key any, val interface{}, exp time.Duration) bo
 
 	t.m.Range(func(key, value any) bool {
 		if v, e := parse(value, exp); v == nil {
-			t.m.Delete(key)
+			t.del(key)
 			return true
 		} else {
 			return fct(key, v, e)
@@ -152,28 +192,53 @@ func (t *cache[T]) Load(key any) (val interface{}, exp time.Duration, ok bool) {
 	var o any
 
 	if o, ok = t.m.Load(key); !ok {
+		t.s.misses.Add(1)
 		return nil, 0, false
 	} else if val, exp = parse(o, t.e); val == nil {
-		t.m.Delete(key)
+		t.del(key)
+		t.s.misses.Add(1)
 		return nil, 0, false
 	} else {
+		i := o.(*cacheItem)
+		t.x.touch(key)
+		t.s.hits.Add(1)
+		t.refresh(key, i, exp)
 		return val, exp, true
 	}
 }
 
-func (t *cache[T]) Store(key any, val interface{}) time.Duration {
-	i := store(val)
+func (t *cache[T]) store(key any, val interface{}, ttl time.Duration, fct FuncLoader) time.Duration {
+	if ttl < time.Microsecond {
+		ttl = 0
+	}
+
+	i := store(val, t.v.weight(key, val), ttl, fct)
 	e := time.Now()
 
+	defer t.evict(key)
+
 	t.w.RLock()
 	defer t.w.RUnlock()
 
-	t.m.Store(key, i)
+	t.set(key, i)
+
+	if ttl > 0 {
+		return ttl - time.Since(e)
+	}
+
 	return t.e - time.Since(e)
 }
 
+func (t *cache[T]) Store(key any, val interface{}) time.Duration {
+	return t.store(key, val, 0, nil)
+}
+
+func (t *cache[T]) StoreWithTTL(key any, val interface{}, ttl time.Duration) time.Duration {
+	return t.store(key, val, ttl, nil)
+}
+
 func (t *cache[T]) Delete(key any) {
-	t.m.LoadAndDelete(key)
+	t.del(key)
 }
 
 func (t *cache[T]) LoadOrStore(key any, val interface{}) (res interface{}, exp time.Duration, loaded bool) {
@@ -193,7 +258,7 @@ func (t *cache[T]) LoadAndDelete(key any) (val interface{}, loaded bool) {
 	t.w.RLock()
 	defer t.w.RUnlock()
 
-	t.m.Delete(key)
+	t.del(key)
 	return val, loaded
 }
 
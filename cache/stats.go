@@ -0,0 +1,84 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package cache
+
+import (
+	"sync/atomic"
+
+	libsiz "github.com/nabbar/golib/size"
+)
+
+// Stats is a snapshot of the usage counters of a cache.
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   uint64
+	Weight    libsiz.Size
+}
+
+// HitRatio returns the ratio of hits on the total of lookups, or 0 if no lookup has been done.
+func (s Stats) HitRatio() float64 {
+	if t := s.Hits + s.Misses; t > 0 {
+		return float64(s.Hits) / float64(t)
+	}
+
+	return 0
+}
+
+type stats struct {
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+	entries   atomic.Uint64
+	weight    atomic.Uint64
+}
+
+func (s *stats) add(w libsiz.Size) {
+	s.entries.Add(1)
+	s.weight.Add(uint64(w))
+}
+
+func (s *stats) sub(w libsiz.Size) {
+	s.entries.Add(^uint64(0))
+	s.weight.Add(^uint64(w - 1))
+}
+
+func (s *stats) reset() {
+	s.entries.Store(0)
+	s.weight.Store(0)
+}
+
+func (t *cache[T]) Stats() Stats {
+	return Stats{
+		Hits:      t.s.hits.Load(),
+		Misses:    t.s.misses.Load(),
+		Evictions: t.s.evictions.Load(),
+		Entries:   t.s.entries.Load(),
+		Weight:    libsiz.Size(t.s.weight.Load()),
+	}
+}
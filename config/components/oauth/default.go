@@ -0,0 +1,70 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package oauth
+
+import (
+	"bytes"
+	"encoding/json"
+
+	cfgcst "github.com/nabbar/golib/config/const"
+)
+
+var _defaultConfig = []byte(`{
+   "client-id":"my-client",
+   "client-secret":"",
+   "auth-url":"https://auth.example.com/oauth2/authorize",
+   "token-url":"https://auth.example.com/oauth2/token",
+   "device-auth-url":"https://auth.example.com/oauth2/device_authorization",
+   "introspect-url":"https://auth.example.com/oauth2/introspect",
+   "redirect-url":"https://app.example.com/callback",
+   "scopes":["openid","offline_access"],
+   "params":{},
+   "auth-style":"auto",
+   "pkce":true,
+   "expiry-delta":"10s",
+   "store":{
+      "type":"memory",
+      "path":""
+   }
+}`)
+
+func SetDefaultConfig(cfg []byte) {
+	_defaultConfig = cfg
+}
+
+func DefaultConfig(indent string) []byte {
+	var res = bytes.NewBuffer(make([]byte, 0))
+	if err := json.Indent(res, _defaultConfig, indent, cfgcst.JSONIndent); err != nil {
+		return _defaultConfig
+	} else {
+		return res.Bytes()
+	}
+}
+
+func (o *componentOAuth) DefaultConfig(indent string) []byte {
+	return DefaultConfig(indent)
+}
@@ -0,0 +1,80 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package oauth
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	libcfg "github.com/nabbar/golib/config"
+	cfgtps "github.com/nabbar/golib/config/types"
+	libctx "github.com/nabbar/golib/context"
+	liboau "github.com/nabbar/golib/oauth"
+)
+
+type ComponentOAuth interface {
+	cfgtps.Component
+
+	GetConfig() *liboau.Config
+	SetConfig(opt *liboau.Config)
+
+	// SetStore define a custom token store (like liboau.NewStoreKV) used instead of the store defined into the config.
+	SetStore(store liboau.TokenStore)
+	// SetHTTPClient define the http client used to send requests to the authorization server.
+	SetHTTPClient(cli *http.Client)
+
+	GetOAuth() liboau.OAuth
+	SetOAuth(o liboau.OAuth)
+}
+
+func New(ctx libctx.FuncContext) ComponentOAuth {
+	var c = new(atomic.Value)
+	c.Store(&liboau.Config{})
+
+	return &componentOAuth{
+		c: c,
+		x: libctx.NewConfig[uint8](ctx),
+	}
+}
+
+func Register(cfg libcfg.Config, key string, cpt ComponentOAuth) {
+	cfg.ComponentSet(key, cpt)
+}
+
+func RegisterNew(ctx libctx.FuncContext, cfg libcfg.Config, key string) {
+	cfg.ComponentSet(key, New(ctx))
+}
+
+func Load(getCpt cfgtps.FuncCptGet, key string) ComponentOAuth {
+	if c := getCpt(key); c == nil {
+		return nil
+	} else if h, ok := c.(ComponentOAuth); !ok {
+		return nil
+	} else {
+		return h
+	}
+}
@@ -0,0 +1,124 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package oauth
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	libctx "github.com/nabbar/golib/context"
+	liboau "github.com/nabbar/golib/oauth"
+)
+
+type componentOAuth struct {
+	c *atomic.Value // config
+	x libctx.Config[uint8]
+}
+
+func (o *componentOAuth) GetConfig() *liboau.Config {
+	if i := o.c.Load(); i == nil {
+		return nil
+	} else if v, k := i.(*liboau.Config); !k {
+		return nil
+	} else if len(v.ClientID) < 1 || len(v.TokenURL) < 1 {
+		return nil
+	} else {
+		var cfg = liboau.Config{}
+		cfg = *v
+		return &cfg
+	}
+}
+
+func (o *componentOAuth) SetConfig(opt *liboau.Config) {
+	if opt == nil {
+		opt = &liboau.Config{}
+	}
+
+	o.c.Store(opt)
+}
+
+func (o *componentOAuth) GetOAuth() liboau.OAuth {
+	if i, l := o.x.Load(keyOAuth); !l {
+		return nil
+	} else if v, k := i.(liboau.OAuth); !k {
+		return nil
+	} else {
+		return v
+	}
+}
+
+func (o *componentOAuth) SetOAuth(cli liboau.OAuth) {
+	if cli == nil {
+		o.x.Delete(keyOAuth)
+	} else {
+		o.x.Store(keyOAuth, cli)
+	}
+}
+
+func (o *componentOAuth) getStore() liboau.TokenStore {
+	if i, l := o.x.Load(keyTokenStore); !l {
+		return nil
+	} else if v, k := i.(liboau.TokenStore); !k {
+		return nil
+	} else {
+		return v
+	}
+}
+
+func (o *componentOAuth) SetStore(store liboau.TokenStore) {
+	if store == nil {
+		o.x.Delete(keyTokenStore)
+	} else {
+		o.x.Store(keyTokenStore, store)
+	}
+
+	if c := o.GetOAuth(); c != nil && store != nil {
+		c.SetStore(store)
+	}
+}
+
+func (o *componentOAuth) getHTTPClient() *http.Client {
+	if i, l := o.x.Load(keyHTTPClient); !l {
+		return nil
+	} else if v, k := i.(*http.Client); !k {
+		return nil
+	} else {
+		return v
+	}
+}
+
+func (o *componentOAuth) SetHTTPClient(cli *http.Client) {
+	if cli == nil {
+		o.x.Delete(keyHTTPClient)
+	} else {
+		o.x.Store(keyHTTPClient, cli)
+	}
+
+	if c := o.GetOAuth(); c != nil {
+		c.SetHTTPClient(cli)
+	}
+}
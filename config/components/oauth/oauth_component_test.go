@@ -0,0 +1,324 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package oauth_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	cptoau "github.com/nabbar/golib/config/components/oauth"
+	cfgtps "github.com/nabbar/golib/config/types"
+	libdur "github.com/nabbar/golib/duration"
+	liboau "github.com/nabbar/golib/oauth"
+	libvpr "github.com/nabbar/golib/viper"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/oauth2"
+)
+
+const (
+	testKey          = "oauth"
+	testClientID     = "my-client"
+	testClientSecret = "my-secret"
+)
+
+// tokenServer is a token endpoint for the client credentials and the refresh token grants,
+// rotating the refresh token on each use.
+type tokenServer struct {
+	m sync.Mutex
+	s *httptest.Server
+	r string // current refresh token
+	n int    // issued tokens
+}
+
+func newTokenServer() *tokenServer {
+	o := &tokenServer{}
+
+	o.s = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		o.m.Lock()
+		defer o.m.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if u, p, k := r.BasicAuth(); !k || u != testClientID || p != testClientSecret || r.ParseForm() != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		} else if g := r.PostForm.Get("grant_type"); g == "refresh_token" && r.PostForm.Get("refresh_token") != o.r {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+
+		o.n++
+		o.r = fmt.Sprintf("refresh-%d", o.n)
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  fmt.Sprintf("access-%d", o.n),
+			"refresh_token": o.r,
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+		})
+	}))
+
+	return o
+}
+
+// SetRefresh register the given refresh token as the current one.
+func (o *tokenServer) SetRefresh(r string) {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	o.r = r
+}
+
+func (o *tokenServer) Count() int {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	return o.n
+}
+
+// countTransport count the requests sent by a http client.
+type countTransport struct {
+	n atomic.Int32
+}
+
+func (t *countTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	t.n.Add(1)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// newViper return a viper with the given oauth config under the test key
+// and the decoder hook of durations registered as done by the application.
+func newViper(cfg map[string]interface{}) libvpr.Viper {
+	v := libvpr.New(context.Background, nil)
+	v.HookRegister(libdur.ViperDecoderHook())
+	v.Viper().SetConfigType("json")
+
+	p, err := json.Marshal(map[string]interface{}{testKey: cfg})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(v.Viper().ReadConfig(bytes.NewReader(p))).To(Succeed())
+
+	return v
+}
+
+func newComponent(vpr libvpr.Viper) cptoau.ComponentOAuth {
+	cpt := cptoau.New(context.Background)
+	cpt.Init(testKey, context.Background, nil, func() libvpr.Viper {
+		return vpr
+	}, nil, nil)
+
+	return cpt
+}
+
+var _ = Describe("Config Component OAuth", func() {
+	var (
+		srv *tokenServer
+		cfg map[string]interface{}
+	)
+
+	BeforeEach(func() {
+		srv = newTokenServer()
+		DeferCleanup(srv.s.Close)
+
+		cfg = map[string]interface{}{
+			"client-id":     testClientID,
+			"client-secret": testClientSecret,
+			"token-url":     srv.s.URL + "/token",
+			"auth-style":    liboau.AuthStyleHeader,
+			"expiry-delta":  "30s",
+			"store":         map[string]interface{}{"type": liboau.StoreMemory},
+		}
+	})
+
+	Context("Default config", func() {
+		It("Must be a valid config", func() {
+			var c liboau.Config
+
+			Expect(json.Unmarshal(cptoau.DefaultConfig(""), &c)).To(Succeed())
+			Expect(c.Validate()).ToNot(HaveOccurred())
+			Expect(c.PKCE).To(BeTrue())
+			Expect(c.Store.Type).To(Equal(liboau.StoreMemory))
+		})
+	})
+
+	Context("Start, reload and stop the component", func() {
+		It("Must start with the config of viper", func() {
+			var sta, rel int
+
+			cpt := newComponent(newViper(cfg))
+			cpt.RegisterFuncStart(nil, func(c cfgtps.Component) error {
+				sta++
+				return nil
+			})
+			cpt.RegisterFuncReload(nil, func(c cfgtps.Component) error {
+				rel++
+				return nil
+			})
+
+			Expect(cpt.Type()).To(Equal(cptoau.ComponentType))
+			Expect(cpt.IsStarted()).To(BeFalse())
+			Expect(cpt.Start()).To(Succeed())
+			Expect(cpt.IsStarted()).To(BeTrue())
+			Expect(sta).To(Equal(1))
+
+			c := cpt.GetConfig()
+			Expect(c).ToNot(BeNil())
+			Expect(c.ClientID).To(Equal(testClientID))
+			Expect(c.ExpiryDelta.Time()).To(Equal(30 * time.Second))
+
+			tok, err := cpt.GetOAuth().ClientCredentials().Token()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tok.AccessToken).To(Equal("access-1"))
+
+			Expect(cpt.Reload()).To(Succeed())
+			Expect(rel).To(Equal(1))
+
+			cpt.Stop()
+			Expect(cpt.IsStarted()).To(BeFalse())
+			Expect(cpt.GetOAuth()).To(BeNil())
+		})
+
+		It("Must fail without viper or config", func() {
+			cpt := cptoau.New(context.Background)
+			Expect(cpt.Start()).To(HaveOccurred())
+			Expect(cpt.IsStarted()).To(BeFalse())
+
+			cpt = newComponent(newViper(cfg))
+			cpt.Init("missing", context.Background, nil, func() libvpr.Viper {
+				return newViper(cfg)
+			}, nil, nil)
+			Expect(cpt.Start()).To(HaveOccurred())
+		})
+
+		It("Must fail with an invalid config", func() {
+			cfg["store"] = map[string]interface{}{"type": liboau.StoreFile}
+
+			cpt := newComponent(newViper(cfg))
+			Expect(cpt.Start()).To(HaveOccurred())
+			Expect(cpt.IsStarted()).To(BeFalse())
+		})
+	})
+
+	Context("Custom store and http client", func() {
+		It("Must use the store and the client defined before the start", func() {
+			var (
+				str = liboau.NewStoreMemory()
+				trp = &countTransport{}
+				cpt = newComponent(newViper(cfg))
+			)
+
+			cpt.SetStore(str)
+			cpt.SetHTTPClient(&http.Client{Transport: trp})
+			Expect(cpt.Start()).To(Succeed())
+			Expect(cpt.GetOAuth().Store()).To(BeIdenticalTo(str))
+
+			_, err := cpt.GetOAuth().ClientCredentials().Token()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(trp.n.Load()).To(BeEquivalentTo(1))
+
+			tok, err := str.Load(liboau.KeyClientCredentials + testClientID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tok.AccessToken).To(Equal("access-1"))
+
+			Expect(cpt.Reload()).To(Succeed())
+			Expect(cpt.GetOAuth().Store()).To(BeIdenticalTo(str))
+		})
+
+		It("Must update the started client", func() {
+			var (
+				str = liboau.NewStoreMemory()
+				cpt = newComponent(newViper(cfg))
+			)
+
+			Expect(cpt.Start()).To(Succeed())
+			Expect(cpt.GetOAuth().Store()).ToNot(BeIdenticalTo(str))
+
+			cpt.SetStore(str)
+			Expect(cpt.GetOAuth().Store()).To(BeIdenticalTo(str))
+		})
+	})
+
+	Context("Persist the rotated refresh tokens", func() {
+		It("Must reload the rotated refresh token from the file store after a restart", func() {
+			var pth = filepath.Join(GinkgoT().TempDir(), "tokens.json")
+
+			cfg["store"] = map[string]interface{}{"type": liboau.StoreFile, "path": pth}
+			vpr := newViper(cfg)
+
+			cpt := newComponent(vpr)
+			Expect(cpt.Start()).To(Succeed())
+
+			srv.SetRefresh("refresh-0")
+			Expect(cpt.GetOAuth().Store().Save("user", &oauth2.Token{
+				AccessToken:  "access-0",
+				RefreshToken: "refresh-0",
+				Expiry:       time.Now().Add(-time.Hour),
+			})).To(Succeed())
+
+			src, e := cpt.GetOAuth().TokenSource("user")
+			Expect(e).ToNot(HaveOccurred())
+
+			tok, err := src.Token()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tok.RefreshToken).To(Equal("refresh-1"))
+
+			// restart the component and expire the stored token to force a new refresh
+			cpt.Stop()
+			cpt = newComponent(vpr)
+			Expect(cpt.Start()).To(Succeed())
+
+			tok, err = cpt.GetOAuth().Store().Load("user")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tok.RefreshToken).To(Equal("refresh-1"))
+
+			tok.Expiry = time.Now().Add(-time.Hour)
+			Expect(cpt.GetOAuth().Store().Save("user", tok)).To(Succeed())
+
+			src, e = cpt.GetOAuth().TokenSource("user")
+			Expect(e).ToNot(HaveOccurred())
+
+			tok, err = src.Token()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tok.AccessToken).To(Equal("access-2"))
+
+			tok, err = liboau.NewStoreFile(pth).Load("user")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tok.RefreshToken).To(Equal("refresh-2"))
+			Expect(srv.Count()).To(Equal(2))
+		})
+	})
+})
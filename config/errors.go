@@ -57,6 +57,7 @@ const (
 	MinErrorComponentRequest  = MinErrorComponentNutsDB + 10
 	MinErrorComponentSmtp     = MinErrorComponentRequest + 10
 	MinErrorComponentTls      = MinErrorComponentSmtp + 10
+	MinErrorComponentOAuth    = MinErrorComponentTls + 10
 )
 
 func init() {
@@ -0,0 +1,96 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package kvfile
+
+import (
+	"fmt"
+
+	liberr "github.com/nabbar/golib/errors"
+)
+
+const pkgName = "golib/database/kvfile"
+
+const (
+	ErrorParamEmpty liberr.CodeError = iota + liberr.MinPkgDatabaseKVFil
+	ErrorBadInstance
+	ErrorFunctionParams
+	ErrorFileOpen
+	ErrorFileRead
+	ErrorFileWrite
+	ErrorFileCorrupted
+	ErrorFileCompact
+	ErrorFileClosed
+	ErrorKeyNotFound
+	ErrorEncode
+	ErrorDecode
+	ErrorVersionConflict
+	ErrorTxDone
+)
+
+func init() {
+	if liberr.ExistInMapMessage(ErrorParamEmpty) {
+		panic(fmt.Errorf("error code collision with package %s", pkgName))
+	}
+	liberr.RegisterIdFctMessage(ErrorParamEmpty, getMessage)
+}
+
+func getMessage(code liberr.CodeError) (message string) {
+	switch code {
+	case liberr.UnknownError:
+		return liberr.NullMessage
+	case ErrorParamEmpty:
+		return "given parameters is empty"
+	case ErrorBadInstance:
+		return "bad instance of " + pkgName
+	case ErrorFunctionParams:
+		return "missing function params"
+	case ErrorFileOpen:
+		return "cannot open the storage file"
+	case ErrorFileRead:
+		return "cannot read the storage file"
+	case ErrorFileWrite:
+		return "cannot write into the storage file"
+	case ErrorFileCorrupted:
+		return "storage file contains a corrupted record"
+	case ErrorFileCompact:
+		return "cannot compact the storage file"
+	case ErrorFileClosed:
+		return "storage file is already closed"
+	case ErrorKeyNotFound:
+		return "key not found"
+	case ErrorEncode:
+		return "cannot encode the record"
+	case ErrorDecode:
+		return "cannot decode the record"
+	case ErrorVersionConflict:
+		return "key has been changed by another writer since read"
+	case ErrorTxDone:
+		return "transaction has already been committed or rolled back"
+	}
+
+	return liberr.NullMessage
+}
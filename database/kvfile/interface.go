@@ -0,0 +1,78 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package kvfile
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	libkvt "github.com/nabbar/golib/database/kvtypes"
+	liblog "github.com/nabbar/golib/logger"
+)
+
+// KVFile is an embedded KVDriver persisting its models into a single append-only file.
+// Each Set or Del appends a record at the end of the file and an in-memory index keeps
+// the offset of the last record of each key. Dead records are purged by Compact, which
+// is also triggered automatically when the dead records take more room than the live ones.
+// The driver versions each record and supports atomic transactions.
+type KVFile[K comparable, M any] interface {
+	libkvt.KVDriverCAS[K, M]
+	libkvt.KVDriverTx[K, M]
+
+	// Compact rewrites the storage file with only the live records.
+	Compact() error
+	// Close flushes and closes the storage file. The driver cannot be used anymore after.
+	Close() error
+	// SetLogger defines the logger used to report errors of the automatic compaction.
+	SetLogger(fct liblog.FuncLog)
+}
+
+// New opens or creates the storage file at the given path and rebuilds the index from its content.
+// A truncated record at the end of the file, due to a crash while writing, is dropped.
+// A corrupted record into the file is skipped and purged by the next compaction.
+func New[K comparable, M any](path string) (KVFile[K, M], error) {
+	if len(path) < 1 {
+		return nil, ErrorParamEmpty.Error(nil)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, ErrorFileOpen.Error(err)
+	}
+
+	o := &kvf[K, M]{
+		m: sync.RWMutex{},
+		p: filepath.Clean(path),
+		i: make(map[K]record),
+	}
+
+	if err := o.open(); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
@@ -0,0 +1,308 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package kvfile_test
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	libkvf "github.com/nabbar/golib/database/kvfile"
+	libkvt "github.com/nabbar/golib/database/kvtypes"
+	liberr "github.com/nabbar/golib/errors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type model struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func open(path string) libkvf.KVFile[string, model] {
+	d, e := libkvf.New[string, model](path)
+	Expect(e).ToNot(HaveOccurred())
+	Expect(d).ToNot(BeNil())
+	return d
+}
+
+func keys(d libkvf.KVFile[string, model]) []string {
+	l, e := d.List()
+	Expect(e).ToNot(HaveOccurred())
+	sort.Strings(l)
+	return l
+}
+
+func appendRaw(path, raw string) {
+	f, e := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	Expect(e).ToNot(HaveOccurred())
+	_, e = f.WriteString(raw)
+	Expect(e).ToNot(HaveOccurred())
+	Expect(f.Close()).ToNot(HaveOccurred())
+}
+
+func size(path string) int64 {
+	i, e := os.Stat(path)
+	Expect(e).ToNot(HaveOccurred())
+	return i.Size()
+}
+
+var _ = Describe("KV File", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "sub", "store.kv")
+	})
+
+	Context("with basic operations", func() {
+		It("must set, get, list, walk and delete models", func() {
+			d := open(path)
+			defer func() { _ = d.Close() }()
+
+			Expect(d.Set("a", model{Name: "alice", Age: 30})).ToNot(HaveOccurred())
+			Expect(d.Set("b", model{Name: "bob", Age: 40})).ToNot(HaveOccurred())
+			Expect(d.Set("a", model{Name: "alice", Age: 31})).ToNot(HaveOccurred())
+
+			var m model
+			Expect(d.Get("a", &m)).ToNot(HaveOccurred())
+			Expect(m).To(Equal(model{Name: "alice", Age: 31}))
+			Expect(keys(d)).To(Equal([]string{"a", "b"}))
+
+			var n int
+			Expect(d.Walk(func(key string, model model) bool {
+				n++
+				return true
+			})).ToNot(HaveOccurred())
+			Expect(n).To(Equal(2))
+
+			Expect(d.Del("a")).ToNot(HaveOccurred())
+			Expect(liberr.IsCode(d.Get("a", &m), libkvf.ErrorKeyNotFound)).To(BeTrue())
+			Expect(libkvt.IsNotFound(d.Get("a", &m))).To(BeTrue())
+			Expect(keys(d)).To(Equal([]string{"b"}))
+		})
+
+		It("must keep the models after a reopen", func() {
+			d := open(path)
+			Expect(d.Set("a", model{Name: "alice"})).ToNot(HaveOccurred())
+			Expect(d.Set("b", model{Name: "bob"})).ToNot(HaveOccurred())
+			Expect(d.Del("b")).ToNot(HaveOccurred())
+			Expect(d.Close()).ToNot(HaveOccurred())
+
+			Expect(liberr.IsCode(d.Set("c", model{}), libkvf.ErrorFileClosed)).To(BeTrue())
+
+			d = open(path)
+			defer func() { _ = d.Close() }()
+
+			var m model
+			Expect(d.Get("a", &m)).ToNot(HaveOccurred())
+			Expect(m.Name).To(Equal("alice"))
+			Expect(keys(d)).To(Equal([]string{"a"}))
+		})
+	})
+
+	Context("with a damaged storage file", func() {
+		It("must drop a torn record at the end of the file", func() {
+			d := open(path)
+			Expect(d.Set("a", model{Name: "alice"})).ToNot(HaveOccurred())
+			Expect(d.Close()).ToNot(HaveOccurred())
+
+			s := size(path)
+			appendRaw(path, `{"o":"s","n":2,"k":"b","v":{"na`)
+
+			d = open(path)
+			Expect(keys(d)).To(Equal([]string{"a"}))
+			Expect(d.Close()).ToNot(HaveOccurred())
+			Expect(size(path)).To(Equal(s))
+		})
+
+		It("must truncate a corrupted line at the end of the file", func() {
+			d := open(path)
+			Expect(d.Set("a", model{Name: "alice"})).ToNot(HaveOccurred())
+			Expect(d.Close()).ToNot(HaveOccurred())
+
+			s := size(path)
+			appendRaw(path, "\x00\x00garbage\n")
+
+			d = open(path)
+			defer func() { _ = d.Close() }()
+
+			Expect(keys(d)).To(Equal([]string{"a"}))
+			Expect(size(path)).To(Equal(s))
+
+			Expect(d.Set("b", model{Name: "bob"})).ToNot(HaveOccurred())
+			Expect(keys(d)).To(Equal([]string{"a", "b"}))
+		})
+
+		It("must skip a corrupted line into the file", func() {
+			d := open(path)
+			Expect(d.Set("a", model{Name: "alice"})).ToNot(HaveOccurred())
+			Expect(d.Close()).ToNot(HaveOccurred())
+
+			appendRaw(path, "garbage\n"+`{"o":"s","n":2,"k":"b","v":{"name":"bob","age":0}}`+"\n")
+
+			d = open(path)
+			defer func() { _ = d.Close() }()
+
+			var m model
+			Expect(d.Get("b", &m)).ToNot(HaveOccurred())
+			Expect(m.Name).To(Equal("bob"))
+			Expect(keys(d)).To(Equal([]string{"a", "b"}))
+
+			Expect(d.Compact()).ToNot(HaveOccurred())
+
+			p, e := os.ReadFile(path)
+			Expect(e).ToNot(HaveOccurred())
+			Expect(string(p)).ToNot(ContainSubstring("garbage"))
+		})
+
+		It("must drop an uncommitted transaction", func() {
+			d := open(path)
+			Expect(d.Set("a", model{Name: "alice"})).ToNot(HaveOccurred())
+			Expect(d.Close()).ToNot(HaveOccurred())
+
+			appendRaw(path, `{"o":"s","t":9,"n":2,"k":"b","v":{"name":"bob","age":0}}`+"\n")
+
+			d = open(path)
+			defer func() { _ = d.Close() }()
+
+			Expect(keys(d)).To(Equal([]string{"a"}))
+		})
+	})
+
+	Context("with the compaction", func() {
+		It("must keep only the live records", func() {
+			d := open(path)
+			defer func() { _ = d.Close() }()
+
+			for i := 0; i < 100; i++ {
+				Expect(d.Set("a", model{Name: strings.Repeat("x", 100), Age: i})).ToNot(HaveOccurred())
+			}
+
+			Expect(d.Set("b", model{Name: "bob"})).ToNot(HaveOccurred())
+			Expect(d.Del("b")).ToNot(HaveOccurred())
+
+			s := size(path)
+			Expect(d.Compact()).ToNot(HaveOccurred())
+			Expect(size(path)).To(BeNumerically("<", s/10))
+
+			var m model
+			Expect(d.Get("a", &m)).ToNot(HaveOccurred())
+			Expect(m.Age).To(Equal(99))
+			Expect(keys(d)).To(Equal([]string{"a"}))
+
+			_, e := os.Stat(path + ".compact")
+			Expect(os.IsNotExist(e)).To(BeTrue())
+		})
+
+		It("must compact automatically and keep the versions", func() {
+			d := open(path)
+
+			var (
+				m model
+				n uint64
+			)
+
+			for i := 0; i < 12000; i++ {
+				Expect(d.Set("a", model{Name: strings.Repeat("x", 100), Age: i})).ToNot(HaveOccurred())
+			}
+
+			Expect(size(path)).To(BeNumerically("<", 1024*1024))
+
+			n, e := d.GetVersion("a", &m)
+			Expect(e).ToNot(HaveOccurred())
+			Expect(m.Age).To(Equal(11999))
+			Expect(d.Close()).ToNot(HaveOccurred())
+
+			d = open(path)
+			defer func() { _ = d.Close() }()
+
+			Expect(d.Set("b", model{})).ToNot(HaveOccurred())
+			v, e := d.GetVersion("b", &m)
+			Expect(e).ToNot(HaveOccurred())
+			Expect(v).To(BeNumerically(">", n))
+		})
+	})
+
+	Context("with versions and transactions", func() {
+		It("must refuse a write with a stale version", func() {
+			d := open(path)
+			defer func() { _ = d.Close() }()
+
+			v, ok, e := d.SetVersion("a", model{Name: "first"}, 0)
+			Expect(e).ToNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			_, ok, e = d.SetVersion("a", model{Name: "other"}, 0)
+			Expect(e).ToNot(HaveOccurred())
+			Expect(ok).To(BeFalse())
+
+			_, ok, e = d.SetVersion("a", model{Name: "second"}, v)
+			Expect(e).ToNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			var m model
+			Expect(d.Get("a", &m)).ToNot(HaveOccurred())
+			Expect(m.Name).To(Equal("second"))
+		})
+
+		It("must commit all writes or none", func() {
+			d := open(path)
+			defer func() { _ = d.Close() }()
+
+			Expect(d.Set("a", model{Name: "alice"})).ToNot(HaveOccurred())
+
+			t, e := d.Begin()
+			Expect(e).ToNot(HaveOccurred())
+
+			var m model
+			Expect(t.Get("a", &m)).ToNot(HaveOccurred())
+			Expect(t.Set("b", model{Name: "bob"})).ToNot(HaveOccurred())
+			Expect(t.Del("a")).ToNot(HaveOccurred())
+			Expect(keys(d)).To(Equal([]string{"a"}))
+
+			Expect(t.Commit()).ToNot(HaveOccurred())
+			Expect(keys(d)).To(Equal([]string{"b"}))
+			Expect(liberr.IsCode(t.Commit(), libkvf.ErrorTxDone)).To(BeTrue())
+
+			t, e = d.Begin()
+			Expect(e).ToNot(HaveOccurred())
+			Expect(t.Get("b", &m)).ToNot(HaveOccurred())
+			Expect(t.Set("c", model{Name: "carol"})).ToNot(HaveOccurred())
+
+			Expect(d.Set("b", model{Name: "bobby"})).ToNot(HaveOccurred())
+			Expect(liberr.IsCode(t.Commit(), libkvf.ErrorVersionConflict)).To(BeTrue())
+			Expect(keys(d)).To(Equal([]string{"b"}))
+
+			t, e = d.Begin()
+			Expect(e).ToNot(HaveOccurred())
+			Expect(t.Set("d", model{})).ToNot(HaveOccurred())
+			Expect(t.Rollback()).ToNot(HaveOccurred())
+			Expect(keys(d)).To(Equal([]string{"b"}))
+		})
+	})
+})
@@ -0,0 +1,317 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package kvfile
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sync"
+
+	libkvt "github.com/nabbar/golib/database/kvtypes"
+	liberr "github.com/nabbar/golib/errors"
+	liblog "github.com/nabbar/golib/logger"
+	loglvl "github.com/nabbar/golib/logger/level"
+)
+
+type kvf[K comparable, M any] struct {
+	m sync.RWMutex
+	p string       // path of storage file
+	f *os.File     // storage file
+	i map[K]record // index of live records
+	s int64        // size of storage file
+	d int64        // size of dead records
+	q uint64       // last version given
+	t uint64       // last transaction id given
+	l liblog.FuncLog
+}
+
+func (o *kvf[K, M]) SetLogger(fct liblog.FuncLog) {
+	if o == nil {
+		return
+	}
+
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	o.l = fct
+}
+
+// logError is called with the lock held.
+func (o *kvf[K, M]) logError(msg string, err error) {
+	if o.l == nil {
+		return
+	} else if l := o.l(); l == nil {
+		return
+	} else {
+		l.Entry(loglvl.ErrorLevel, msg).FieldAdd("kvfile.path", o.p).ErrorAdd(true, err).Log()
+	}
+}
+
+func (o *kvf[K, M]) New() libkvt.KVDriver[K, M] {
+	// all instances share the same storage file
+	return o
+}
+
+func (o *kvf[K, M]) Get(key K, model *M) error {
+	if o == nil {
+		return ErrorBadInstance.Error(nil)
+	} else if model == nil {
+		return ErrorParamEmpty.Error(nil)
+	}
+
+	o.m.RLock()
+	defer o.m.RUnlock()
+
+	if o.f == nil {
+		return ErrorFileClosed.Error(nil)
+	} else if r, ok := o.i[key]; !ok {
+		return ErrorKeyNotFound.Error(libkvt.ErrKeyNotFound)
+	} else if l, e := o.read(r); e != nil {
+		return e
+	} else if l.V != nil {
+		*model = *l.V
+	}
+
+	return nil
+}
+
+func (o *kvf[K, M]) Set(key K, model M) error {
+	if o == nil {
+		return ErrorBadInstance.Error(nil)
+	}
+
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if o.f == nil {
+		return ErrorFileClosed.Error(nil)
+	}
+
+	return o.write(line[K, M]{
+		O: opSet,
+		N: o.q + 1,
+		K: key,
+		V: &model,
+	})
+}
+
+func (o *kvf[K, M]) Del(key K) error {
+	if o == nil {
+		return ErrorBadInstance.Error(nil)
+	}
+
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if o.f == nil {
+		return ErrorFileClosed.Error(nil)
+	} else if _, ok := o.i[key]; !ok {
+		return nil
+	}
+
+	return o.write(line[K, M]{
+		O: opDel,
+		K: key,
+	})
+}
+
+func (o *kvf[K, M]) List() ([]K, error) {
+	if o == nil {
+		return nil, ErrorBadInstance.Error(nil)
+	}
+
+	o.m.RLock()
+	defer o.m.RUnlock()
+
+	if o.f == nil {
+		return nil, ErrorFileClosed.Error(nil)
+	}
+
+	var res = make([]K, 0, len(o.i))
+
+	for k := range o.i {
+		res = append(res, k)
+	}
+
+	return res, nil
+}
+
+func (o *kvf[K, M]) Walk(fct libkvt.FctWalk[K, M]) error {
+	if o == nil {
+		return ErrorBadInstance.Error(nil)
+	} else if fct == nil {
+		return ErrorFunctionParams.Error(nil)
+	}
+
+	l, e := o.List()
+	if e != nil {
+		return e
+	}
+
+	for _, k := range l {
+		var m M
+
+		if e = o.Get(k, &m); e != nil {
+			if liberr.IsCode(e, ErrorKeyNotFound) {
+				// removed since the listing
+				continue
+			}
+			return e
+		}
+
+		if !fct(k, m) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (o *kvf[K, M]) Compact() error {
+	if o == nil {
+		return ErrorBadInstance.Error(nil)
+	}
+
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if o.f == nil {
+		return ErrorFileClosed.Error(nil)
+	}
+
+	return o.compact()
+}
+
+func (o *kvf[K, M]) autoCompact() error {
+	if !o.needCompact() {
+		return nil
+	}
+
+	return o.compact()
+}
+
+func (o *kvf[K, M]) compact() error {
+	var tmp = o.p + ".compact"
+
+	t, e := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if e != nil {
+		return ErrorFileCompact.Error(e)
+	}
+
+	defer func() {
+		_ = t.Close()
+		_ = os.Remove(tmp)
+	}()
+
+	w := bufio.NewWriter(t)
+
+	// keep the version sequence to never give again a version of a removed key
+	if p, err := o.encode(line[K, M]{O: opCmt, N: o.q}); err != nil {
+		return err
+	} else if _, e = w.Write(p); e != nil {
+		return ErrorFileCompact.Error(e)
+	}
+
+	for _, r := range o.i {
+		l, err := o.read(r)
+		if err != nil {
+			return err
+		}
+
+		// live records are committed, so the transaction id is not needed anymore
+		l.T = 0
+
+		if p, err := o.encode(l); err != nil {
+			return err
+		} else if _, e = w.Write(p); e != nil {
+			return ErrorFileCompact.Error(e)
+		}
+	}
+
+	if e = w.Flush(); e != nil {
+		return ErrorFileCompact.Error(e)
+	} else if e = t.Sync(); e != nil {
+		return ErrorFileCompact.Error(e)
+	} else if e = t.Close(); e != nil {
+		return ErrorFileCompact.Error(e)
+	} else if e = o.f.Close(); e != nil {
+		return ErrorFileCompact.Error(e)
+	}
+
+	o.f = nil
+
+	if e = os.Rename(tmp, o.p); e != nil {
+		_ = o.open()
+		return ErrorFileCompact.Error(e)
+	} else if e = syncDir(filepath.Dir(o.p)); e != nil {
+		_ = o.open()
+		return ErrorFileCompact.Error(e)
+	}
+
+	return o.open()
+}
+
+func (o *kvf[K, M]) Close() error {
+	if o == nil {
+		return ErrorBadInstance.Error(nil)
+	}
+
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if o.f == nil {
+		return nil
+	}
+
+	defer func() {
+		o.f = nil
+	}()
+
+	if e := o.f.Sync(); e != nil {
+		_ = o.f.Close()
+		return ErrorFileWrite.Error(e)
+	} else if e = o.f.Close(); e != nil {
+		return ErrorFileWrite.Error(e)
+	}
+
+	return nil
+}
+
+// syncDir flushes the directory entry, so a renamed file is durable.
+func syncDir(path string) error {
+	d, e := os.Open(path)
+	if e != nil {
+		return e
+	}
+
+	defer func() {
+		_ = d.Close()
+	}()
+
+	return d.Sync()
+}
@@ -0,0 +1,258 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package kvfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+)
+
+const (
+	opSet = "s"
+	opDel = "d"
+	opCmt = "c"
+
+	compactMinSize = 1024 * 1024
+)
+
+type record struct {
+	o int64  // offset of the record line
+	l int64  // length of the record line, including the line feed
+	n uint64 // version of the record
+}
+
+type line[K comparable, M any] struct {
+	O string `json:"o"`
+	T uint64 `json:"t,omitempty"`
+	N uint64 `json:"n,omitempty"`
+	K K      `json:"k"`
+	V *M     `json:"v,omitempty"`
+}
+
+func (o *kvf[K, M]) encode(l line[K, M]) ([]byte, error) {
+	if p, e := json.Marshal(l); e != nil {
+		return nil, ErrorEncode.Error(e)
+	} else {
+		return append(p, '\n'), nil
+	}
+}
+
+func (o *kvf[K, M]) decode(p []byte) (line[K, M], error) {
+	var l line[K, M]
+
+	if e := json.Unmarshal(bytes.TrimSpace(p), &l); e != nil {
+		return l, ErrorDecode.Error(e)
+	} else if l.O != opSet && l.O != opDel && l.O != opCmt {
+		return l, ErrorFileCorrupted.Error(nil)
+	}
+
+	return l, nil
+}
+
+func (o *kvf[K, M]) open() error {
+	f, e := os.OpenFile(o.p, os.O_RDWR|os.O_CREATE, 0600)
+
+	if e != nil {
+		return ErrorFileOpen.Error(e)
+	}
+
+	o.f = f
+	o.i = make(map[K]record)
+	o.s = 0
+	o.d = 0
+
+	if e = o.index(); e != nil {
+		_ = f.Close()
+		o.f = nil
+		return e
+	}
+
+	return nil
+}
+
+// apply updates the index with a record line written at the given offset.
+func (o *kvf[K, M]) apply(l line[K, M], r record) {
+	if l.N > o.q {
+		o.q = l.N
+	}
+
+	if l.T > o.t {
+		o.t = l.T
+	}
+
+	if l.O == opCmt {
+		o.d += r.l
+		return
+	}
+
+	if old, ok := o.i[l.K]; ok {
+		o.d += old.l
+	}
+
+	if l.O == opDel {
+		delete(o.i, l.K)
+		o.d += r.l
+	} else {
+		r.n = l.N
+		o.i[l.K] = r
+	}
+}
+
+type pending[K comparable, M any] struct {
+	l line[K, M]
+	r record
+}
+
+func (o *kvf[K, M]) index() error {
+	if _, e := o.f.Seek(0, io.SeekStart); e != nil {
+		return ErrorFileRead.Error(e)
+	}
+
+	var (
+		r   = bufio.NewReader(o.f)
+		off int64
+		end int64 // end of the last complete record or committed transaction
+		bad int64 // size of corrupted records not yet followed by a valid one
+		txn = make([]pending[K, M], 0)
+	)
+
+	for {
+		p, e := r.ReadBytes('\n')
+
+		if errors.Is(e, io.EOF) {
+			// incomplete last record, dropped to keep the file consistent
+			break
+		} else if e != nil {
+			return ErrorFileRead.Error(e)
+		}
+
+		c := record{o: off, l: int64(len(p))}
+		off += c.l
+
+		l, e := o.decode(p)
+		if e != nil {
+			// a corrupted record is skipped and counted as dead to be purged by the next compaction,
+			// if it is the last one of the file (torn write), it is truncated with the end of the file
+			bad += c.l
+			continue
+		}
+
+		if l.T == 0 {
+			o.apply(l, c)
+			end = off
+			o.d += bad
+			bad = 0
+		} else if l.O != opCmt {
+			// transaction records are applied only once the commit record is read
+			txn = append(txn, pending[K, M]{l: l, r: c})
+		} else {
+			for _, i := range txn {
+				o.apply(i.l, i.r)
+			}
+
+			txn = txn[:0]
+			o.apply(l, c)
+			end = off
+			o.d += bad
+			bad = 0
+		}
+	}
+
+	// an uncommitted transaction can only be at the end of the file and is dropped
+	if e := o.f.Truncate(end); e != nil {
+		return ErrorFileWrite.Error(e)
+	} else if _, e = o.f.Seek(end, io.SeekStart); e != nil {
+		return ErrorFileWrite.Error(e)
+	}
+
+	o.s = end
+	return nil
+}
+
+// write appends the given lines with only one write and updates the index.
+func (o *kvf[K, M]) write(lst ...line[K, M]) error {
+	var (
+		buf = make([]byte, 0)
+		rec = make([]record, 0, len(lst))
+		off = o.s
+	)
+
+	for _, l := range lst {
+		if p, e := o.encode(l); e != nil {
+			return e
+		} else {
+			rec = append(rec, record{o: off, l: int64(len(p))})
+			buf = append(buf, p...)
+			off += int64(len(p))
+		}
+	}
+
+	if _, e := o.f.WriteAt(buf, o.s); e != nil {
+		return ErrorFileWrite.Error(e)
+	} else if e = o.f.Sync(); e != nil {
+		return ErrorFileWrite.Error(e)
+	}
+
+	o.s = off
+
+	for i, l := range lst {
+		o.apply(l, rec[i])
+	}
+
+	// the records are written, so a failed compaction is only logged and will be tried again later
+	if e := o.autoCompact(); e != nil {
+		o.logError("auto compaction of storage file failed", e)
+	}
+
+	return nil
+}
+
+func (o *kvf[K, M]) version(key K) uint64 {
+	if r, ok := o.i[key]; ok {
+		return r.n
+	}
+
+	return 0
+}
+
+func (o *kvf[K, M]) read(r record) (line[K, M], error) {
+	var p = make([]byte, r.l)
+
+	if _, e := o.f.ReadAt(p, r.o); e != nil {
+		return line[K, M]{}, ErrorFileRead.Error(e)
+	}
+
+	return o.decode(p)
+}
+
+func (o *kvf[K, M]) needCompact() bool {
+	return o.d > compactMinSize && o.d > o.s-o.d
+}
@@ -0,0 +1,255 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package kvfile
+
+import (
+	"sync"
+
+	libkvt "github.com/nabbar/golib/database/kvtypes"
+)
+
+func (o *kvf[K, M]) GetVersion(key K, model *M) (uint64, error) {
+	if o == nil {
+		return 0, ErrorBadInstance.Error(nil)
+	} else if model == nil {
+		return 0, ErrorParamEmpty.Error(nil)
+	}
+
+	o.m.RLock()
+	defer o.m.RUnlock()
+
+	if o.f == nil {
+		return 0, ErrorFileClosed.Error(nil)
+	} else if r, ok := o.i[key]; !ok {
+		return 0, ErrorKeyNotFound.Error(libkvt.ErrKeyNotFound)
+	} else if l, e := o.read(r); e != nil {
+		return 0, e
+	} else {
+		if l.V != nil {
+			*model = *l.V
+		}
+		return r.n, nil
+	}
+}
+
+func (o *kvf[K, M]) SetVersion(key K, model M, version uint64) (uint64, bool, error) {
+	if o == nil {
+		return 0, false, ErrorBadInstance.Error(nil)
+	}
+
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if o.f == nil {
+		return 0, false, ErrorFileClosed.Error(nil)
+	} else if n := o.version(key); n != version {
+		return n, false, nil
+	}
+
+	n := o.q + 1
+
+	if e := o.write(line[K, M]{
+		O: opSet,
+		N: n,
+		K: key,
+		V: &model,
+	}); e != nil {
+		return 0, false, e
+	}
+
+	return n, true, nil
+}
+
+func (o *kvf[K, M]) DelVersion(key K, version uint64) (bool, error) {
+	if o == nil {
+		return false, ErrorBadInstance.Error(nil)
+	}
+
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if o.f == nil {
+		return false, ErrorFileClosed.Error(nil)
+	} else if n := o.version(key); n != version {
+		return false, nil
+	} else if n == 0 {
+		return true, nil
+	}
+
+	if e := o.write(line[K, M]{
+		O: opDel,
+		K: key,
+	}); e != nil {
+		return false, e
+	}
+
+	return true, nil
+}
+
+func (o *kvf[K, M]) Begin() (libkvt.KVTx[K, M], error) {
+	if o == nil {
+		return nil, ErrorBadInstance.Error(nil)
+	}
+
+	o.m.RLock()
+	defer o.m.RUnlock()
+
+	if o.f == nil {
+		return nil, ErrorFileClosed.Error(nil)
+	}
+
+	return &txn[K, M]{
+		d: o,
+		r: make(map[K]uint64),
+		w: make(map[K]*M),
+		o: make([]K, 0),
+	}, nil
+}
+
+type txn[K comparable, M any] struct {
+	m sync.Mutex
+	d *kvf[K, M]
+	r map[K]uint64 // version of keys read
+	w map[K]*M     // pending writes, nil for a delete
+	o []K          // order of pending writes
+	x bool         // done
+}
+
+func (t *txn[K, M]) Get(key K, model *M) error {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if t.x {
+		return ErrorTxDone.Error(nil)
+	} else if model == nil {
+		return ErrorParamEmpty.Error(nil)
+	}
+
+	if v, ok := t.w[key]; ok {
+		if v == nil {
+			return ErrorKeyNotFound.Error(libkvt.ErrKeyNotFound)
+		}
+		*model = *v
+		return nil
+	}
+
+	n, e := t.d.GetVersion(key, model)
+
+	if _, ok := t.r[key]; !ok {
+		t.r[key] = n
+	}
+
+	return e
+}
+
+func (t *txn[K, M]) put(key K, model *M) error {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if t.x {
+		return ErrorTxDone.Error(nil)
+	}
+
+	if _, ok := t.w[key]; !ok {
+		t.o = append(t.o, key)
+	}
+
+	t.w[key] = model
+	return nil
+}
+
+func (t *txn[K, M]) Set(key K, model M) error {
+	return t.put(key, &model)
+}
+
+func (t *txn[K, M]) Del(key K) error {
+	return t.put(key, nil)
+}
+
+func (t *txn[K, M]) Commit() error {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if t.x {
+		return ErrorTxDone.Error(nil)
+	}
+
+	t.x = true
+
+	if len(t.o) < 1 {
+		return nil
+	}
+
+	o := t.d
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if o.f == nil {
+		return ErrorFileClosed.Error(nil)
+	}
+
+	for k, n := range t.r {
+		if o.version(k) != n {
+			return ErrorVersionConflict.Error(nil)
+		}
+	}
+
+	var (
+		id  = o.t + 1
+		lst = make([]line[K, M], 0, len(t.o)+1)
+		seq = o.q
+	)
+
+	for _, k := range t.o {
+		if m := t.w[k]; m == nil {
+			lst = append(lst, line[K, M]{O: opDel, T: id, K: k})
+		} else {
+			seq++
+			lst = append(lst, line[K, M]{O: opSet, T: id, N: seq, K: k, V: m})
+		}
+	}
+
+	lst = append(lst, line[K, M]{O: opCmt, T: id})
+
+	return o.write(lst...)
+}
+
+func (t *txn[K, M]) Rollback() error {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if t.x {
+		return ErrorTxDone.Error(nil)
+	}
+
+	t.x = true
+	t.r = make(map[K]uint64)
+	t.w = make(map[K]*M)
+	t.o = t.o[:0]
+
+	return nil
+}
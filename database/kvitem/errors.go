@@ -39,6 +39,7 @@ const (
 	ErrorLoadFunction
 	ErrorStoreFunction
 	ErrorRemoveFunction
+	ErrorVersionConflict
 )
 
 func init() {
@@ -60,6 +61,8 @@ func getMessage(code liberr.CodeError) (message string) {
 		return "missing store function of " + pkgName
 	case ErrorRemoveFunction:
 		return "missing remove function of " + pkgName
+	case ErrorVersionConflict:
+		return "item has been changed by another writer since loaded"
 	}
 
 	return liberr.NullMessage
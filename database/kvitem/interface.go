@@ -41,6 +41,7 @@ func New[K comparable, M any](drv libkvt.KVDriver[K, M], key K) libkvt.KVItem[K,
 		fl: new(atomic.Value),
 		fs: new(atomic.Value),
 		fr: new(atomic.Value),
+		vr: new(atomic.Uint64),
 	}
 
 }
@@ -0,0 +1,227 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package kvitem_test
+
+import (
+	"path/filepath"
+	"sync"
+
+	libkvd "github.com/nabbar/golib/database/kvdriver"
+	libkvf "github.com/nabbar/golib/database/kvfile"
+	libkvi "github.com/nabbar/golib/database/kvitem"
+	libkvt "github.com/nabbar/golib/database/kvtypes"
+	liberr "github.com/nabbar/golib/errors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type model struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func newMapDriver() libkvt.KVDriver[string, model] {
+	var (
+		m = sync.Mutex{}
+		s = make(map[string]model)
+	)
+
+	return libkvd.New[string, model](nil, func(key string) (model, error) {
+		m.Lock()
+		defer m.Unlock()
+		return s[key], nil
+	}, func(key string, mod model) error {
+		m.Lock()
+		defer m.Unlock()
+		s[key] = mod
+		return nil
+	}, func(key string) error {
+		m.Lock()
+		defer m.Unlock()
+		delete(s, key)
+		return nil
+	}, func() ([]string, error) {
+		m.Lock()
+		defer m.Unlock()
+		var r = make([]string, 0, len(s))
+		for k := range s {
+			r = append(r, k)
+		}
+		return r, nil
+	}, nil)
+}
+
+var _ = Describe("KV Item", func() {
+	var drv libkvf.KVFile[string, model]
+
+	BeforeEach(func() {
+		var e error
+		drv, e = libkvf.New[string, model](filepath.Join(GinkgoT().TempDir(), "item.kv"))
+		Expect(e).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(drv.Close()).ToNot(HaveOccurred())
+	})
+
+	Context("with a versioned driver", func() {
+		It("must store again an item after a remove", func() {
+			i := libkvi.New[string, model](drv, "a")
+			i.Set(model{Name: "first"})
+			Expect(i.Store(false)).ToNot(HaveOccurred())
+
+			Expect(i.Load()).ToNot(HaveOccurred())
+			Expect(i.Remove()).ToNot(HaveOccurred())
+
+			i.Set(model{Name: "second"})
+			Expect(i.Store(false)).ToNot(HaveOccurred())
+
+			var m model
+			Expect(drv.Get("a", &m)).ToNot(HaveOccurred())
+			Expect(m.Name).To(Equal("second"))
+		})
+
+		It("must store again an item after a load of a removed key", func() {
+			i := libkvi.New[string, model](drv, "a")
+			i.Set(model{Name: "first"})
+			Expect(i.Store(false)).ToNot(HaveOccurred())
+			Expect(drv.Del("a")).ToNot(HaveOccurred())
+
+			Expect(liberr.IsCode(i.Load(), libkvf.ErrorKeyNotFound)).To(BeTrue())
+
+			i.Set(model{Name: "second"})
+			Expect(i.Store(false)).ToNot(HaveOccurred())
+		})
+
+		It("must store again an item after a clean", func() {
+			i := libkvi.New[string, model](drv, "a")
+			i.Set(model{Name: "first"})
+			Expect(i.Store(false)).ToNot(HaveOccurred())
+			Expect(drv.Del("a")).ToNot(HaveOccurred())
+
+			i.Clean()
+			i.Set(model{Name: "second"})
+			Expect(i.Store(false)).ToNot(HaveOccurred())
+		})
+
+		It("must refuse a store or a remove of an item changed since its load", func() {
+			a := libkvi.New[string, model](drv, "a")
+			b := libkvi.New[string, model](drv, "a")
+
+			a.Set(model{Name: "init"})
+			Expect(a.Store(false)).ToNot(HaveOccurred())
+
+			Expect(a.Load()).ToNot(HaveOccurred())
+			Expect(b.Load()).ToNot(HaveOccurred())
+
+			a.Set(model{Name: "by a"})
+			Expect(a.Store(false)).ToNot(HaveOccurred())
+
+			b.Set(model{Name: "by b"})
+			Expect(liberr.IsCode(b.Store(false), libkvi.ErrorVersionConflict)).To(BeTrue())
+			Expect(liberr.IsCode(b.Remove(), libkvi.ErrorVersionConflict)).To(BeTrue())
+			Expect(b.Get().Name).To(Equal("init"))
+
+			var m model
+			Expect(drv.Get("a", &m)).ToNot(HaveOccurred())
+			Expect(m.Name).To(Equal("by a"))
+
+			Expect(b.Load()).ToNot(HaveOccurred())
+			Expect(b.Get().Name).To(Equal("by a"))
+			Expect(b.Remove()).ToNot(HaveOccurred())
+
+			l, e := drv.List()
+			Expect(e).ToNot(HaveOccurred())
+			Expect(l).To(BeEmpty())
+		})
+
+		It("must not lose an update with concurrent writers", func() {
+			var (
+				w sync.WaitGroup
+				n = 20
+			)
+
+			i := libkvi.New[string, model](drv, "a")
+			i.Set(model{Name: "counter"})
+			Expect(i.Store(false)).ToNot(HaveOccurred())
+
+			for j := 0; j < n; j++ {
+				w.Add(1)
+
+				go func() {
+					defer GinkgoRecover()
+					defer w.Done()
+
+					t := libkvi.New[string, model](drv, "a")
+
+					for {
+						Expect(t.Load()).ToNot(HaveOccurred())
+
+						m := t.Get()
+						m.Count++
+						t.Set(m)
+
+						if e := t.Store(false); e == nil {
+							return
+						} else {
+							Expect(liberr.IsCode(e, libkvi.ErrorVersionConflict)).To(BeTrue())
+						}
+					}
+				}()
+			}
+
+			w.Wait()
+
+			var m model
+			Expect(drv.Get("a", &m)).ToNot(HaveOccurred())
+			Expect(m.Count).To(Equal(n))
+		})
+	})
+
+	Context("with a driver without version", func() {
+		It("must load, store and remove an item", func() {
+			d := newMapDriver()
+
+			i := libkvi.New[string, model](d, "a")
+			i.Set(model{Name: "first"})
+			Expect(i.Store(false)).ToNot(HaveOccurred())
+
+			j := libkvi.New[string, model](d, "a")
+			Expect(j.Load()).ToNot(HaveOccurred())
+			Expect(j.Get().Name).To(Equal("first"))
+
+			Expect(j.Remove()).ToNot(HaveOccurred())
+			l, e := d.List()
+			Expect(e).ToNot(HaveOccurred())
+			Expect(l).To(BeEmpty())
+
+			i.Set(model{Name: "second"})
+			Expect(i.Store(false)).ToNot(HaveOccurred())
+			Expect(d.Get("a", &model{})).ToNot(HaveOccurred())
+		})
+	})
+})
@@ -43,6 +43,8 @@ type itm[K comparable, M any] struct {
 	fl *atomic.Value
 	fs *atomic.Value
 	fr *atomic.Value
+
+	vr *atomic.Uint64 // version read, used with versioned drivers
 }
 
 func (o *itm[K, M]) getDriver() libkvt.KVDriver[K, M] {
@@ -124,6 +126,18 @@ func (o *itm[K, M]) Load() error {
 		return ErrorLoadFunction.Error(nil)
 	}
 
+	if cas, ok := drv.(libkvt.KVDriverCAS[K, M]); ok {
+		if n, e := cas.GetVersion(o.k, &mod); e != nil {
+			// the key may have been removed, so the next store must create it again
+			o.vr.Store(0)
+			return e
+		} else {
+			o.setModelLoad(mod)
+			o.vr.Store(n)
+			return nil
+		}
+	}
+
 	if e := drv.Get(o.k, &mod); e == nil {
 		o.setModelLoad(mod)
 	} else {
@@ -140,6 +154,10 @@ func (o *itm[K, M]) Store(force bool) error {
 		return ErrorStoreFunction.Error(nil)
 	}
 
+	if cas, ok := drv.(libkvt.KVDriverCAS[K, M]); ok {
+		return o.storeVersion(cas, force)
+	}
+
 	var (
 		lod M
 		str M
@@ -162,6 +180,41 @@ func (o *itm[K, M]) Store(force bool) error {
 	return nil
 }
 
+// storeVersion does not reload the item before storing it, so the write is refused
+// if the item has been changed by another writer since the last Load. The refused
+// change is dropped, so it could not overwrite the other writer's change after the next Load.
+func (o *itm[K, M]) storeVersion(cas libkvt.KVDriverCAS[K, M], force bool) error {
+	var (
+		lod M
+		str M
+	)
+
+	str = o.getModelStore()
+	if reflect.DeepEqual(lod, str) {
+		str = o.getModelLoad()
+	}
+
+	lod = o.getModelLoad()
+	if reflect.DeepEqual(lod, str) && !force {
+		return nil
+	}
+
+	if n, ok, e := cas.SetVersion(o.k, str, o.vr.Load()); e != nil {
+		return e
+	} else if !ok {
+		var tmp M
+		o.Set(tmp)
+		return ErrorVersionConflict.Error(nil)
+	} else {
+		o.setModelLoad(str)
+		o.vr.Store(n)
+	}
+
+	return nil
+}
+
+// Remove deletes the item. With a versioned driver, an item loaded before
+// is removed only if it has not been changed by another writer since the last Load.
 func (o *itm[K, M]) Remove() error {
 	drv := o.getDriver()
 
@@ -169,13 +222,25 @@ func (o *itm[K, M]) Remove() error {
 		return ErrorStoreFunction.Error(nil)
 	}
 
-	return drv.Del(o.k)
+	if cas, ok := drv.(libkvt.KVDriverCAS[K, M]); ok && o.vr.Load() > 0 {
+		if ok, e := cas.DelVersion(o.k, o.vr.Load()); e != nil {
+			return e
+		} else if !ok {
+			return ErrorVersionConflict.Error(nil)
+		}
+	} else if e := drv.Del(o.k); e != nil {
+		return e
+	}
+
+	o.vr.Store(0)
+	return nil
 }
 
 func (o *itm[K, M]) Clean() {
 	var tmp M
 	o.setModelLoad(tmp)
 	o.Set(tmp)
+	o.vr.Store(0)
 }
 
 func (o *itm[K, M]) HasChange() bool {
@@ -0,0 +1,52 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package kvtypes
+
+import (
+	"errors"
+	"io/fs"
+
+	liberr "github.com/nabbar/golib/errors"
+)
+
+// ErrKeyNotFound is returned by the drivers, directly or as parent of their own error,
+// when the key is not stored. Drivers using custom funcs (kvdriver, kvmap) should return it too.
+var ErrKeyNotFound = errors.New("key not found")
+
+// IsNotFound returns true if the given error reports a key not stored by the driver :
+// ErrKeyNotFound, wrapped or as parent of a liberr.Error, or an error matching fs.ErrNotExist.
+func IsNotFound(e error) bool {
+	if e == nil {
+		return false
+	} else if errors.Is(e, ErrKeyNotFound) || errors.Is(e, fs.ErrNotExist) {
+		return true
+	} else if err := liberr.Get(e); err != nil {
+		return err.HasError(ErrKeyNotFound)
+	}
+
+	return false
+}
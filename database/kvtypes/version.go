@@ -0,0 +1,60 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package kvtypes
+
+// KVDriverCAS is an optional interface of KVDriver for drivers able to version their models.
+// The version is changed by the driver at each write of a key. A zero version means the key does not exist.
+type KVDriverCAS[K comparable, M any] interface {
+	KVDriver[K, M]
+
+	// GetVersion loads the model and returns its current version.
+	GetVersion(key K, model *M) (version uint64, err error)
+	// SetVersion stores the model only if the current version of the key is still the given version.
+	// The swapped result is false if the key has been changed since, the new version is returned otherwise.
+	SetVersion(key K, model M, version uint64) (newVersion uint64, swapped bool, err error)
+	// DelVersion removes the key only if its current version is still the given version.
+	// The deleted result is false if the key has been changed since.
+	DelVersion(key K, version uint64) (deleted bool, err error)
+}
+
+// KVTx is a transaction over a KVDriverTx. Writes are not visible until commit.
+// The commit fails if a key read in the transaction has been changed since by another writer.
+type KVTx[K comparable, M any] interface {
+	Get(key K, model *M) error
+	Set(key K, model M) error
+	Del(key K) error
+
+	Commit() error
+	Rollback() error
+}
+
+// KVDriverTx is an optional interface of KVDriver for drivers able to apply several writes atomically.
+type KVDriverTx[K comparable, M any] interface {
+	KVDriver[K, M]
+
+	Begin() (KVTx[K, M], error)
+}
@@ -36,6 +36,9 @@ import (
 
 var idMsgFct = make(map[CodeError]Message)
 
+// idMsgDup store the message functions replaced by another registration with the same minimal code.
+var idMsgDup = make(map[CodeError][]Message)
+
 type Message func(code CodeError) (message string)
 type CodeError uint16
 
@@ -81,27 +84,31 @@ func GetCodePackages(rootPackage string) map[CodeError]string {
 	var res = make(map[CodeError]string)
 
 	for i, f := range idMsgFct {
-		p := reflect.ValueOf(f).Pointer()
-		n, _ := runtime.FuncForPC(p).FileLine(p)
+		res[i] = getFctSource(f, rootPackage)
+	}
 
-		if strings.Contains(n, "/vendor/") {
-			a := strings.SplitN(n, "/vendor/", 2)
-			n = a[1]
-		}
+	return res
+}
 
-		if strings.Contains(n, rootPackage) {
-			a := strings.SplitN(n, rootPackage, 2)
-			n = a[1]
-		}
+func getFctSource(f Message, rootPackage string) string {
+	p := reflect.ValueOf(f).Pointer()
+	n, _ := runtime.FuncForPC(p).FileLine(p)
 
-		if !strings.HasPrefix(n, "/") {
-			n = "/" + n
-		}
+	if strings.Contains(n, "/vendor/") {
+		a := strings.SplitN(n, "/vendor/", 2)
+		n = a[1]
+	}
 
-		res[i] = n
+	if len(rootPackage) > 0 && strings.Contains(n, rootPackage) {
+		a := strings.SplitN(n, rootPackage, 2)
+		n = a[1]
 	}
 
-	return res
+	if !strings.HasPrefix(n, "/") {
+		n = "/" + n
+	}
+
+	return n
 }
 
 func RegisterIdFctMessage(minCode CodeError, fct Message) {
@@ -109,6 +116,10 @@ func RegisterIdFctMessage(minCode CodeError, fct Message) {
 		idMsgFct = make(map[CodeError]Message)
 	}
 
+	if f, ok := idMsgFct[minCode]; ok && f != nil && fct != nil && reflect.ValueOf(f).Pointer() != reflect.ValueOf(fct).Pointer() {
+		idMsgDup[minCode] = append(idMsgDup[minCode], f)
+	}
+
 	idMsgFct[minCode] = fct
 	orderMapMessage()
 }
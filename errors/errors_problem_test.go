@@ -0,0 +1,150 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+
+package errors_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+	liberr "github.com/nabbar/golib/errors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func problemContext() (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/some/path", nil)
+
+	return c, w
+}
+
+func problemBody(w *httptest.ResponseRecorder) liberr.ProblemReturn {
+	var r liberr.ProblemReturn
+
+	Expect(w.Header().Get("Content-Type")).To(Equal(liberr.ContentTypeProblemJSON))
+	Expect(json.Unmarshal(w.Body.Bytes(), &r)).ToNot(HaveOccurred())
+
+	return r
+}
+
+var _ = Describe("Errors Problem Details", func() {
+	var err liberr.Error
+
+	BeforeEach(func() {
+		err = liberr.New(60100, "main error", liberr.New(60101, "parent error"))
+	})
+
+	AfterEach(func() {
+		liberr.SetDefaultReturnGin(nil)
+		liberr.SetProblemType(nil)
+	})
+
+	Context("Render an error as problem details", func() {
+		It("Must write the problem+json body with the default type", func() {
+			c, w := problemContext()
+
+			r := liberr.NewProblemReturn()
+			err.Return(r)
+			r.GinTonicErrorAbort(c, http.StatusNotFound)
+
+			Expect(c.IsAborted()).To(BeTrue())
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+			Expect(problemBody(w)).To(Equal(liberr.ProblemReturn{
+				Type:     "urn:golib:error:60100",
+				Title:    "main error",
+				Status:   http.StatusNotFound,
+				Detail:   "parent error",
+				Instance: "/some/path",
+				Code:     60100,
+				Errors: []liberr.ProblemError{
+					{Type: "urn:golib:error:60101", Code: 60101, Message: "parent error"},
+				},
+			}))
+		})
+
+		It("Must use the status text as title and internal server error by default", func() {
+			c, w := problemContext()
+
+			liberr.NewProblemReturn().GinTonicAbort(c, 0)
+
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+			r := problemBody(w)
+			Expect(r.Type).To(Equal("about:blank"))
+			Expect(r.Title).To(Equal(http.StatusText(http.StatusInternalServerError)))
+			Expect(r.Status).To(Equal(http.StatusInternalServerError))
+		})
+
+		It("Must use the type function given to SetProblemType", func() {
+			liberr.SetProblemType(func(code liberr.CodeError) string {
+				return "https://example.com/errors/" + code.GetString()
+			})
+
+			c, w := problemContext()
+
+			r := liberr.NewProblemReturn()
+			err.Return(r)
+			r.GinTonicAbort(c, http.StatusBadRequest)
+
+			p := problemBody(w)
+			Expect(p.Type).To(Equal("https://example.com/errors/60100"))
+			Expect(p.Errors).To(HaveLen(1))
+			Expect(p.Errors[0].Type).To(Equal("https://example.com/errors/60101"))
+		})
+	})
+
+	Context("Select the ReturnGin implementation globally", func() {
+		It("Must return a DefaultReturn if not defined", func() {
+			Expect(liberr.NewReturnGin()).To(BeAssignableToTypeOf(&liberr.DefaultReturn{}))
+		})
+
+		It("Must return the implementation given to SetDefaultReturnGin", func() {
+			liberr.SetDefaultReturnGin(func() liberr.ReturnGin {
+				return liberr.NewProblemReturn()
+			})
+
+			c, w := problemContext()
+
+			r := liberr.NewReturnGin()
+			Expect(r).To(BeAssignableToTypeOf(&liberr.ProblemReturn{}))
+
+			err.Return(r)
+			r.GinTonicErrorAbort(c, http.StatusConflict)
+
+			Expect(w.Code).To(Equal(http.StatusConflict))
+			Expect(problemBody(w).Title).To(Equal("main error"))
+
+			liberr.SetDefaultReturnGin(nil)
+			Expect(liberr.NewReturnGin()).To(BeAssignableToTypeOf(&liberr.DefaultReturn{}))
+		})
+	})
+})
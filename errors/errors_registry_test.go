@@ -0,0 +1,215 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+
+package errors_test
+
+import (
+	"encoding/json"
+	"fmt"
+
+	liberr "github.com/nabbar/golib/errors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	testPkg     = "github.com/nabbar/golib/errors_test"
+	testMinCode = liberr.CodeError(60000)
+)
+
+type codeTester []string
+
+func (t *codeTester) Errorf(format string, args ...interface{}) {
+	*t = append(*t, fmt.Sprintf(format, args...))
+}
+
+// msgRangeA define two codes and a default message for all other codes.
+func msgRangeA(code liberr.CodeError) string {
+	switch code {
+	case testMinCode:
+		return "first error of A"
+	case testMinCode + 1:
+		return "second error | of A"
+	default:
+		return "default message of A"
+	}
+}
+
+func msgRangeB(code liberr.CodeError) string {
+	switch code {
+	case testMinCode + 10:
+		return "first error of B"
+	case testMinCode + 11:
+		return "second error of B"
+	}
+
+	return liberr.NullMessage
+}
+
+// msgRangeC define a code owned by the range D.
+func msgRangeC(code liberr.CodeError) string {
+	switch code {
+	case testMinCode + 20:
+		return "first error of C"
+	case testMinCode + 30:
+		return "error of C into D"
+	}
+
+	return liberr.NullMessage
+}
+
+func msgRangeD(code liberr.CodeError) string {
+	if code == testMinCode+30 {
+		return "first error of D"
+	}
+
+	return liberr.NullMessage
+}
+
+func msgRangeE(code liberr.CodeError) string {
+	if code == testMinCode+40 {
+		return "first error of E"
+	}
+
+	return liberr.NullMessage
+}
+
+func msgRangeF(code liberr.CodeError) string {
+	if code == testMinCode+40 {
+		return "first error of F"
+	}
+
+	return liberr.NullMessage
+}
+
+func findRange(lst []liberr.CodeRange, min liberr.CodeError) *liberr.CodeRange {
+	for i := range lst {
+		if lst[i].Min == min {
+			return &lst[i]
+		}
+	}
+
+	return nil
+}
+
+func findCollision(lst []liberr.CodeCollision, code liberr.CodeError) *liberr.CodeCollision {
+	for i := range lst {
+		if lst[i].Code == code {
+			return &lst[i]
+		}
+	}
+
+	return nil
+}
+
+var _ = Describe("Errors Registry", func() {
+	BeforeEach(func() {
+		liberr.RegisterIdFctMessage(testMinCode, msgRangeA)
+		liberr.RegisterIdFctMessage(testMinCode+10, msgRangeB)
+	})
+
+	Context("List the registered code ranges", func() {
+		It("Must stop each range at the next registration and ignore the default message", func() {
+			lst := liberr.GetCodeRanges("")
+
+			a := findRange(lst, testMinCode)
+			Expect(a).ToNot(BeNil())
+			Expect(a.Max).To(Equal(testMinCode + 1))
+			Expect(a.Package).To(Equal(testPkg))
+			Expect(a.Codes).To(Equal([]liberr.CodeMessage{
+				{Code: testMinCode, Message: "first error of A"},
+				{Code: testMinCode + 1, Message: "second error | of A"},
+			}))
+
+			b := findRange(lst, testMinCode+10)
+			Expect(b).ToNot(BeNil())
+			Expect(b.Max).To(Equal(testMinCode + 11))
+			Expect(b.Codes).To(HaveLen(2))
+		})
+
+		It("Must remove the root package from the import path", func() {
+			a := findRange(liberr.GetCodeRanges("github.com/nabbar/golib"), testMinCode)
+			Expect(a).ToNot(BeNil())
+			Expect(a.Package).To(Equal("/errors_test"))
+		})
+	})
+
+	Context("Export the registered code ranges", func() {
+		It("Must export the ranges as JSON", func() {
+			p, e := liberr.ExportCodeJSON("")
+			Expect(e).ToNot(HaveOccurred())
+
+			var lst []liberr.CodeRange
+			Expect(json.Unmarshal(p, &lst)).ToNot(HaveOccurred())
+			Expect(lst).To(Equal(liberr.GetCodeRanges("")))
+
+			a := findRange(lst, testMinCode)
+			Expect(a).ToNot(BeNil())
+			Expect(a.Codes).To(HaveLen(2))
+		})
+
+		It("Must export the ranges as Markdown", func() {
+			p := string(liberr.ExportCodeMarkdown("github.com/nabbar/golib"))
+
+			Expect(p).To(HavePrefix("# Error codes\n"))
+			Expect(p).To(ContainSubstring("\n## /errors_test (60000 - 60001)\n\n| Code | Message |\n|-----:|---------|\n"))
+			Expect(p).To(ContainSubstring("| 60001 | second error \\| of A |\n"))
+			Expect(p).To(ContainSubstring("| 60011 | second error of B |\n"))
+			Expect(p).ToNot(ContainSubstring("default message of A"))
+		})
+	})
+
+	Context("Check the code collisions", func() {
+		It("Must not report a collision for distinct ranges", func() {
+			Expect(findCollision(liberr.CheckCodeCollision(), testMinCode+10)).To(BeNil())
+		})
+
+		It("Must report a range defining a code of the next range", func() {
+			liberr.RegisterIdFctMessage(testMinCode+20, msgRangeC)
+			liberr.RegisterIdFctMessage(testMinCode+30, msgRangeD)
+
+			c := findCollision(liberr.CheckCodeCollision(), testMinCode+30)
+			Expect(c).ToNot(BeNil())
+			Expect(c.Package).To(Equal(testPkg))
+			Expect(c.Other).To(Equal(testPkg))
+
+			t := make(codeTester, 0)
+			liberr.AssertCodeCollision(&t)
+			Expect(t).To(ContainElement(c.Error()))
+		})
+
+		It("Must report two registrations with the same minimal code", func() {
+			liberr.RegisterIdFctMessage(testMinCode+40, msgRangeE)
+			liberr.RegisterIdFctMessage(testMinCode+40, msgRangeF)
+
+			c := findCollision(liberr.CheckCodeCollision(), testMinCode+40)
+			Expect(c).ToNot(BeNil())
+			Expect(c.Package).To(Equal(testPkg))
+			Expect(c.Other).To(Equal(testPkg))
+		})
+	})
+})
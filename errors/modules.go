@@ -44,6 +44,7 @@ const (
 	MinPkgDatabaseKVMap = baseSub + MinPkgDatabaseKVDrv
 	MinPkgDatabaseKVTbl = baseSub + MinPkgDatabaseKVMap
 	MinPkgDatabaseKVItm = baseSub + MinPkgDatabaseKVTbl
+	MinPkgDatabaseKVFil = baseSub + MinPkgDatabaseKVItm // 20 codes : a next sub-range must start at 2*baseSub + MinPkgDatabaseKVFil
 
 	MinPkgFileProgress     = baseInc + MinPkgDatabaseGorm
 	MinPkgFTPClient        = baseInc + MinPkgFileProgress
@@ -63,6 +64,7 @@ const (
 	MinPkgMonitor     = baseInc + MinPkgMailPooler
 	MinPkgMonitorCfg  = baseSub + MinPkgMonitor
 	MinPkgMonitorPool = baseSub + MinPkgMonitorCfg
+	MinPkgMonitorBrk  = baseSub + MinPkgMonitorPool
 
 	MinPkgNetwork   = baseInc + MinPkgMonitor
 	MinPkgNats      = baseInc + MinPkgNetwork
@@ -71,6 +73,7 @@ const (
 	MinPkgAws       = baseInc + MinPkgOAuth
 	MinPkgRequest   = baseInc + MinPkgAws
 	MinPkgRouter    = baseInc + MinPkgRequest
+	MinPkgRouterJWT = baseSub + MinPkgRouter
 	MinPkgSemaphore = baseInc + MinPkgRouter
 
 	MinPkgSMTP       = baseInc + MinPkgSemaphore
@@ -0,0 +1,173 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package errors
+
+import (
+	"encoding/json"
+	goErr "errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentTypeProblemJSON is the media type of the RFC 7807 problem details.
+const ContentTypeProblemJSON = "application/problem+json"
+
+// FuncProblemType return the type URI of the problem for the given error code.
+type FuncProblemType func(code CodeError) string
+
+// fctProblemType store the FuncProblemType given to SetProblemType.
+var fctProblemType = new(atomic.Value)
+
+// DefaultProblemType return 'about:blank' for an unknown code or an URN 'urn:golib:error:<code>'.
+func DefaultProblemType(code CodeError) string {
+	if code == UnknownError {
+		return "about:blank"
+	}
+
+	return "urn:golib:error:" + code.GetString()
+}
+
+// SetProblemType allow to change the function building the type URI of the problem from
+// the error code, as for example to an URL of the documentation of error codes.
+// Giving nil will restore the default function.
+func SetProblemType(fct FuncProblemType) {
+	if fct == nil {
+		fctProblemType.Store(FuncProblemType(DefaultProblemType))
+	} else {
+		fctProblemType.Store(fct)
+	}
+}
+
+func problemType(code CodeError) string {
+	if f, k := fctProblemType.Load().(FuncProblemType); k && f != nil {
+		return f(code)
+	}
+
+	return DefaultProblemType(code)
+}
+
+// ProblemError is an entry of the extension member 'errors' of the problem
+// details containing the parent errors.
+type ProblemError struct {
+	Type    string `json:"type"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// ProblemReturn is a Return implementation rendering the error as RFC 7807 problem details.
+// The main error define the type, title and code, the first parent error define the detail
+// and all parent errors are listed in the 'errors' extension member.
+type ProblemReturn struct {
+	Type     string         `json:"type"`
+	Title    string         `json:"title,omitempty"`
+	Status   int            `json:"status,omitempty"`
+	Detail   string         `json:"detail,omitempty"`
+	Instance string         `json:"instance,omitempty"`
+	Code     int            `json:"code,omitempty"`
+	Errors   []ProblemError `json:"errors,omitempty"`
+}
+
+func NewProblemReturn() *ProblemReturn {
+	return &ProblemReturn{
+		Type: DefaultProblemType(UnknownError),
+	}
+}
+
+func (r *ProblemReturn) SetError(code int, msg string, file string, line int) {
+	r.Type = problemType(CodeError(code))
+	r.Title = msg
+	r.Code = code
+}
+
+func (r *ProblemReturn) AddParent(code int, msg string, file string, line int) {
+	if len(r.Errors) < 1 {
+		r.Errors = make([]ProblemError, 0)
+		r.Detail = msg
+	}
+
+	r.Errors = append(r.Errors, ProblemError{
+		Type:    problemType(CodeError(code)),
+		Code:    code,
+		Message: msg,
+	})
+}
+
+func (r ProblemReturn) JSON() []byte {
+	if str, err := json.Marshal(r); err != nil {
+		return make([]byte, 0)
+	} else {
+		return str
+	}
+}
+
+func (r ProblemReturn) GinTonicAbort(ctx *gin.Context, httpCode int) {
+	if ctx == nil || ctx.IsAborted() {
+		return
+	}
+
+	if httpCode == 0 {
+		httpCode = http.StatusInternalServerError
+	}
+
+	r.Status = httpCode
+
+	if len(r.Title) < 1 {
+		r.Title = http.StatusText(httpCode)
+	}
+
+	if len(r.Instance) < 1 && ctx.Request != nil && ctx.Request.URL != nil {
+		r.Instance = ctx.Request.URL.Path
+	}
+
+	ctx.Abort()
+	ctx.Data(httpCode, ContentTypeProblemJSON, r.JSON())
+}
+
+func (r ProblemReturn) GinTonicErrorAbort(ctx *gin.Context, httpCode int) {
+	if ctx == nil || ctx.IsAborted() {
+		return
+	}
+
+	ctx.Errors = append(ctx.Errors, &gin.Error{
+		//nolint #goerr113
+		Err:  goErr.New(r.Title),
+		Type: gin.ErrorTypeAny,
+	})
+
+	for _, e := range r.Errors {
+		ctx.Errors = append(ctx.Errors, &gin.Error{
+			//nolint #goerr113
+			Err:  goErr.New(fmt.Sprintf("(%d) %s", e.Code, e.Message)),
+			Type: gin.ErrorTypeAny,
+		})
+	}
+
+	r.GinTonicAbort(ctx, httpCode)
+}
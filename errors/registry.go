@@ -0,0 +1,244 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// CodeMessage is a registered error code with its message.
+type CodeMessage struct {
+	Code    CodeError `json:"code"`
+	Message string    `json:"message"`
+}
+
+// CodeRange is the list of error codes registered with one message function.
+// Min is the minimal code given at registration and Max the last code having a message.
+type CodeRange struct {
+	Min     CodeError     `json:"min"`
+	Max     CodeError     `json:"max"`
+	Package string        `json:"package"`
+	Codes   []CodeMessage `json:"codes"`
+}
+
+// CodeCollision describe two registrations sharing the same error codes.
+type CodeCollision struct {
+	// Code is the first code shared by the two registrations.
+	Code CodeError `json:"code"`
+	// Package is the package owning the code by its registered range.
+	Package string `json:"package"`
+	// Other is the package defining a message for the same code.
+	Other string `json:"other"`
+}
+
+func (c CodeCollision) Error() string {
+	return fmt.Sprintf("error code %d of package '%s' collide with package '%s'", c.Code, c.Package, c.Other)
+}
+
+// CodeTester is the part of testing.TB used to report the code collisions.
+type CodeTester interface {
+	Errorf(format string, args ...interface{})
+}
+
+// codeRange is a CodeRange with the message function and its default message.
+type codeRange struct {
+	CodeRange
+	f Message
+	d string
+}
+
+// has checks if the message function of the range define a message for the given code.
+func (r codeRange) has(c CodeError) bool {
+	m := r.f(c)
+	return m != NullMessage && m != r.d
+}
+
+// GetCodeRanges return all registered error code ranges ordered by minimal code.
+// The rootPackage is removed from the package import path.
+func GetCodeRanges(rootPackage string) []CodeRange {
+	var (
+		lst = getCodeRanges(rootPackage)
+		res = make([]CodeRange, 0, len(lst))
+	)
+
+	for _, r := range lst {
+		res = append(res, r.CodeRange)
+	}
+
+	return res
+}
+
+// getCodeRanges list the codes of each message function, from its minimal code to
+// the minimal code of the next registration. The message returned for the unknown error
+// code is considered as the default message of the function and never define a code.
+func getCodeRanges(rootPackage string) []codeRange {
+	var (
+		key = getMapMessageKey()
+		res = make([]codeRange, 0, len(key))
+	)
+
+	for i, k := range key {
+		f := idMsgFct[k]
+
+		if f == nil {
+			continue
+		}
+
+		r := codeRange{
+			CodeRange: CodeRange{
+				Min:     k,
+				Max:     k,
+				Package: getFctPackage(f, rootPackage),
+				Codes:   make([]CodeMessage, 0),
+			},
+			f: f,
+			d: f(UnknownError),
+		}
+
+		n := math.MaxUint16 + 1
+		if i+1 < len(key) {
+			n = int(key[i+1])
+		}
+
+		for c := int(k); c < n; c++ {
+			if r.has(CodeError(c)) {
+				r.Max = CodeError(c)
+				r.Codes = append(r.Codes, CodeMessage{
+					Code:    CodeError(c),
+					Message: r.f(CodeError(c)),
+				})
+			}
+		}
+
+		res = append(res, r)
+	}
+
+	return res
+}
+
+// getFctPackage return the import path of the package defining the message function,
+// without the rootPackage prefix.
+func getFctPackage(f Message, rootPackage string) string {
+	n := runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
+
+	// the function name is the import path followed by a dot and the function name (pkg.fct, pkg.(*T).fct, pkg.init.func1, ...)
+	if i := strings.LastIndex(n, "/"); i < 0 {
+		n, _, _ = strings.Cut(n, ".")
+	} else if j := strings.Index(n[i:], "."); j >= 0 {
+		n = n[:i+j]
+	}
+
+	if strings.Contains(n, "/vendor/") {
+		a := strings.SplitN(n, "/vendor/", 2)
+		n = a[1]
+	}
+
+	if len(rootPackage) > 0 && strings.HasPrefix(n, rootPackage) && n != rootPackage {
+		n = strings.TrimPrefix(n, rootPackage)
+	}
+
+	return n
+}
+
+// ExportCodeJSON return the list of registered error code ranges as JSON.
+func ExportCodeJSON(rootPackage string) ([]byte, error) {
+	return json.MarshalIndent(GetCodeRanges(rootPackage), "", "  ")
+}
+
+// ExportCodeMarkdown return the list of registered error codes as a Markdown document,
+// with one table by package.
+func ExportCodeMarkdown(rootPackage string) []byte {
+	var buf = bytes.NewBuffer(make([]byte, 0))
+
+	buf.WriteString("# Error codes\n")
+
+	for _, r := range GetCodeRanges(rootPackage) {
+		_, _ = fmt.Fprintf(buf, "\n## %s (%d - %d)\n\n", r.Package, r.Min, r.Max)
+		buf.WriteString("| Code | Message |\n")
+		buf.WriteString("|-----:|---------|\n")
+
+		for _, c := range r.Codes {
+			_, _ = fmt.Fprintf(buf, "| %d | %s |\n", c.Code, strings.ReplaceAll(c.Message, "|", "\\|"))
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// CheckCodeCollision return the collisions between the registered error codes : a range
+// having a message for a code owned by a next range, or two registrations with the same minimal code.
+func CheckCodeCollision() []CodeCollision {
+	var (
+		res = make([]CodeCollision, 0)
+		lst = getCodeRanges("")
+	)
+
+	for _, r := range lst {
+		for _, f := range idMsgDup[r.Min] {
+			res = append(res, CodeCollision{
+				Code:    r.Min,
+				Package: r.Package,
+				Other:   getFctPackage(f, ""),
+			})
+		}
+	}
+
+	for i := 0; i < len(lst); i++ {
+		for j := i + 1; j < len(lst); j++ {
+			for _, c := range lst[j].Codes {
+				if lst[i].has(c.Code) {
+					res = append(res, CodeCollision{
+						Code:    c.Code,
+						Package: lst[j].Package,
+						Other:   lst[i].Package,
+					})
+					break
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+// AssertCodeCollision report an error on the tester for each error code collision.
+// It is intended to be called in a test of an application importing all its packages :
+//
+//	func TestErrorCodes(t *testing.T) {
+//		liberr.AssertCodeCollision(t)
+//	}
+func AssertCodeCollision(t CodeTester) {
+	for _, c := range CheckCodeCollision() {
+		t.Errorf("%s", c.Error())
+	}
+}
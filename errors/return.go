@@ -31,10 +31,36 @@ import (
 	goErr "errors"
 	"fmt"
 	"net/http"
+	"sync/atomic"
 
 	"github.com/gin-gonic/gin"
 )
 
+// FuncReturnGin return a new ReturnGin instance used to render an error into a gin context.
+type FuncReturnGin func() ReturnGin
+
+// defaultReturnGin store the FuncReturnGin given to SetDefaultReturnGin.
+var defaultReturnGin = new(atomic.Value)
+
+// SetDefaultReturnGin allow to change globally the ReturnGin implementation returned by NewReturnGin,
+// as for example NewProblemReturn to render errors as RFC 7807 problem details.
+// Giving nil will restore the DefaultReturn implementation.
+func SetDefaultReturnGin(fct FuncReturnGin) {
+	defaultReturnGin.Store(fct)
+}
+
+// NewReturnGin return a new instance of the ReturnGin implementation defined with SetDefaultReturnGin
+// or a DefaultReturn if not defined.
+func NewReturnGin() ReturnGin {
+	if f, k := defaultReturnGin.Load().(FuncReturnGin); !k || f == nil {
+		return NewDefaultReturn()
+	} else if r := f(); r == nil {
+		return NewDefaultReturn()
+	} else {
+		return r
+	}
+}
+
 type DefaultReturn struct {
 	Code    string
 	Message string
@@ -51,6 +51,13 @@ const (
 	ErrorLDAPAttributeEmpty
 	ErrorLDAPValidatorError
 	ErrorLDAPGroupNotFound
+	ErrorLDAPPool
+	ErrorLDAPAdd
+	ErrorLDAPModify
+	ErrorLDAPDelete
+	ErrorLDAPModifyDN
+	ErrorLDAPPasswordModify
+	ErrorParamInvalid
 )
 
 func init() {
@@ -98,6 +105,20 @@ func getMessage(code liberr.CodeError) (message string) {
 		return "invalid validation config"
 	case ErrorLDAPGroupNotFound:
 		return "group not found"
+	case ErrorLDAPPool:
+		return "cannot get a connection from the pool"
+	case ErrorLDAPAdd:
+		return "error on adding entry on connected server"
+	case ErrorLDAPModify:
+		return "error on modifying entry on connected server"
+	case ErrorLDAPDelete:
+		return "error on deleting entry on connected server"
+	case ErrorLDAPModifyDN:
+		return "error on renaming or moving entry on connected server"
+	case ErrorLDAPPasswordModify:
+		return "error on modifying password on connected server"
+	case ErrorParamInvalid:
+		return "at least one given parameters is invalid"
 	}
 
 	return liberr.NullMessage
@@ -0,0 +1,355 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package ldap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	libcch "github.com/nabbar/golib/cache"
+	liberr "github.com/nabbar/golib/errors"
+	loglvl "github.com/nabbar/golib/logger/level"
+)
+
+type NestedGroupMode uint8
+
+const (
+	//NestedGroupNone use only the direct memberOf values of the user (default).
+	NestedGroupNone NestedGroupMode = iota
+	//NestedGroupAuto use the LDAP_MATCHING_RULE_IN_CHAIN if the server support it, otherwise the iterative expansion.
+	NestedGroupAuto
+	//NestedGroupInChain use the LDAP_MATCHING_RULE_IN_CHAIN of Active Directory.
+	NestedGroupInChain
+	//NestedGroupIterative expand the groups level by level with cycle detection.
+	NestedGroupIterative
+)
+
+const (
+	// oidMatchingRuleInChain is the OID of LDAP_MATCHING_RULE_IN_CHAIN (Active Directory).
+	oidMatchingRuleInChain = "1.2.840.113556.1.4.1941"
+
+	defaultNestedMaxDepth = 10
+	groupFieldMember      = "member"
+	userFieldMemberOf     = "memberOf"
+)
+
+func (m NestedGroupMode) String() string {
+	switch m {
+	case NestedGroupAuto:
+		return "auto"
+	case NestedGroupInChain:
+		return "in-chain"
+	case NestedGroupIterative:
+		return "iterative"
+	default:
+		return "none"
+	}
+}
+
+// SetNestedGroups define how the groups of a user are resolved by UserMemberOf, UserMemberOfDN and UserIsInGroup.
+// The maxDepth is the maximum level of nested groups for the iterative expansion (zero for default 10).
+// The group cache is flushed as the groups kept in cache may have been resolved with the previous mode.
+func (lc *HelperLDAP) SetNestedGroups(mode NestedGroupMode, maxDepth int) {
+	if maxDepth < 1 {
+		maxDepth = defaultNestedMaxDepth
+	}
+
+	lc.gm.Lock()
+	defer lc.gm.Unlock()
+
+	lc.nested = mode
+	lc.nestedDepth = maxDepth
+
+	if lc.groups != nil {
+		lc.groups.Clean()
+	}
+}
+
+func (lc *HelperLDAP) getNestedGroups() (NestedGroupMode, int) {
+	lc.gm.RLock()
+	defer lc.gm.RUnlock()
+
+	return lc.nested, lc.nestedDepth
+}
+
+func (lc *HelperLDAP) getGroupCache() libcch.Cache[string] {
+	lc.gm.RLock()
+	defer lc.gm.RUnlock()
+
+	return lc.groups
+}
+
+// closeGroupCache stop the current cache by cancelling its context, so it could not block
+// and could be called several times. The lock must be held by the caller.
+func (lc *HelperLDAP) closeGroupCache() {
+	if lc.groupsStop != nil {
+		lc.groupsStop()
+	}
+
+	lc.groups = nil
+	lc.groupsStop = nil
+	lc.groupsTTL = 0
+}
+
+// EnableGroupCache keep in cache the groups of each user for the given duration.
+// Each clone of the helper get its own cache, so a flush or a disable does not apply to the other clones.
+func (lc *HelperLDAP) EnableGroupCache(ttl time.Duration) liberr.Error {
+	if lc == nil {
+		return ErrorParamEmpty.Error(nil)
+	}
+
+	var par = lc.ctx
+	if par == nil {
+		par = context.Background()
+	}
+
+	var ctx, cnl = context.WithCancel(par)
+
+	if c := libcch.New[string](ctx, ttl); c == nil {
+		cnl()
+		return ErrorParamEmpty.Error(nil)
+	} else {
+		lc.gm.Lock()
+		defer lc.gm.Unlock()
+
+		lc.closeGroupCache()
+		lc.groups = c
+		lc.groupsStop = cnl
+		lc.groupsTTL = ttl
+	}
+
+	return nil
+}
+
+// DisableGroupCache remove the cache of the groups of users.
+func (lc *HelperLDAP) DisableGroupCache() {
+	if lc == nil {
+		return
+	}
+
+	lc.gm.Lock()
+	defer lc.gm.Unlock()
+
+	lc.closeGroupCache()
+}
+
+// FlushGroupCache remove the groups of the given users from the cache, or all groups if no user is given.
+func (lc *HelperLDAP) FlushGroupCache(username ...string) {
+	var c libcch.Cache[string]
+
+	if lc == nil {
+		return
+	} else if c = lc.getGroupCache(); c == nil {
+		return
+	} else if len(username) < 1 {
+		c.Clean()
+		return
+	}
+
+	for _, u := range username {
+		c.Delete(strings.ToLower(strings.TrimSpace(u)))
+	}
+}
+
+// UserMemberOfDN returns the DN list of the groups of a given user, including nested groups if enabled.
+func (lc *HelperLDAP) UserMemberOfDN(username string) ([]string, liberr.Error) {
+	var (
+		err liberr.Error
+		cch libcch.Cache[string]
+	)
+
+	if username, err = lc.getUserName(username); err != nil {
+		return nil, err
+	}
+
+	if cch = lc.getGroupCache(); cch == nil {
+		return lc.userGroups(username)
+	}
+
+	i, _, e := cch.GetOrLoad(strings.ToLower(username), func(key any) (interface{}, error) {
+		if r, er := lc.userGroups(username); er != nil {
+			return nil, er
+		} else {
+			return r, nil
+		}
+	})
+
+	if e != nil {
+		if er, ok := e.(liberr.Error); ok {
+			return nil, er
+		}
+
+		return nil, ErrorLDAPSearch.Error(e)
+	} else if r, ok := i.([]string); ok {
+		return append(make([]string, 0, len(r)), r...), nil
+	}
+
+	return make([]string, 0), nil
+}
+
+func (lc *HelperLDAP) userGroups(username string) ([]string, liberr.Error) {
+	con, rel, e := lc.getConn()
+	if e != nil {
+		return nil, e
+	}
+
+	var err error
+
+	defer func() {
+		rel(err)
+	}()
+
+	src, err := lc.searchConn(con, lc.config.Basedn, ldap.ScopeWholeSubtree, fmt.Sprintf(lc.config.FilterUser, userFieldUid, ldap.EscapeFilter(username)), []string{userFieldMemberOf})
+	if err != nil {
+		return nil, ErrorLDAPSearch.Error(err)
+	}
+
+	var (
+		usr = make([]string, 0, len(src.Entries))
+		dir = make([]string, 0)
+		res []string
+	)
+
+	for _, e := range src.Entries {
+		usr = append(usr, e.DN)
+		dir = append(dir, e.GetAttributeValues(userFieldMemberOf)...)
+	}
+
+	var mod, max = lc.getNestedGroups()
+
+	switch mod {
+	case NestedGroupInChain:
+		res, err = lc.groupsInChain(con, usr)
+	case NestedGroupIterative:
+		res, err = lc.groupsIterative(con, dir, max)
+	case NestedGroupAuto:
+		// a server not supporting the matching rule return an error or no group
+		if res, err = lc.groupsInChain(con, usr); err != nil || !containsAllDN(res, dir) {
+			res, err = lc.groupsIterative(con, dir, max)
+		}
+	default:
+		res = dir
+	}
+
+	if err != nil {
+		return nil, ErrorLDAPSearch.Error(err)
+	}
+
+	lc.getLogEntry(loglvl.DebugLevel, "ldap user group list success").FieldAdd("ldap.user", username).FieldAdd("ldap.nested", mod.String()).FieldAdd("ldap.grouplist", res).Log()
+	return res, nil
+}
+
+func (lc *HelperLDAP) searchConn(con *ldap.Conn, base string, scope int, filter string, attributes []string) (*ldap.SearchResult, error) {
+	return con.Search(ldap.NewSearchRequest(base, scope, ldap.NeverDerefAliases, 0, 0, false, filter, attributes, nil))
+}
+
+// groupsInChain return all groups of the users DN with one search by user using LDAP_MATCHING_RULE_IN_CHAIN.
+func (lc *HelperLDAP) groupsInChain(con *ldap.Conn, userDN []string) ([]string, error) {
+	var res = make([]string, 0)
+
+	for _, u := range userDN {
+		src, err := lc.searchConn(con, lc.config.Basedn, ldap.ScopeWholeSubtree, fmt.Sprintf("(%s:%s:=%s)", groupFieldMember, oidMatchingRuleInChain, ldap.EscapeFilter(u)), []string{"1.1"})
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range src.Entries {
+			if !containsAllDN(res, []string{e.DN}) {
+				res = append(res, e.DN)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// groupsIterative expand the given groups with their parent groups level by level, until the given max depth.
+// A group already seen is not expanded again, so a cycle into the groups does not loop.
+func (lc *HelperLDAP) groupsIterative(con *ldap.Conn, direct []string, max int) ([]string, error) {
+	var (
+		res = make([]string, 0, len(direct))
+		see = make(map[string]bool)
+		cur = direct
+	)
+
+	if max < 1 {
+		max = defaultNestedMaxDepth
+	}
+
+	for lvl := 0; len(cur) > 0 && lvl <= max; lvl++ {
+		var nxt = make([]string, 0)
+
+		for _, dn := range cur {
+			if k := strings.ToLower(dn); see[k] {
+				continue
+			} else {
+				see[k] = true
+			}
+
+			res = append(res, dn)
+
+			if lvl == max {
+				continue
+			}
+
+			src, err := lc.searchConn(con, lc.config.Basedn, ldap.ScopeWholeSubtree, fmt.Sprintf(lc.config.FilterGroup, groupFieldMember, ldap.EscapeFilter(dn)), []string{"1.1"})
+			if err != nil {
+				return nil, err
+			}
+
+			for _, e := range src.Entries {
+				nxt = append(nxt, e.DN)
+			}
+		}
+
+		cur = nxt
+	}
+
+	return res, nil
+}
+
+func containsAllDN(list, sub []string) bool {
+	for _, s := range sub {
+		var found bool
+
+		for _, l := range list {
+			if strings.EqualFold(s, l) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
@@ -31,8 +31,11 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-ldap/ldap/v3"
+	libcch "github.com/nabbar/golib/cache"
 	libcrt "github.com/nabbar/golib/certificates"
 	libctx "github.com/nabbar/golib/context"
 	liberr "github.com/nabbar/golib/errors"
@@ -54,6 +57,14 @@ type HelperLDAP struct {
 	bindPass   string
 	ctx        context.Context
 	log        liblog.FuncLog
+	pool       *Pool
+
+	gm          sync.RWMutex // protect the nested groups settings and the groups cache
+	nested      NestedGroupMode
+	nestedDepth int
+	groupsTTL   time.Duration
+	groups      libcch.Cache[string]
+	groupsStop  context.CancelFunc
 }
 
 // NewLDAP build a new LDAP helper based on config struct given.
@@ -72,11 +83,16 @@ func NewLDAP(ctx context.Context, cnf *Config, attributes []string) (*HelperLDAP
 	}, nil
 }
 
+// Clone return a new helper with the same settings. If the group cache is enabled,
+// the clone get its own cache with the same duration.
 func (lc *HelperLDAP) Clone() *HelperLDAP {
 	var att = make([]string, 0)
 	copy(att, lc.Attributes)
 
-	return &HelperLDAP{
+	lc.gm.RLock()
+	defer lc.gm.RUnlock()
+
+	n := &HelperLDAP{
 		Attributes: att,
 		conn:       nil,
 		config:     lc.config.Clone(),
@@ -86,7 +102,17 @@ func (lc *HelperLDAP) Clone() *HelperLDAP {
 		bindPass:   lc.bindPass,
 		ctx:        lc.ctx,
 		log:        lc.log,
+		pool:       lc.pool,
+
+		nested:      lc.nested,
+		nestedDepth: lc.nestedDepth,
 	}
+
+	if lc.groupsTTL > 0 {
+		_ = n.EnableGroupCache(lc.groupsTTL)
+	}
+
+	return n
 }
 
 // SetLogger is used to specify the logger to be used for debug messgae
@@ -324,56 +350,67 @@ func (lc *HelperLDAP) connect() liberr.Error {
 	}
 
 	if lc.conn == nil {
-		var (
-			l   *ldap.Conn
-			err liberr.Error
-		)
+		l, err := lc.newConn()
 
-		if lc.tlsMode == _TLSModeInit {
-			m, e := lc.tryConnect()
+		if err != nil {
+			return err
+		}
 
-			if e != nil {
-				return e
-			}
+		lc.getLogEntry(loglvl.DebugLevel, "ldap connected").Log()
+		lc.conn = l
+	}
+
+	return nil
+}
+
+// newConn open a new connection to the server with the tls mode of the helper (detected if not defined).
+func (lc *HelperLDAP) newConn() (*ldap.Conn, liberr.Error) {
+	var (
+		l   *ldap.Conn
+		err liberr.Error
+	)
+
+	if lc.tlsMode == _TLSModeInit {
+		m, e := lc.tryConnect()
 
-			lc.tlsMode = m
+		if e != nil {
+			return nil, e
 		}
 
-		if lc.tlsMode == TLSModeTLS {
-			l, err = lc.dialTLS()
-			if err != nil {
-				if l != nil {
-					_ = l.Close()
-				}
-				return err
+		lc.tlsMode = m
+	}
+
+	if lc.tlsMode == TLSModeTLS {
+		l, err = lc.dialTLS()
+		if err != nil {
+			if l != nil {
+				_ = l.Close()
 			}
+			return nil, err
 		}
+	}
 
-		if lc.tlsMode == TLSModeNone || lc.tlsMode == TLSModeStarttls {
-			l, err = lc.dial()
-			if err != nil {
-				if l != nil {
-					_ = l.Close()
-				}
-				return err
+	if lc.tlsMode == TLSModeNone || lc.tlsMode == TLSModeStarttls {
+		l, err = lc.dial()
+		if err != nil {
+			if l != nil {
+				_ = l.Close()
 			}
+			return nil, err
 		}
+	}
 
-		if lc.tlsMode == TLSModeStarttls {
-			err = lc.starttls(l)
-			if err != nil {
-				if l != nil {
-					_ = l.Close()
-				}
-				return err
+	if lc.tlsMode == TLSModeStarttls {
+		err = lc.starttls(l)
+		if err != nil {
+			if l != nil {
+				_ = l.Close()
 			}
+			return nil, err
 		}
-
-		lc.getLogEntry(loglvl.DebugLevel, "ldap connected").Log()
-		lc.conn = l
 	}
 
-	return nil
+	return l, nil
 }
 
 // Check used to check if connection success (without any bind).
@@ -444,17 +481,46 @@ func (lc *HelperLDAP) Connect() liberr.Error {
 	return nil
 }
 
+// getConn return a bound connection and the func to release it after use :
+// a connection of the pool if enabled, or a new connection closed on release.
+func (lc *HelperLDAP) getConn() (*ldap.Conn, func(err error), liberr.Error) {
+	if lc == nil {
+		return nil, nil, ErrorParamEmpty.Error(nil)
+	}
+
+	if p := lc.pool; p != nil {
+		if l, e := p.Get(lc.ctx); e != nil {
+			return nil, nil, e
+		} else {
+			return l, func(err error) {
+				p.Put(l, err)
+			}, nil
+		}
+	}
+
+	if e := lc.Connect(); e != nil {
+		return nil, nil, e
+	}
+
+	return lc.conn, func(err error) {
+		lc.Close()
+	}, nil
+}
+
 func (lc *HelperLDAP) runSearch(filter string, attributes []string) (*ldap.SearchResult, liberr.Error) {
 	var (
 		err error
 		src *ldap.SearchResult
 	)
 
-	if e := lc.Connect(); e != nil {
+	con, rel, e := lc.getConn()
+	if e != nil {
 		return nil, e
 	}
 
-	defer lc.Close()
+	defer func() {
+		rel(err)
+	}()
 
 	searchRequest := ldap.NewSearchRequest(
 		lc.config.Basedn,
@@ -466,7 +532,7 @@ func (lc *HelperLDAP) runSearch(filter string, attributes []string) (*ldap.Searc
 		nil,
 	)
 
-	if src, err = lc.conn.Search(searchRequest); err != nil {
+	if src, err = con.Search(searchRequest); err != nil {
 		return nil, ErrorLDAPSearch.Error(err)
 	}
 
@@ -610,10 +676,11 @@ func (lc *HelperLDAP) GroupInfoByField(groupname string, fieldForUnicValue strin
 }
 
 // UserMemberOf returns the group list of a given user.
+// The nested groups are included if enabled with SetNestedGroups.
 func (lc *HelperLDAP) UserMemberOf(username string) ([]string, liberr.Error) {
 	var (
 		err liberr.Error
-		src *ldap.SearchResult
+		lst []string
 		grp []string
 	)
 
@@ -623,17 +690,14 @@ func (lc *HelperLDAP) UserMemberOf(username string) ([]string, liberr.Error) {
 
 	grp = make([]string, 0)
 
-	src, err = lc.runSearch(fmt.Sprintf(lc.config.FilterUser, userFieldUid, username), []string{"memberOf"})
-	if err != nil {
+	if lst, err = lc.UserMemberOfDN(username); err != nil {
 		return grp, err
 	}
 
-	for _, entry := range src.Entries {
-		for _, mmb := range entry.GetAttributeValues("memberOf") {
-			lc.getLogEntry(loglvl.DebugLevel, "ldap find user group list building").FieldAdd("ldap.user", username).FieldAdd("ldap.raw.groups", mmb).Log()
-			mmo := lc.ParseEntries(mmb)
-			grp = append(grp, mmo["cn"]...)
-		}
+	for _, mmb := range lst {
+		lc.getLogEntry(loglvl.DebugLevel, "ldap find user group list building").FieldAdd("ldap.user", username).FieldAdd("ldap.raw.groups", mmb).Log()
+		mmo := lc.ParseEntries(mmb)
+		grp = append(grp, mmo["cn"]...)
 	}
 
 	lc.getLogEntry(loglvl.DebugLevel, "ldap user group list success").FieldAdd("ldap.user", username).FieldAdd("ldap.grouplist", grp).Log()
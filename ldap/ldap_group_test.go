@@ -0,0 +1,163 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package ldap_test
+
+import (
+	"context"
+	"time"
+
+	libldp "github.com/nabbar/golib/ldap"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LDAP Group", func() {
+	var (
+		srv *testServer
+		hlp *libldp.HelperLDAP
+		g1  string
+		g2  string
+		g3  string
+	)
+
+	// groups return the DN of the groups of the given user.
+	groups := func(h *libldp.HelperLDAP, user string) []string {
+		res, e := h.UserMemberOfDN(user)
+		Expect(e).ToNot(HaveOccurred())
+		return res
+	}
+
+	BeforeEach(func() {
+		srv = newServer()
+		hlp = srv.Helper()
+
+		// user1 is member of g1, g1 of g2, g2 of g3 and g3 of g1
+		g1 = srv.AddGroup("g1", userDN("user1"), groupDN("g3"))
+		g2 = srv.AddGroup("g2", g1)
+		g3 = srv.AddGroup("g3", g2)
+
+		DeferCleanup(func() {
+			hlp.DisableGroupCache()
+			srv.Close()
+		})
+	})
+
+	Context("Resolve the groups of a user", func() {
+		It("Must use only the direct groups by default", func() {
+			Expect(groups(hlp, "user1")).To(Equal([]string{g1}))
+			Expect(groups(hlp, "user2")).To(BeEmpty())
+		})
+
+		It("Must expand the nested groups with a cycle", func() {
+			hlp.SetNestedGroups(libldp.NestedGroupIterative, 0)
+			Expect(groups(hlp, "user1")).To(Equal([]string{g1, g2, g3}))
+
+			l, e := hlp.UserMemberOf("user1")
+			Expect(e).ToNot(HaveOccurred())
+			Expect(l).To(Equal([]string{"g1", "g2", "g3"}))
+
+			ok, e := hlp.UserIsInGroup("user1", []string{"g3"})
+			Expect(e).ToNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+		})
+
+		It("Must stop the expansion at the max depth", func() {
+			hlp.SetNestedGroups(libldp.NestedGroupIterative, 1)
+			Expect(groups(hlp, "user1")).To(Equal([]string{g1, g2}))
+
+			hlp.SetNestedGroups(libldp.NestedGroupIterative, 2)
+			Expect(groups(hlp, "user1")).To(Equal([]string{g1, g2, g3}))
+		})
+
+		It("Must fall back to the iterative expansion if the in chain rule is not supported", func() {
+			hlp.SetNestedGroups(libldp.NestedGroupInChain, 0)
+			Expect(groups(hlp, "user1")).To(BeEmpty())
+
+			hlp.SetNestedGroups(libldp.NestedGroupAuto, 0)
+			Expect(groups(hlp, "user1")).To(Equal([]string{g1, g2, g3}))
+		})
+	})
+
+	Context("Cache the groups of users", func() {
+		BeforeEach(func() {
+			hlp.SetNestedGroups(libldp.NestedGroupIterative, 0)
+			Expect(hlp.EnableGroupCache(time.Minute)).ToNot(HaveOccurred())
+		})
+
+		It("Must use the cache until a flush", func() {
+			Expect(groups(hlp, "user1")).To(Equal([]string{g1, g2, g3}))
+
+			g4 := srv.AddGroup("g4", g3)
+			Expect(groups(hlp, "USER1")).To(Equal([]string{g1, g2, g3}))
+
+			hlp.FlushGroupCache("user2")
+			Expect(groups(hlp, "user1")).To(Equal([]string{g1, g2, g3}))
+
+			hlp.FlushGroupCache("User1")
+			Expect(groups(hlp, "user1")).To(Equal([]string{g1, g2, g3, g4}))
+
+			srv.AddGroup("g5", userDN("user1"))
+			hlp.FlushGroupCache()
+			Expect(groups(hlp, "user1")).To(HaveLen(5))
+		})
+
+		It("Must flush the cache when the nested mode change", func() {
+			Expect(groups(hlp, "user1")).To(Equal([]string{g1, g2, g3}))
+
+			hlp.SetNestedGroups(libldp.NestedGroupNone, 0)
+			Expect(groups(hlp, "user1")).To(Equal([]string{g1}))
+		})
+
+		It("Must give its own cache to each clone", func() {
+			cln := hlp.Clone()
+			Expect(groups(cln, "user1")).To(Equal([]string{g1, g2, g3}))
+			Expect(groups(hlp, "user1")).To(Equal([]string{g1, g2, g3}))
+
+			g4 := srv.AddGroup("g4", g3)
+			cln.FlushGroupCache()
+			Expect(groups(cln, "user1")).To(Equal([]string{g1, g2, g3, g4}))
+			Expect(groups(hlp, "user1")).To(Equal([]string{g1, g2, g3}))
+
+			// a clone disabled after its parent must not block, nor disable the cache of its parent
+			hlp.DisableGroupCache()
+			cln.DisableGroupCache()
+			cln.DisableGroupCache()
+			Expect(groups(hlp, "user1")).To(Equal([]string{g1, g2, g3, g4}))
+		})
+
+		It("Must not block when disabled after the end of its context", func() {
+			ctx, cnl := context.WithCancel(context.Background())
+
+			h, e := libldp.NewLDAP(ctx, &libldp.Config{Uri: "127.0.0.1", Basedn: testBaseDN}, libldp.GetDefaultAttributes())
+			Expect(e).ToNot(HaveOccurred())
+			Expect(h.EnableGroupCache(time.Minute)).ToNot(HaveOccurred())
+
+			cnl()
+			h.DisableGroupCache()
+			h.DisableGroupCache()
+		})
+	})
+})
@@ -0,0 +1,205 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package ldap_test
+
+import (
+	"context"
+	"time"
+
+	libdur "github.com/nabbar/golib/duration"
+	libldp "github.com/nabbar/golib/ldap"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LDAP Pool", func() {
+	var (
+		srv *testServer
+		hlp *libldp.HelperLDAP
+	)
+
+	BeforeEach(func() {
+		srv = newServer()
+		hlp = srv.Helper()
+		DeferCleanup(func() {
+			hlp.DisablePool()
+			srv.Close()
+		})
+	})
+
+	Context("Get and Put connections", func() {
+		It("Must reuse the idle connections within the limits", func() {
+			Expect(hlp.EnablePool(libldp.PoolConfig{MaxOpen: 2, MaxIdle: 1})).ToNot(HaveOccurred())
+
+			p := hlp.Pool()
+			Expect(p).ToNot(BeNil())
+
+			c1, e := p.Get(context.Background())
+			Expect(e).ToNot(HaveOccurred())
+			c2, e := p.Get(context.Background())
+			Expect(e).ToNot(HaveOccurred())
+			Expect(p.Stats()).To(Equal(libldp.PoolStats{Open: 2, Idle: 0, InUse: 2}))
+
+			ctx, cnl := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cnl()
+
+			_, e = p.Get(ctx)
+			Expect(e).To(HaveOccurred())
+			Expect(e.HasCode(libldp.ErrorLDAPPool)).To(BeTrue())
+
+			p.Put(c1, nil)
+			p.Put(c2, nil)
+			Expect(p.Stats()).To(Equal(libldp.PoolStats{Open: 1, Idle: 1, InUse: 0}))
+			Expect(c2.IsClosing()).To(BeTrue())
+
+			c3, e := p.Get(context.Background())
+			Expect(e).ToNot(HaveOccurred())
+			Expect(c3).To(BeIdenticalTo(c1))
+			Expect(srv.Accepted()).To(Equal(2))
+			p.Put(c3, nil)
+		})
+
+		It("Must wait for a connection given back", func() {
+			Expect(hlp.EnablePool(libldp.PoolConfig{MaxOpen: 1})).ToNot(HaveOccurred())
+
+			p := hlp.Pool()
+			c1, e := p.Get(context.Background())
+			Expect(e).ToNot(HaveOccurred())
+
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				p.Put(c1, nil)
+			}()
+
+			ctx, cnl := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cnl()
+
+			c2, e := p.Get(ctx)
+			Expect(e).ToNot(HaveOccurred())
+			Expect(c2).To(BeIdenticalTo(c1))
+			p.Put(c2, nil)
+		})
+
+		It("Must share the connections with the operations of the helper", func() {
+			Expect(hlp.EnablePool(libldp.PoolConfig{})).ToNot(HaveOccurred())
+
+			for i := 0; i < 3; i++ {
+				res, e := hlp.Search(libldp.SearchRequest{BaseDN: testPeopleDN, Scope: libldp.ScopeOneLevel})
+				Expect(e).ToNot(HaveOccurred())
+				Expect(res).To(HaveLen(5))
+			}
+
+			Expect(srv.Accepted()).To(Equal(1))
+			Expect(hlp.Pool().Stats()).To(Equal(libldp.PoolStats{Open: 1, Idle: 1, InUse: 0}))
+		})
+
+		It("Must fail with invalid credentials", func() {
+			hlp.SetCredentials(testAdminDN, "wrong")
+			Expect(hlp.EnablePool(libldp.PoolConfig{})).ToNot(HaveOccurred())
+
+			_, e := hlp.Pool().Get(context.Background())
+			Expect(e).To(HaveOccurred())
+			Expect(e.HasCode(libldp.ErrorLDAPBind)).To(BeTrue())
+			Expect(hlp.Pool().Stats()).To(Equal(libldp.PoolStats{}))
+		})
+
+		It("Must refuse connections once closed", func() {
+			Expect(hlp.EnablePool(libldp.PoolConfig{})).ToNot(HaveOccurred())
+
+			p := hlp.Pool()
+			c, e := p.Get(context.Background())
+			Expect(e).ToNot(HaveOccurred())
+
+			p.Close()
+			p.Put(c, nil)
+			Expect(c.IsClosing()).To(BeTrue())
+
+			_, e = p.Get(context.Background())
+			Expect(e).To(HaveOccurred())
+			Expect(p.Check()).To(HaveOccurred())
+		})
+	})
+
+	Context("Health check of idle connections", func() {
+		It("Must replace a broken connection on Get", func() {
+			Expect(hlp.EnablePool(libldp.PoolConfig{HealthCheck: libdur.ParseDuration(time.Millisecond)})).ToNot(HaveOccurred())
+
+			p := hlp.Pool()
+			c1, e := p.Get(context.Background())
+			Expect(e).ToNot(HaveOccurred())
+			p.Put(c1, nil)
+
+			srv.CloseConns()
+			time.Sleep(10 * time.Millisecond)
+
+			c2, e := p.Get(context.Background())
+			Expect(e).ToNot(HaveOccurred())
+			Expect(c2).ToNot(BeIdenticalTo(c1))
+			Expect(srv.Accepted()).To(Equal(2))
+			p.Put(c2, nil)
+
+			res, e := hlp.Search(libldp.SearchRequest{BaseDN: testPeopleDN, Scope: libldp.ScopeOneLevel})
+			Expect(e).ToNot(HaveOccurred())
+			Expect(res).To(HaveLen(5))
+		})
+
+		It("Must close the broken idle connections on Check", func() {
+			Expect(hlp.EnablePool(libldp.PoolConfig{MaxIdle: 2})).ToNot(HaveOccurred())
+
+			p := hlp.Pool()
+			c1, e := p.Get(context.Background())
+			Expect(e).ToNot(HaveOccurred())
+			c2, e := p.Get(context.Background())
+			Expect(e).ToNot(HaveOccurred())
+			p.Put(c1, nil)
+			p.Put(c2, nil)
+
+			Expect(p.Check()).ToNot(HaveOccurred())
+			Expect(p.Stats().Idle).To(Equal(2))
+
+			srv.CloseConns()
+
+			Eventually(func() int {
+				_ = p.Check()
+				return p.Stats().Idle
+			}, time.Second, 10*time.Millisecond).Should(Equal(0))
+		})
+
+		It("Must close the expired idle connections", func() {
+			Expect(hlp.EnablePool(libldp.PoolConfig{IdleTimeout: libdur.ParseDuration(time.Millisecond)})).ToNot(HaveOccurred())
+
+			p := hlp.Pool()
+			c1, e := p.Get(context.Background())
+			Expect(e).ToNot(HaveOccurred())
+			p.Put(c1, nil)
+
+			time.Sleep(5 * time.Millisecond)
+			Expect(p.Check()).ToNot(HaveOccurred())
+			Expect(p.Stats()).To(Equal(libldp.PoolStats{}))
+			Expect(c1.IsClosing()).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,177 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package ldap_test
+
+import (
+	libldp "github.com/nabbar/golib/ldap"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type testPerson struct {
+	DN   string   `ldap:"dn"`
+	UID  string   `ldap:"uid"`
+	Name string   `ldap:"cn"`
+	Mail []string `ldap:"mail"`
+}
+
+var _ = Describe("LDAP Search", func() {
+	var (
+		srv *testServer
+		hlp *libldp.HelperLDAP
+	)
+
+	BeforeEach(func() {
+		srv = newServer()
+		hlp = srv.Helper()
+		DeferCleanup(srv.Close)
+	})
+
+	Context("Search entries", func() {
+		It("Must return the entries matching the scope and the filter", func() {
+			res, e := hlp.Search(libldp.SearchRequest{Filter: "(objectClass=inetOrgPerson)"})
+			Expect(e).ToNot(HaveOccurred())
+			Expect(res).To(HaveLen(5))
+
+			res, e = hlp.Search(libldp.SearchRequest{BaseDN: testPeopleDN, Scope: libldp.ScopeOneLevel, Filter: "(uid=user3)", Attributes: []string{"uid", "cn"}})
+			Expect(e).ToNot(HaveOccurred())
+			Expect(res).To(HaveLen(1))
+			Expect(res[0].DN).To(Equal(userDN("user3")))
+			Expect(res[0].Get("uid")).To(Equal("user3"))
+			Expect(res[0].Get("cn")).To(Equal("User 3"))
+			Expect(res[0].GetAll("mail")).To(BeEmpty())
+
+			res, e = hlp.Search(libldp.SearchRequest{BaseDN: testPeopleDN, Scope: libldp.ScopeBase})
+			Expect(e).ToNot(HaveOccurred())
+			Expect(res).To(HaveLen(1))
+			Expect(res[0].Get("ou")).To(Equal("people"))
+		})
+
+		It("Must fail on an unknown base", func() {
+			_, e := hlp.Search(libldp.SearchRequest{BaseDN: "ou=unknown," + testBaseDN})
+			Expect(e).To(HaveOccurred())
+			Expect(e.HasCode(libldp.ErrorLDAPSearch)).To(BeTrue())
+		})
+
+		It("Must load all pages of a paged search", func() {
+			res, e := hlp.Search(libldp.SearchRequest{BaseDN: testPeopleDN, Scope: libldp.ScopeOneLevel, PageSize: 2})
+			Expect(e).ToNot(HaveOccurred())
+			Expect(res).To(HaveLen(5))
+			Expect(res[4].DN).To(Equal(userDN("user5")))
+			Expect(srv.Pages()).To(Equal(3))
+			Expect(srv.Abandoned()).To(BeEmpty())
+		})
+	})
+
+	Context("Walk on entries", func() {
+		It("Must refuse a nil func", func() {
+			e := hlp.SearchWalk(libldp.SearchRequest{}, nil)
+			Expect(e).To(HaveOccurred())
+			Expect(e.IsCode(libldp.ErrorParamEmpty)).To(BeTrue())
+		})
+
+		It("Must abandon the paged search stopped on the first page", func() {
+			var lst = make([]string, 0)
+
+			e := hlp.SearchWalk(libldp.SearchRequest{BaseDN: testPeopleDN, Scope: libldp.ScopeOneLevel, PageSize: 2}, func(e libldp.Entry) bool {
+				lst = append(lst, e.Get("uid"))
+				return false
+			})
+
+			Expect(e).ToNot(HaveOccurred())
+			Expect(lst).To(Equal([]string{"user1"}))
+			Expect(srv.Abandoned()).To(Equal([]string{"2"}))
+			Expect(srv.Pages()).To(Equal(2))
+		})
+
+		It("Must abandon the paged search with the cookie of the last page", func() {
+			var lst = make([]string, 0)
+
+			e := hlp.SearchWalk(libldp.SearchRequest{BaseDN: testPeopleDN, Scope: libldp.ScopeOneLevel, PageSize: 2}, func(e libldp.Entry) bool {
+				lst = append(lst, e.Get("uid"))
+				return len(lst) < 3
+			})
+
+			Expect(e).ToNot(HaveOccurred())
+			Expect(lst).To(Equal([]string{"user1", "user2", "user3"}))
+			Expect(srv.Abandoned()).To(Equal([]string{"4"}))
+			Expect(srv.Pages()).To(Equal(3))
+		})
+
+		It("Must not abandon a search stopped on the last page", func() {
+			var cnt int
+
+			e := hlp.SearchWalk(libldp.SearchRequest{BaseDN: testPeopleDN, Scope: libldp.ScopeOneLevel, PageSize: 2}, func(e libldp.Entry) bool {
+				cnt++
+				return cnt < 5
+			})
+
+			Expect(e).ToNot(HaveOccurred())
+			Expect(cnt).To(Equal(5))
+			Expect(srv.Abandoned()).To(BeEmpty())
+			Expect(srv.Pages()).To(Equal(3))
+		})
+
+		It("Must not send paging control without page size", func() {
+			var cnt int
+
+			e := hlp.SearchWalk(libldp.SearchRequest{BaseDN: testPeopleDN, Scope: libldp.ScopeOneLevel}, func(e libldp.Entry) bool {
+				cnt++
+				return false
+			})
+
+			Expect(e).ToNot(HaveOccurred())
+			Expect(cnt).To(Equal(1))
+			Expect(srv.Pages()).To(Equal(0))
+		})
+	})
+
+	Context("Search typed entries", func() {
+		It("Must decode the entries into the given type", func() {
+			res, e := libldp.SearchAs[testPerson](hlp, libldp.SearchRequest{BaseDN: testPeopleDN, Filter: "(objectClass=inetOrgPerson)", PageSize: 2})
+			Expect(e).ToNot(HaveOccurred())
+			Expect(res).To(HaveLen(5))
+			Expect(res[1]).To(Equal(testPerson{
+				DN:   userDN("user2"),
+				UID:  "user2",
+				Name: "User 2",
+				Mail: []string{"user2@example.com"},
+			}))
+		})
+
+		It("Must return an empty list without match", func() {
+			res, e := libldp.SearchAs[testPerson](hlp, libldp.SearchRequest{Filter: "(uid=nobody)"})
+			Expect(e).ToNot(HaveOccurred())
+			Expect(res).To(BeEmpty())
+		})
+
+		It("Must fail on a type not decodable", func() {
+			_, e := libldp.SearchAs[string](hlp, libldp.SearchRequest{Filter: "(uid=user1)"})
+			Expect(e).To(HaveOccurred())
+			Expect(e.HasCode(libldp.ErrorLDAPSearch)).To(BeTrue())
+		})
+	})
+})
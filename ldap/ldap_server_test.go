@@ -0,0 +1,881 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package ldap_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	libldp "github.com/nabbar/golib/ldap"
+	. "github.com/onsi/gomega"
+)
+
+// minimal LDAP server, encoding and decoding the BER messages of the operations used by the helper.
+
+const (
+	testBaseDN   = "dc=example,dc=com"
+	testPeopleDN = "ou=people," + testBaseDN
+	testGroupsDN = "ou=groups," + testBaseDN
+	testAdminDN  = "cn=admin," + testBaseDN
+	testAdminPwd = "secret"
+
+	oidPaging         = "1.2.840.113556.1.4.319"
+	oidPasswordModify = "1.3.6.1.4.1.4203.1.11.1"
+
+	berInt    = 0x02
+	berOctet  = 0x04
+	berEnum   = 0x0A
+	berSeq    = 0x30
+	berSet    = 0x31
+	berCtx0   = 0x80
+	berCtx1   = 0x81
+	berCtx2   = 0x82
+	berCtrls  = 0xA0
+	berPDV    = 0x8B
+	berMaxLen = 1 << 20
+
+	resSuccess          = 0
+	resProtocolError    = 2
+	resNoSuchObject     = 32
+	resInvalidCred      = 49
+	resNotAllowedNoLeaf = 66
+	resAlreadyExists    = 68
+)
+
+// berPacket is a decoded BER element with its children if constructed.
+type berPacket struct {
+	t byte
+	v []byte
+	c []berPacket
+}
+
+func (p berPacket) str() string {
+	return string(p.v)
+}
+
+func (p berPacket) int() int64 {
+	var i int64
+
+	for k, b := range p.v {
+		if k == 0 && b&0x80 != 0 {
+			i = -1
+		}
+
+		i = i<<8 | int64(b)
+	}
+
+	return i
+}
+
+func berLength(r io.ByteReader) (int, error) {
+	b, err := r.ReadByte()
+
+	if err != nil {
+		return 0, err
+	} else if b < 0x80 {
+		return int(b), nil
+	}
+
+	var n int
+
+	for i := 0; i < int(b&0x7F); i++ {
+		if c, e := r.ReadByte(); e != nil {
+			return 0, e
+		} else {
+			n = n<<8 | int(c)
+		}
+	}
+
+	if n > berMaxLen {
+		return 0, fmt.Errorf("ber length too large: %d", n)
+	}
+
+	return n, nil
+}
+
+func berRead(r *bufio.Reader) (berPacket, error) {
+	t, err := r.ReadByte()
+	if err != nil {
+		return berPacket{}, err
+	}
+
+	n, err := berLength(r)
+	if err != nil {
+		return berPacket{}, err
+	}
+
+	v := make([]byte, n)
+	if _, err = io.ReadFull(r, v); err != nil {
+		return berPacket{}, err
+	}
+
+	return berDecode(t, v)
+}
+
+func berDecode(t byte, v []byte) (berPacket, error) {
+	var p = berPacket{t: t, v: v}
+
+	if t&0x20 == 0 {
+		return p, nil
+	}
+
+	for r := bufio.NewReader(bytes.NewReader(v)); ; {
+		if _, err := r.Peek(1); err == io.EOF {
+			return p, nil
+		} else if c, e := berRead(r); e != nil {
+			return p, e
+		} else {
+			p.c = append(p.c, c)
+		}
+	}
+}
+
+func berEnc(t byte, v []byte) []byte {
+	var l []byte
+
+	if n := len(v); n < 0x80 {
+		l = []byte{byte(n)}
+	} else {
+		for ; n > 0; n >>= 8 {
+			l = append([]byte{byte(n)}, l...)
+		}
+
+		l = append([]byte{0x80 | byte(len(l))}, l...)
+	}
+
+	return append(append([]byte{t}, l...), v...)
+}
+
+func berCat(t byte, lst ...[]byte) []byte {
+	return berEnc(t, bytes.Join(lst, nil))
+}
+
+func berStr(t byte, s string) []byte {
+	return berEnc(t, []byte(s))
+}
+
+func berNum(t byte, i int64) []byte {
+	var b []byte
+
+	for {
+		b = append([]byte{byte(i)}, b...)
+
+		if i >= -128 && i < 128 {
+			break
+		}
+
+		i >>= 8
+	}
+
+	return berEnc(t, b)
+}
+
+type srvAttr struct {
+	n string
+	v []string
+}
+
+type srvEntry struct {
+	dn string
+	at []srvAttr
+}
+
+func (e *srvEntry) get(name string) []string {
+	for _, a := range e.at {
+		if strings.EqualFold(a.n, name) {
+			return a.v
+		}
+	}
+
+	return nil
+}
+
+func (e *srvEntry) set(name string, val []string) {
+	var res = make([]srvAttr, 0, len(e.at)+1)
+
+	for _, a := range e.at {
+		if !strings.EqualFold(a.n, name) {
+			res = append(res, a)
+		}
+	}
+
+	if len(val) > 0 {
+		res = append(res, srvAttr{n: name, v: val})
+	}
+
+	e.at = res
+}
+
+func (e *srvEntry) add(name string, val ...string) {
+	var cur = e.get(name)
+
+	for _, v := range val {
+		if !containsFold(cur, v) {
+			cur = append(cur, v)
+		}
+	}
+
+	e.set(name, cur)
+}
+
+func (e *srvEntry) del(name string, val ...string) {
+	var res = make([]string, 0)
+
+	if len(val) > 0 {
+		for _, v := range e.get(name) {
+			if !containsFold(val, v) {
+				res = append(res, v)
+			}
+		}
+	}
+
+	e.set(name, res)
+}
+
+func (e *srvEntry) match(f berPacket) bool {
+	switch f.t {
+	case 0xA0: // and
+		for _, c := range f.c {
+			if !e.match(c) {
+				return false
+			}
+		}
+		return true
+	case 0xA1: // or
+		for _, c := range f.c {
+			if e.match(c) {
+				return true
+			}
+		}
+		return false
+	case 0xA2: // not
+		return len(f.c) > 0 && !e.match(f.c[0])
+	case 0xA3: // equality
+		return len(f.c) > 1 && containsFold(e.get(f.c[0].str()), f.c[1].str())
+	case 0x87: // present
+		return len(e.get(f.str())) > 0
+	}
+
+	return false
+}
+
+func (e *srvEntry) encode(att []berPacket) []byte {
+	var (
+		all = len(att) < 1
+		lst = make([][]byte, 0, len(e.at))
+	)
+
+	for _, a := range att {
+		if a.str() == "*" {
+			all = true
+		}
+	}
+
+	for _, a := range e.at {
+		var sel = all
+
+		for _, n := range att {
+			if strings.EqualFold(n.str(), a.n) {
+				sel = true
+			}
+		}
+
+		if !sel {
+			continue
+		}
+
+		var val = make([][]byte, 0, len(a.v))
+		for _, v := range a.v {
+			val = append(val, berStr(berOctet, v))
+		}
+
+		lst = append(lst, berCat(berSeq, berStr(berOctet, a.n), berCat(berSet, val...)))
+	}
+
+	return berCat(0x64, berStr(berOctet, e.dn), berCat(berSeq, lst...))
+}
+
+func containsFold(lst []string, val string) bool {
+	for _, v := range lst {
+		if strings.EqualFold(v, val) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parentDN(dn string) string {
+	if p := strings.SplitN(dn, ",", 2); len(p) > 1 {
+		return p[1]
+	}
+
+	return ""
+}
+
+func splitRDN(rdn string) (string, string) {
+	p := strings.SplitN(rdn, "=", 2)
+
+	if len(p) < 2 {
+		return p[0], ""
+	}
+
+	return p[0], p[1]
+}
+
+func result(t byte, code int64, msg string, ext ...[]byte) []byte {
+	return berCat(t, append([][]byte{berNum(berEnum, code), berStr(berOctet, ""), berStr(berOctet, msg)}, ext...)...)
+}
+
+type testServer struct {
+	m sync.Mutex
+	l net.Listener
+	e []*srvEntry // directory entries
+	c []net.Conn  // opened connections
+	n int         // accepted connections
+	p int         // paged search requests
+	a []string    // cookies of the abandoned paged searches
+	g int         // generated passwords
+	w sync.WaitGroup
+}
+
+// newServer start a new server listening on a local port, with a base of five users.
+func newServer() *testServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ToNot(HaveOccurred())
+
+	s := &testServer{l: l}
+
+	s.e = []*srvEntry{
+		{dn: testBaseDN, at: []srvAttr{{"objectClass", []string{"top", "dcObject"}}, {"dc", []string{"example"}}}},
+		{dn: testAdminDN, at: []srvAttr{{"objectClass", []string{"top", "person"}}, {"cn", []string{"admin"}}, {"userPassword", []string{testAdminPwd}}}},
+		{dn: testPeopleDN, at: []srvAttr{{"objectClass", []string{"top", "organizationalUnit"}}, {"ou", []string{"people"}}}},
+		{dn: testGroupsDN, at: []srvAttr{{"objectClass", []string{"top", "organizationalUnit"}}, {"ou", []string{"groups"}}}},
+	}
+
+	for i := 1; i <= 5; i++ {
+		s.e = append(s.e, &srvEntry{
+			dn: userDN(fmt.Sprintf("user%d", i)),
+			at: []srvAttr{
+				{"objectClass", []string{"top", "inetOrgPerson"}},
+				{"uid", []string{fmt.Sprintf("user%d", i)}},
+				{"cn", []string{fmt.Sprintf("User %d", i)}},
+				{"mail", []string{fmt.Sprintf("user%d@example.com", i)}},
+				{"userPassword", []string{fmt.Sprintf("pass%d", i)}},
+			},
+		})
+	}
+
+	s.w.Add(1)
+	go s.accept()
+
+	return s
+}
+
+func userDN(uid string) string {
+	return "uid=" + uid + "," + testPeopleDN
+}
+
+// Helper return a new helper connected to the server with the admin credentials.
+func (s *testServer) Helper() *libldp.HelperLDAP {
+	h, e := libldp.NewLDAP(context.Background(), &libldp.Config{
+		Uri:         "127.0.0.1",
+		PortLdap:    s.l.Addr().(*net.TCPAddr).Port,
+		Basedn:      testBaseDN,
+		FilterGroup: "(&(objectClass=groupOfNames)(%s=%s))",
+		FilterUser:  "(%s=%s)",
+	}, libldp.GetDefaultAttributes())
+
+	Expect(e).ToNot(HaveOccurred())
+
+	h.ForceTLSMode(libldp.TLSModeNone, nil)
+	h.SetCredentials(testAdminDN, testAdminPwd)
+
+	return h
+}
+
+func groupDN(cn string) string {
+	return "cn=" + cn + "," + testGroupsDN
+}
+
+// AddGroup add a group of names with the given members and add the group to the memberOf values
+// of the member users, as a server maintaining the direct membership. The DN of the group is returned.
+func (s *testServer) AddGroup(cn string, member ...string) string {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var dn = groupDN(cn)
+
+	s.e = append(s.e, &srvEntry{
+		dn: dn,
+		at: []srvAttr{{"objectClass", []string{"top", "groupOfNames"}}, {"cn", []string{cn}}, {"member", member}},
+	})
+
+	for _, m := range member {
+		if e := s.find(m); e != nil && strings.EqualFold(parentDN(m), testPeopleDN) {
+			e.add("memberOf", dn)
+		}
+	}
+
+	return dn
+}
+
+// Accepted return the number of connections accepted since the start.
+func (s *testServer) Accepted() int {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.n
+}
+
+// Pages return the number of paged search requests received.
+func (s *testServer) Pages() int {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.p
+}
+
+// Abandoned return the cookies of the paged searches abandoned by the client.
+func (s *testServer) Abandoned() []string {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return append(make([]string, 0, len(s.a)), s.a...)
+}
+
+// CloseConns close all opened connections on the server side.
+func (s *testServer) CloseConns() {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	for _, c := range s.c {
+		_ = c.Close()
+	}
+
+	s.c = nil
+}
+
+func (s *testServer) Close() {
+	_ = s.l.Close()
+	s.CloseConns()
+	s.w.Wait()
+}
+
+func (s *testServer) accept() {
+	defer s.w.Done()
+
+	for {
+		c, err := s.l.Accept()
+		if err != nil {
+			return
+		}
+
+		s.m.Lock()
+		s.n++
+		s.c = append(s.c, c)
+		s.m.Unlock()
+
+		s.w.Add(1)
+		go s.serve(c)
+	}
+}
+
+func (s *testServer) serve(c net.Conn) {
+	defer s.w.Done()
+	defer func() {
+		_ = c.Close()
+	}()
+
+	var (
+		r   = bufio.NewReader(c)
+		bnd string
+	)
+
+	for {
+		msg, err := berRead(r)
+		if err != nil || len(msg.c) < 2 {
+			return
+		}
+
+		var (
+			op  = msg.c[1]
+			ctl []berPacket
+			res [][]byte
+		)
+
+		if len(msg.c) > 2 {
+			ctl = msg.c[2].c
+		}
+
+		switch op.t {
+		case 0x42: // unbind
+			return
+		case 0x50: // abandon
+			continue
+		case 0x60:
+			res = [][]byte{s.bind(op, &bnd)}
+		case 0x63:
+			res = s.search(op, ctl)
+		case 0x66:
+			res = [][]byte{s.modify(op)}
+		case 0x68:
+			res = [][]byte{s.add(op)}
+		case 0x4A:
+			res = [][]byte{s.delete(op)}
+		case 0x6C:
+			res = [][]byte{s.modifyDN(op)}
+		case 0x77:
+			res = [][]byte{s.extended(op, bnd)}
+		default:
+			return
+		}
+
+		for _, p := range res {
+			if _, err = c.Write(berCat(berSeq, berNum(berInt, msg.c[0].int()), p)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// find return the entry of the given DN, the lock must be held.
+func (s *testServer) find(dn string) *srvEntry {
+	for _, e := range s.e {
+		if strings.EqualFold(e.dn, dn) {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// leaf return true if the given DN has no child, the lock must be held.
+func (s *testServer) leaf(dn string) bool {
+	for _, e := range s.e {
+		if strings.EqualFold(parentDN(e.dn), dn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *testServer) bind(op berPacket, bnd *string) []byte {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if len(op.c) < 3 {
+		return result(0x61, resProtocolError, "invalid bind request")
+	} else if e := s.find(op.c[1].str()); e == nil || !containsFold(e.get("userPassword"), op.c[2].str()) {
+		return result(0x61, resInvalidCred, "invalid credentials")
+	}
+
+	*bnd = op.c[1].str()
+	return result(0x61, resSuccess, "")
+}
+
+func (s *testServer) search(op berPacket, ctl []berPacket) [][]byte {
+	if len(op.c) < 8 {
+		return [][]byte{result(0x65, resProtocolError, "invalid search request")}
+	}
+
+	var (
+		bdn = op.c[0].str()
+		scp = op.c[1].int()
+		flt = op.c[6]
+		att = op.c[7].c
+		res = make([][]byte, 0)
+		lst = make([]*srvEntry, 0)
+	)
+
+	if len(bdn) < 1 && scp == 0 {
+		// root DSE
+		return [][]byte{berCat(0x64, berStr(berOctet, ""), berCat(berSeq)), result(0x65, resSuccess, "")}
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.find(bdn) == nil {
+		return [][]byte{result(0x65, resNoSuchObject, "no such object")}
+	}
+
+	for _, e := range s.e {
+		var in bool
+
+		switch scp {
+		case 0:
+			in = strings.EqualFold(e.dn, bdn)
+		case 1:
+			in = strings.EqualFold(parentDN(e.dn), bdn)
+		default:
+			in = strings.EqualFold(e.dn, bdn) || strings.HasSuffix(strings.ToLower(e.dn), ","+strings.ToLower(bdn))
+		}
+
+		if in && e.match(flt) {
+			lst = append(lst, e)
+		}
+	}
+
+	siz, cok, pag := pagingControl(ctl)
+
+	if pag {
+		s.p++
+
+		if siz == 0 {
+			s.a = append(s.a, cok)
+			return [][]byte{append(result(0x65, resSuccess, ""), berCat(berCtrls, pagingResponse(""))...)}
+		}
+
+		var (
+			off, _ = strconv.Atoi(cok)
+			end    = off + siz
+			nxt    string
+		)
+
+		if off > len(lst) {
+			off = len(lst)
+		}
+
+		if end < len(lst) {
+			nxt = strconv.Itoa(end)
+		} else {
+			end = len(lst)
+		}
+
+		lst = lst[off:end]
+		defer func() {
+			res[len(res)-1] = append(res[len(res)-1], berCat(berCtrls, pagingResponse(nxt))...)
+		}()
+	}
+
+	for _, e := range lst {
+		res = append(res, e.encode(att))
+	}
+
+	res = append(res, result(0x65, resSuccess, ""))
+	return res
+}
+
+// pagingControl return the size and cookie of the paging control if found in the given controls.
+func pagingControl(ctl []berPacket) (int, string, bool) {
+	for _, c := range ctl {
+		if len(c.c) < 2 || c.c[0].str() != oidPaging {
+			continue
+		}
+
+		v, err := berRead(bufio.NewReader(bytes.NewReader(c.c[len(c.c)-1].v)))
+
+		if err != nil || len(v.c) < 2 {
+			return 0, "", false
+		}
+
+		return int(v.c[0].int()), v.c[1].str(), true
+	}
+
+	return 0, "", false
+}
+
+func pagingResponse(cookie string) []byte {
+	return berCat(berSeq, berStr(berOctet, oidPaging), berEnc(berOctet, berCat(berSeq, berNum(berInt, 0), berStr(berOctet, cookie))))
+}
+
+func (s *testServer) add(op berPacket) []byte {
+	if len(op.c) < 2 {
+		return result(0x69, resProtocolError, "invalid add request")
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var e = &srvEntry{dn: op.c[0].str()}
+
+	if s.find(e.dn) != nil {
+		return result(0x69, resAlreadyExists, "entry already exists")
+	} else if s.find(parentDN(e.dn)) == nil {
+		return result(0x69, resNoSuchObject, "parent not found")
+	}
+
+	for _, a := range op.c[1].c {
+		for _, v := range a.c[1].c {
+			e.add(a.c[0].str(), v.str())
+		}
+	}
+
+	s.e = append(s.e, e)
+	return result(0x69, resSuccess, "")
+}
+
+func (s *testServer) modify(op berPacket) []byte {
+	if len(op.c) < 2 {
+		return result(0x67, resProtocolError, "invalid modify request")
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var e = s.find(op.c[0].str())
+
+	if e == nil {
+		return result(0x67, resNoSuchObject, "no such object")
+	}
+
+	for _, c := range op.c[1].c {
+		var (
+			nam = c.c[1].c[0].str()
+			val = make([]string, 0)
+		)
+
+		for _, v := range c.c[1].c[1].c {
+			val = append(val, v.str())
+		}
+
+		switch c.c[0].int() {
+		case 0:
+			e.add(nam, val...)
+		case 1:
+			e.del(nam, val...)
+		case 2:
+			e.set(nam, val)
+		}
+	}
+
+	return result(0x67, resSuccess, "")
+}
+
+func (s *testServer) delete(op berPacket) []byte {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var dn = op.str()
+
+	if s.find(dn) == nil {
+		return result(0x6B, resNoSuchObject, "no such object")
+	} else if !s.leaf(dn) {
+		return result(0x6B, resNotAllowedNoLeaf, "entry has children")
+	}
+
+	for i, e := range s.e {
+		if strings.EqualFold(e.dn, dn) {
+			s.e = append(s.e[:i], s.e[i+1:]...)
+			break
+		}
+	}
+
+	return result(0x6B, resSuccess, "")
+}
+
+func (s *testServer) modifyDN(op berPacket) []byte {
+	if len(op.c) < 3 {
+		return result(0x6D, resProtocolError, "invalid modify dn request")
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var (
+		e   = s.find(op.c[0].str())
+		rdn = op.c[1].str()
+		sup = parentDN(op.c[0].str())
+	)
+
+	if len(op.c) > 3 && len(op.c[3].v) > 0 {
+		sup = op.c[3].str()
+	}
+
+	if e == nil || s.find(sup) == nil {
+		return result(0x6D, resNoSuchObject, "no such object")
+	} else if !s.leaf(e.dn) {
+		return result(0x6D, resNotAllowedNoLeaf, "entry has children")
+	} else if s.find(rdn+","+sup) != nil {
+		return result(0x6D, resAlreadyExists, "entry already exists")
+	}
+
+	if op.c[2].int() != 0 {
+		e.del(splitRDN(strings.SplitN(e.dn, ",", 2)[0]))
+	}
+
+	e.add(splitRDN(rdn))
+	e.dn = rdn + "," + sup
+
+	return result(0x6D, resSuccess, "")
+}
+
+func (s *testServer) extended(op berPacket, bnd string) []byte {
+	if len(op.c) < 1 || op.c[0].str() != oidPasswordModify {
+		return result(0x78, resProtocolError, "unsupported extended operation")
+	}
+
+	var usr, old, pwd = bnd, "", ""
+
+	if len(op.c) > 1 {
+		v, err := berRead(bufio.NewReader(bytes.NewReader(op.c[1].v)))
+		if err != nil {
+			return result(0x78, resProtocolError, "invalid password modify request")
+		}
+
+		for _, c := range v.c {
+			switch c.t {
+			case berCtx0:
+				usr = c.str()
+			case berCtx1:
+				old = c.str()
+			case berCtx2:
+				pwd = c.str()
+			}
+		}
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var e = s.find(usr)
+
+	if e == nil {
+		return result(0x78, resNoSuchObject, "no such object")
+	} else if len(old) > 0 && !containsFold(e.get("userPassword"), old) {
+		return result(0x78, resInvalidCred, "invalid old password")
+	}
+
+	if len(pwd) > 0 {
+		e.set("userPassword", []string{pwd})
+		return result(0x78, resSuccess, "")
+	}
+
+	s.g++
+	pwd = fmt.Sprintf("generated-%d", s.g)
+	e.set("userPassword", []string{pwd})
+
+	return result(0x78, resSuccess, "", berEnc(berPDV, berCat(berSeq, berStr(berCtx0, pwd))))
+}
@@ -0,0 +1,221 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package ldap_test
+
+import (
+	libldp "github.com/nabbar/golib/ldap"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LDAP Write", func() {
+	var (
+		srv *testServer
+		hlp *libldp.HelperLDAP
+	)
+
+	// get return the entry of the given DN or nil if not found.
+	get := func(dn string) *libldp.Entry {
+		res, e := hlp.Search(libldp.SearchRequest{BaseDN: dn, Scope: libldp.ScopeBase})
+
+		if e != nil || len(res) < 1 {
+			return nil
+		}
+
+		return &res[0]
+	}
+
+	BeforeEach(func() {
+		srv = newServer()
+		hlp = srv.Helper()
+		Expect(hlp.EnablePool(libldp.PoolConfig{})).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			hlp.DisablePool()
+			srv.Close()
+		})
+	})
+
+	Context("Add an entry", func() {
+		It("Must create the entry with its attributes", func() {
+			Expect(hlp.Add(userDN("user6"), map[string][]string{
+				"objectClass": {"top", "inetOrgPerson"},
+				"uid":         {"user6"},
+				"cn":          {"User 6"},
+				"mail":        {"user6@example.com", "six@example.com"},
+			})).ToNot(HaveOccurred())
+
+			e := get(userDN("user6"))
+			Expect(e).ToNot(BeNil())
+			Expect(e.Get("cn")).To(Equal("User 6"))
+			Expect(e.GetAll("mail")).To(Equal([]string{"user6@example.com", "six@example.com"}))
+		})
+
+		It("Must fail on an existing entry", func() {
+			e := hlp.Add(userDN("user1"), map[string][]string{"uid": {"user1"}})
+			Expect(e).To(HaveOccurred())
+			Expect(e.HasCode(libldp.ErrorLDAPAdd)).To(BeTrue())
+		})
+
+		It("Must refuse empty parameters", func() {
+			e := hlp.Add(userDN("user6"), nil)
+			Expect(e).To(HaveOccurred())
+			Expect(e.IsCode(libldp.ErrorParamEmpty)).To(BeTrue())
+
+			e = hlp.Add("", map[string][]string{"uid": {"user6"}})
+			Expect(e).To(HaveOccurred())
+			Expect(e.IsCode(libldp.ErrorParamEmpty)).To(BeTrue())
+		})
+	})
+
+	Context("Modify an entry", func() {
+		It("Must apply the modifications in order", func() {
+			Expect(hlp.Modify(userDN("user1"),
+				libldp.Modification{Operation: libldp.ModifyReplace, Attribute: "mail", Values: []string{"first@example.com"}},
+				libldp.Modification{Operation: libldp.ModifyAdd, Attribute: "mail", Values: []string{"one@example.com"}},
+				libldp.Modification{Operation: libldp.ModifyAdd, Attribute: "description", Values: []string{"first user"}},
+				libldp.Modification{Operation: libldp.ModifyDelete, Attribute: "cn"},
+			)).ToNot(HaveOccurred())
+
+			e := get(userDN("user1"))
+			Expect(e).ToNot(BeNil())
+			Expect(e.GetAll("mail")).To(Equal([]string{"first@example.com", "one@example.com"}))
+			Expect(e.Get("description")).To(Equal("first user"))
+			Expect(e.GetAll("cn")).To(BeEmpty())
+
+			Expect(hlp.Modify(userDN("user1"),
+				libldp.Modification{Operation: libldp.ModifyDelete, Attribute: "mail", Values: []string{"first@example.com"}},
+			)).ToNot(HaveOccurred())
+			Expect(get(userDN("user1")).GetAll("mail")).To(Equal([]string{"one@example.com"}))
+		})
+
+		It("Must fail on an unknown entry", func() {
+			e := hlp.Modify(userDN("nobody"), libldp.Modification{Operation: libldp.ModifyReplace, Attribute: "cn", Values: []string{"Nobody"}})
+			Expect(e).To(HaveOccurred())
+			Expect(e.HasCode(libldp.ErrorLDAPModify)).To(BeTrue())
+		})
+
+		It("Must fail on an unknown operation", func() {
+			e := hlp.Modify(userDN("user1"), libldp.Modification{Operation: libldp.ModifyOperation(99), Attribute: "cn", Values: []string{"Nobody"}})
+			Expect(e).To(HaveOccurred())
+			Expect(e.HasCode(libldp.ErrorParamInvalid)).To(BeTrue())
+			Expect(get(userDN("user1")).Get("cn")).To(Equal("User 1"))
+		})
+
+		It("Must refuse empty modifications", func() {
+			e := hlp.Modify(userDN("user1"))
+			Expect(e).To(HaveOccurred())
+			Expect(e.IsCode(libldp.ErrorParamEmpty)).To(BeTrue())
+		})
+	})
+
+	Context("Delete an entry", func() {
+		It("Must remove the entry", func() {
+			Expect(hlp.Delete(userDN("user5"))).ToNot(HaveOccurred())
+			Expect(get(userDN("user5"))).To(BeNil())
+
+			res, e := hlp.Search(libldp.SearchRequest{BaseDN: testPeopleDN, Scope: libldp.ScopeOneLevel})
+			Expect(e).ToNot(HaveOccurred())
+			Expect(res).To(HaveLen(4))
+		})
+
+		It("Must fail on an entry with children", func() {
+			e := hlp.Delete(testPeopleDN)
+			Expect(e).To(HaveOccurred())
+			Expect(e.HasCode(libldp.ErrorLDAPDelete)).To(BeTrue())
+			Expect(get(testPeopleDN)).ToNot(BeNil())
+		})
+	})
+
+	Context("Rename or move an entry", func() {
+		It("Must rename the entry and remove the old RDN value", func() {
+			Expect(hlp.ModifyDN(userDN("user1"), "uid=renamed", true, "")).ToNot(HaveOccurred())
+			Expect(get(userDN("user1"))).To(BeNil())
+
+			e := get(userDN("renamed"))
+			Expect(e).ToNot(BeNil())
+			Expect(e.GetAll("uid")).To(Equal([]string{"renamed"}))
+		})
+
+		It("Must rename the entry and keep the old RDN value", func() {
+			Expect(hlp.ModifyDN(userDN("user1"), "uid=renamed", false, "")).ToNot(HaveOccurred())
+
+			e := get(userDN("renamed"))
+			Expect(e).ToNot(BeNil())
+			Expect(e.GetAll("uid")).To(Equal([]string{"user1", "renamed"}))
+		})
+
+		It("Must move the entry under the new superior", func() {
+			Expect(hlp.ModifyDN(userDN("user2"), "uid=user2", false, testGroupsDN)).ToNot(HaveOccurred())
+			Expect(get(userDN("user2"))).To(BeNil())
+			Expect(get("uid=user2," + testGroupsDN)).ToNot(BeNil())
+		})
+
+		It("Must fail on an existing target", func() {
+			e := hlp.ModifyDN(userDN("user1"), "uid=user2", true, "")
+			Expect(e).To(HaveOccurred())
+			Expect(e.HasCode(libldp.ErrorLDAPModifyDN)).To(BeTrue())
+
+			e = hlp.ModifyDN(userDN("user1"), "", true, "")
+			Expect(e).To(HaveOccurred())
+			Expect(e.IsCode(libldp.ErrorParamEmpty)).To(BeTrue())
+		})
+	})
+
+	Context("Modify a password", func() {
+		// auth check the given credentials with a dedicated connection.
+		auth := func(dn, pwd string) error {
+			h := srv.Helper()
+			defer h.Close()
+
+			return h.AuthUser(dn, pwd)
+		}
+
+		It("Must change the password with the old one", func() {
+			gen, e := hlp.PasswordModify(userDN("user2"), "pass2", "changed")
+			Expect(e).ToNot(HaveOccurred())
+			Expect(gen).To(BeEmpty())
+
+			Expect(auth(userDN("user2"), "changed")).ToNot(HaveOccurred())
+			Expect(auth(userDN("user2"), "pass2")).To(HaveOccurred())
+		})
+
+		It("Must return the password generated by the server", func() {
+			gen, e := hlp.PasswordModify(userDN("user3"), "", "")
+			Expect(e).ToNot(HaveOccurred())
+			Expect(gen).To(Equal("generated-1"))
+
+			Expect(auth(userDN("user3"), gen)).ToNot(HaveOccurred())
+		})
+
+		It("Must fail with a wrong old password", func() {
+			_, e := hlp.PasswordModify(userDN("user4"), "wrong", "changed")
+			Expect(e).To(HaveOccurred())
+			Expect(e.HasCode(libldp.ErrorLDAPPasswordModify)).To(BeTrue())
+
+			Expect(auth(userDN("user4"), "pass4")).ToNot(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,341 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package ldap
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	libdur "github.com/nabbar/golib/duration"
+	liberr "github.com/nabbar/golib/errors"
+	loglvl "github.com/nabbar/golib/logger/level"
+)
+
+const (
+	defaultPoolMaxOpen     = 10
+	defaultPoolMaxIdle     = 2
+	defaultPoolIdleTimeout = 5 * time.Minute
+	defaultPoolHealthCheck = 30 * time.Second
+)
+
+type PoolConfig struct {
+	//MaxOpen is the maximum number of connections opened at the same time. Default is 10.
+	MaxOpen int `cloud:"max-open" mapstructure:"max-open" json:"max-open" yaml:"max-open" toml:"max-open" validate:"gte=0"`
+	//MaxIdle is the maximum number of connections kept opened when not used. Default is 2.
+	MaxIdle int `cloud:"max-idle" mapstructure:"max-idle" json:"max-idle" yaml:"max-idle" toml:"max-idle" validate:"gte=0"`
+	//IdleTimeout is the duration after which a not used connection is closed. Default is 5 minutes.
+	IdleTimeout libdur.Duration `cloud:"idle-timeout" mapstructure:"idle-timeout" json:"idle-timeout" yaml:"idle-timeout" toml:"idle-timeout"`
+	//HealthCheck is the duration after which a not used connection is checked before being used. Default is 30 seconds.
+	HealthCheck libdur.Duration `cloud:"health-check" mapstructure:"health-check" json:"health-check" yaml:"health-check" toml:"health-check"`
+}
+
+// PoolStats is a snapshot of the connections of a pool.
+type PoolStats struct {
+	Open  int
+	Idle  int
+	InUse int
+}
+
+type poolConn struct {
+	c *ldap.Conn
+	t time.Time
+}
+
+// Pool manage a set of connections bound with the credentials of a helper.
+type Pool struct {
+	m sync.Mutex
+	d sync.Mutex // serialize the dial of the helper
+	h *HelperLDAP
+	c PoolConfig
+	s chan struct{} // semaphore of opened connections
+	i []poolConn    // idle connections
+	u int           // connections in use
+	x bool          // closed
+}
+
+func (cfg PoolConfig) maxOpen() int {
+	if cfg.MaxOpen > 0 {
+		return cfg.MaxOpen
+	}
+
+	return defaultPoolMaxOpen
+}
+
+func (cfg PoolConfig) maxIdle() int {
+	if cfg.MaxIdle > 0 {
+		return cfg.MaxIdle
+	}
+
+	return defaultPoolMaxIdle
+}
+
+func (cfg PoolConfig) idleTimeout() time.Duration {
+	if d := cfg.IdleTimeout.Time(); d > 0 {
+		return d
+	}
+
+	return defaultPoolIdleTimeout
+}
+
+func (cfg PoolConfig) healthCheck() time.Duration {
+	if d := cfg.HealthCheck.Time(); d > 0 {
+		return d
+	}
+
+	return defaultPoolHealthCheck
+}
+
+// EnablePool define a pool of connections used by all operations of the helper and its clones.
+// The connections are bound with the credentials of the helper (see SetCredentials).
+// AuthUser still use a dedicated connection to not change the identity of pooled connections.
+func (lc *HelperLDAP) EnablePool(cfg PoolConfig) liberr.Error {
+	if lc == nil {
+		return ErrorParamEmpty.Error(nil)
+	}
+
+	if lc.pool != nil {
+		lc.pool.Close()
+	}
+
+	lc.pool = &Pool{
+		m: sync.Mutex{},
+		d: sync.Mutex{},
+		h: lc.Clone(),
+		c: cfg,
+		s: make(chan struct{}, cfg.maxOpen()),
+		i: make([]poolConn, 0),
+	}
+
+	lc.pool.h.pool = nil
+	return nil
+}
+
+// DisablePool close the pool of connections of the helper if any.
+func (lc *HelperLDAP) DisablePool() {
+	if lc == nil || lc.pool == nil {
+		return
+	}
+
+	lc.pool.Close()
+	lc.pool = nil
+}
+
+// Pool return the pool of connections of the helper or nil if not enabled.
+func (lc *HelperLDAP) Pool() *Pool {
+	if lc == nil {
+		return nil
+	}
+
+	return lc.pool
+}
+
+// Get return a bound connection of the pool, waiting for a free slot until the context is done.
+// The connection must be given back with Put.
+func (p *Pool) Get(ctx context.Context) (*ldap.Conn, liberr.Error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	select {
+	case p.s <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ErrorLDAPPool.Error(ctx.Err())
+	}
+
+	for {
+		c, ok, e := p.pop()
+
+		if e != nil {
+			<-p.s
+			return nil, e
+		} else if !ok {
+			break
+		} else if p.valid(c) {
+			p.inc(1)
+			return c.c, nil
+		}
+
+		_ = c.c.Close()
+	}
+
+	c, e := p.open()
+
+	if e != nil {
+		<-p.s
+		return nil, e
+	}
+
+	p.inc(1)
+	return c, nil
+}
+
+// Put give back a connection to the pool. The connection is closed if the given error of
+// its last operation is a network error, or if there is already enough idle connections.
+func (p *Pool) Put(c *ldap.Conn, err error) {
+	if c == nil {
+		return
+	}
+
+	defer func() {
+		<-p.s
+	}()
+
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.u--
+
+	if p.x || c.IsClosing() || ldap.IsErrorWithCode(err, ldap.ErrorNetwork) || len(p.i) >= p.c.maxIdle() {
+		_ = c.Close()
+		return
+	}
+
+	p.i = append(p.i, poolConn{c: c, t: time.Now()})
+}
+
+// Check run a health check on all idle connections and close the broken or expired ones.
+func (p *Pool) Check() liberr.Error {
+	p.m.Lock()
+
+	if p.x {
+		p.m.Unlock()
+		return ErrorLDAPPool.Error(nil)
+	}
+
+	var lst = p.i
+	p.i = make([]poolConn, 0, len(lst))
+	p.m.Unlock()
+
+	var res = make([]poolConn, 0, len(lst))
+
+	for _, c := range lst {
+		if time.Since(c.t) > p.c.idleTimeout() || ping(c.c) != nil {
+			_ = c.c.Close()
+		} else {
+			res = append(res, c)
+		}
+	}
+
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.i = append(p.i, res...)
+	return nil
+}
+
+// Stats return the number of opened, idle and used connections.
+func (p *Pool) Stats() PoolStats {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	return PoolStats{
+		Open:  len(p.i) + p.u,
+		Idle:  len(p.i),
+		InUse: p.u,
+	}
+}
+
+// Close close all idle connections. The connections in use are closed when given back.
+func (p *Pool) Close() {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.x = true
+
+	for _, c := range p.i {
+		_ = c.c.Close()
+	}
+
+	p.i = make([]poolConn, 0)
+}
+
+func (p *Pool) inc(n int) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.u += n
+}
+
+func (p *Pool) pop() (poolConn, bool, liberr.Error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if p.x {
+		return poolConn{}, false, ErrorLDAPPool.Error(nil)
+	} else if len(p.i) < 1 {
+		return poolConn{}, false, nil
+	}
+
+	var c = p.i[len(p.i)-1]
+	p.i = p.i[:len(p.i)-1]
+
+	return c, true, nil
+}
+
+func (p *Pool) valid(c poolConn) bool {
+	if c.c.IsClosing() {
+		return false
+	} else if d := time.Since(c.t); d > p.c.idleTimeout() {
+		return false
+	} else if d > p.c.healthCheck() {
+		return ping(c.c) == nil
+	}
+
+	return true
+}
+
+func (p *Pool) open() (*ldap.Conn, liberr.Error) {
+	p.d.Lock()
+	defer p.d.Unlock()
+
+	if err := p.h.ctx.Err(); err != nil {
+		return nil, ErrorLDAPContext.Error(err)
+	}
+
+	c, e := p.h.newConn()
+
+	if e != nil {
+		return nil, e
+	}
+
+	if len(p.h.bindDN) > 0 {
+		if err := c.Bind(p.h.bindDN, p.h.bindPass); err != nil {
+			_ = c.Close()
+			return nil, ErrorLDAPBind.Error(err)
+		}
+	}
+
+	p.h.getLogEntry(loglvl.DebugLevel, "ldap pool connection opened").FieldAdd("bind.dn", p.h.bindDN).Log()
+	return c, nil
+}
+
+// ping check a connection with a search of the root DSE.
+func ping(c *ldap.Conn) error {
+	_, err := c.Search(ldap.NewSearchRequest("", ldap.ScopeBaseObject, ldap.NeverDerefAliases, 0, 5, false, "(objectClass=*)", []string{"1.1"}, nil))
+	return err
+}
@@ -0,0 +1,258 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package ldap
+
+import (
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	liberr "github.com/nabbar/golib/errors"
+	loglvl "github.com/nabbar/golib/logger/level"
+)
+
+type SearchScope uint8
+
+const (
+	//ScopeSubtree search the base object and all its descendants (default).
+	ScopeSubtree SearchScope = iota
+	//ScopeOneLevel search only the immediate children of the base object.
+	ScopeOneLevel
+	//ScopeBase search only the base object.
+	ScopeBase
+)
+
+func (s SearchScope) ldap() int {
+	switch s {
+	case ScopeOneLevel:
+		return ldap.ScopeSingleLevel
+	case ScopeBase:
+		return ldap.ScopeBaseObject
+	default:
+		return ldap.ScopeWholeSubtree
+	}
+}
+
+// SearchRequest define a generic search.
+type SearchRequest struct {
+	//BaseDN is the base of the search. Default is the basedn of the config.
+	BaseDN string
+	//Scope is the scope of the search. Default is the whole subtree.
+	Scope SearchScope
+	//Filter is the ldap filter of the search. Default is '(objectClass=*)'.
+	Filter string
+	//Attributes is the list of attributes to retrieve. Default is all attributes.
+	Attributes []string
+	//SizeLimit is the maximum number of entries returned by the server. Zero means no limit.
+	SizeLimit int
+	//TimeLimit is the maximum duration of the search on the server. Zero means no limit.
+	TimeLimit time.Duration
+	//PageSize enable the paged search (RFC 2696) with the given number of entries by page. Zero disable the paged search.
+	PageSize uint32
+}
+
+// Entry is an entry returned by a search.
+type Entry struct {
+	DN         string              `json:"dn"`
+	Attributes map[string][]string `json:"attributes"`
+}
+
+// Get return the first value of the given attribute.
+func (e Entry) Get(attribute string) string {
+	if v := e.GetAll(attribute); len(v) > 0 {
+		return v[0]
+	}
+
+	return ""
+}
+
+// GetAll return all values of the given attribute.
+func (e Entry) GetAll(attribute string) []string {
+	if e.Attributes == nil {
+		return nil
+	}
+
+	return e.Attributes[attribute]
+}
+
+func newEntry(e *ldap.Entry) Entry {
+	var res = Entry{
+		DN:         e.DN,
+		Attributes: make(map[string][]string, len(e.Attributes)),
+	}
+
+	for _, a := range e.Attributes {
+		res.Attributes[a.Name] = append(res.Attributes[a.Name], a.Values...)
+	}
+
+	return res
+}
+
+func (lc *HelperLDAP) newSearchRequest(req SearchRequest) *ldap.SearchRequest {
+	var (
+		bdn = req.BaseDN
+		flt = req.Filter
+	)
+
+	if len(bdn) < 1 {
+		bdn = lc.config.Basedn
+	}
+
+	if len(flt) < 1 {
+		flt = "(objectClass=*)"
+	}
+
+	return ldap.NewSearchRequest(
+		bdn,
+		req.Scope.ldap(),
+		ldap.NeverDerefAliases,
+		req.SizeLimit,
+		int(req.TimeLimit.Seconds()),
+		false,
+		flt,
+		req.Attributes,
+		nil,
+	)
+}
+
+// Search run the given search request and return all the entries found.
+// With a page size, the search is done by pages to not reach the size limit of the server.
+func (lc *HelperLDAP) Search(req SearchRequest) ([]Entry, liberr.Error) {
+	var res = make([]Entry, 0)
+
+	err := lc.searchWalk(req, func(e *ldap.Entry) bool {
+		res = append(res, newEntry(e))
+		return true
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// SearchWalk run the given search request and call the given func for each entry found, until the func return false.
+// With a page size, only one page is loaded at a time : this is the way to walk on large directories.
+func (lc *HelperLDAP) SearchWalk(req SearchRequest, fct func(e Entry) bool) liberr.Error {
+	if fct == nil {
+		return ErrorParamEmpty.Error(nil)
+	}
+
+	return lc.searchWalk(req, func(e *ldap.Entry) bool {
+		return fct(newEntry(e))
+	})
+}
+
+// SearchAs run the given search request and decode each entry found into a new item of the given type.
+// The fields of the type are matched with the attributes by the 'ldap' tag (see ldap.Entry.Unmarshal),
+// a string field with the tag 'dn' receive the DN of the entry.
+func SearchAs[T any](lc *HelperLDAP, req SearchRequest) ([]T, liberr.Error) {
+	var (
+		res = make([]T, 0)
+		err error
+	)
+
+	e := lc.searchWalk(req, func(e *ldap.Entry) bool {
+		var i T
+
+		if err = e.Unmarshal(&i); err != nil {
+			return false
+		}
+
+		res = append(res, i)
+		return true
+	})
+
+	if e != nil {
+		return nil, e
+	} else if err != nil {
+		return nil, ErrorLDAPSearch.Error(err)
+	}
+
+	return res, nil
+}
+
+func (lc *HelperLDAP) searchWalk(req SearchRequest, fct func(e *ldap.Entry) bool) liberr.Error {
+	var (
+		err error
+		src *ldap.SearchResult
+		srq = lc.newSearchRequest(req)
+		pag *ldap.ControlPaging
+		cnt int
+	)
+
+	con, rel, e := lc.getConn()
+	if e != nil {
+		return e
+	}
+
+	defer func() {
+		rel(err)
+	}()
+
+	if req.PageSize > 0 {
+		pag = ldap.NewControlPaging(req.PageSize)
+		srq.Controls = []ldap.Control{pag}
+	}
+
+	for {
+		if src, err = con.Search(srq); err != nil {
+			return ErrorLDAPSearch.Error(err)
+		}
+
+		var cookie []byte
+
+		if pag != nil {
+			if c, ok := ldap.FindControl(src.Controls, ldap.ControlTypePaging).(*ldap.ControlPaging); ok {
+				cookie = c.Cookie
+			}
+		}
+
+		for _, i := range src.Entries {
+			cnt++
+
+			if !fct(i) {
+				if len(cookie) > 0 {
+					// abandon the paged search on the server side with the cookie of the last page
+					pag.SetCookie(cookie)
+					pag.PagingSize = 0
+					_, _ = con.Search(srq)
+				}
+
+				return nil
+			}
+		}
+
+		if len(cookie) < 1 {
+			break
+		}
+
+		pag.SetCookie(cookie)
+	}
+
+	lc.getLogEntry(loglvl.DebugLevel, "ldap search success").FieldAdd("ldap.basedn", srq.BaseDN).FieldAdd("ldap.filter", srq.Filter).FieldAdd("ldap.entries", cnt).Log()
+	return nil
+}
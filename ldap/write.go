@@ -0,0 +1,165 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package ldap
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-ldap/ldap/v3"
+	liberr "github.com/nabbar/golib/errors"
+	loglvl "github.com/nabbar/golib/logger/level"
+)
+
+type ModifyOperation uint8
+
+const (
+	//ModifyAdd add the values to the attribute.
+	ModifyAdd ModifyOperation = iota
+	//ModifyDelete delete the values of the attribute, or the attribute if no value is given.
+	ModifyDelete
+	//ModifyReplace replace all values of the attribute.
+	ModifyReplace
+)
+
+// Modification is a change on an attribute of an entry.
+type Modification struct {
+	Operation ModifyOperation
+	Attribute string
+	Values    []string
+}
+
+// writeOp run the given write operation with a connection of the helper.
+func (lc *HelperLDAP) writeOp(code liberr.CodeError, msg, dn string, fct func(l *ldap.Conn) error) liberr.Error {
+	if len(dn) < 1 {
+		return ErrorParamEmpty.Error(nil)
+	}
+
+	con, rel, e := lc.getConn()
+	if e != nil {
+		return e
+	}
+
+	err := fct(con)
+	rel(err)
+
+	if err != nil {
+		return code.Error(err)
+	}
+
+	lc.getLogEntry(loglvl.DebugLevel, msg).FieldAdd("ldap.dn", dn).Log()
+	return nil
+}
+
+// Add create a new entry with the given attributes (including objectClass).
+func (lc *HelperLDAP) Add(dn string, attributes map[string][]string) liberr.Error {
+	if len(attributes) < 1 {
+		return ErrorParamEmpty.Error(nil)
+	}
+
+	var (
+		req = ldap.NewAddRequest(dn, nil)
+		key = make([]string, 0, len(attributes))
+	)
+
+	for k := range attributes {
+		key = append(key, k)
+	}
+
+	sort.Strings(key)
+
+	for _, k := range key {
+		req.Attribute(k, attributes[k])
+	}
+
+	return lc.writeOp(ErrorLDAPAdd, "ldap add success", dn, func(l *ldap.Conn) error {
+		return l.Add(req)
+	})
+}
+
+// Modify apply the given modifications on the entry, in order.
+func (lc *HelperLDAP) Modify(dn string, mods ...Modification) liberr.Error {
+	if len(mods) < 1 {
+		return ErrorParamEmpty.Error(nil)
+	}
+
+	var req = ldap.NewModifyRequest(dn, nil)
+
+	for _, m := range mods {
+		switch m.Operation {
+		case ModifyAdd:
+			req.Add(m.Attribute, m.Values)
+		case ModifyDelete:
+			req.Delete(m.Attribute, m.Values)
+		case ModifyReplace:
+			req.Replace(m.Attribute, m.Values)
+		default:
+			return ErrorParamInvalid.Error(fmt.Errorf("invalid modify operation %d for attribute '%s'", m.Operation, m.Attribute))
+		}
+	}
+
+	return lc.writeOp(ErrorLDAPModify, "ldap modify success", dn, func(l *ldap.Conn) error {
+		return l.Modify(req)
+	})
+}
+
+// Delete remove the entry.
+func (lc *HelperLDAP) Delete(dn string) liberr.Error {
+	return lc.writeOp(ErrorLDAPDelete, "ldap delete success", dn, func(l *ldap.Conn) error {
+		return l.Del(ldap.NewDelRequest(dn, nil))
+	})
+}
+
+// ModifyDN rename the entry with the given new RDN (like 'uid=new') and move it under the
+// given new superior DN if not empty. The old RDN value is removed from the attributes if deleteOldRDN is true.
+func (lc *HelperLDAP) ModifyDN(dn, newRDN string, deleteOldRDN bool, newSuperior string) liberr.Error {
+	if len(newRDN) < 1 {
+		return ErrorParamEmpty.Error(nil)
+	}
+
+	return lc.writeOp(ErrorLDAPModifyDN, "ldap modify dn success", dn, func(l *ldap.Conn) error {
+		return l.ModifyDN(ldap.NewModifyDNRequest(dn, newRDN, deleteOldRDN, newSuperior))
+	})
+}
+
+// PasswordModify change the password of the given user DN with the password modify extended operation (RFC 3062).
+// The old password is optional if the bind user is allowed to change it. If the new password is empty,
+// the server generate a new password which is returned.
+func (lc *HelperLDAP) PasswordModify(userDN, oldPassword, newPassword string) (string, liberr.Error) {
+	var gen string
+
+	err := lc.writeOp(ErrorLDAPPasswordModify, "ldap password modify success", userDN, func(l *ldap.Conn) error {
+		if res, e := l.PasswordModify(ldap.NewPasswordModifyRequest(userDN, oldPassword, newPassword)); e != nil {
+			return e
+		} else if res != nil {
+			gen = res.GeneratedPassword
+		}
+
+		return nil
+	})
+
+	return gen, err
+}
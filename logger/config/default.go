@@ -44,7 +44,18 @@ var _defaultConfig = []byte(`
      "disableTimestamp":false,
      "enableTrace":true,
      "disableColor":false,
-     "enableAccessLog": false
+     "enableAccessLog": false,
+     "format":{
+       "type":"text",
+       "timestampLayout":"",
+       "fieldMap":{}
+     },
+     "sampling":{
+       "interval":"1s",
+       "first":0,
+       "thereafter":0,
+       "rateLimit":[]
+     }
    },
    "logFile":[
       {
@@ -62,10 +73,31 @@ var _defaultConfig = []byte(`
          "fileMode":"0644",
          "pathMode":"0755",
          "file-buffer-size": "32KB",
+         "rotate":{
+            "maxSize":"0B",
+            "interval":"0s",
+            "daily":false,
+            "dailyHour":0,
+            "maxBackups":0,
+            "maxAge":"0s",
+            "compress":false,
+            "reopenOnSignal":false
+         },
          "disableStack":false,
          "disableTimestamp":false,
          "enableTrace":true,
-         "enableAccessLog": false
+         "enableAccessLog": false,
+         "format":{
+            "type":"text",
+            "timestampLayout":"",
+            "fieldMap":{}
+         },
+         "sampling":{
+            "interval":"1s",
+            "first":0,
+            "thereafter":0,
+            "rateLimit":[]
+         }
       }
    ],
    "logSyslog":[
@@ -86,7 +118,57 @@ var _defaultConfig = []byte(`
          "disableStack":false,
          "disableTimestamp":false,
          "enableTrace":true,
-         "enableAccessLog": false
+         "enableAccessLog": false,
+         "format":{
+            "type":"text",
+            "timestampLayout":"",
+            "fieldMap":{}
+         },
+         "sampling":{
+            "interval":"1s",
+            "first":0,
+            "thereafter":0,
+            "rateLimit":[]
+         }
+      }
+   ],
+   "logNetwork":[
+      {
+         "logLevel":[
+            "Debug",
+            "Info",
+            "Warning",
+            "Error",
+            "Fatal",
+            "Critical"
+         ],
+         "protocol":"json",
+         "network":"tcp",
+         "host":"",
+         "labels":{},
+         "timeout":"5s",
+         "bufferSize":1024,
+         "spillPath":"",
+         "spillMaxSize":"0B",
+         "batchSize":100,
+         "batchInterval":"1s",
+         "retryMax":3,
+         "retryBackoff":"500ms",
+         "retryBackoffMax":"30s",
+         "disableStack":false,
+         "disableTimestamp":false,
+         "enableTrace":true,
+         "format":{
+            "type":"logfmt",
+            "timestampLayout":"",
+            "fieldMap":{}
+         },
+         "sampling":{
+            "interval":"1s",
+            "first":0,
+            "thereafter":0,
+            "rateLimit":[]
+         }
       }
    ]
 }`)
@@ -58,6 +58,12 @@ type Options struct {
 	// LogSyslog define a list of syslog configuration to allow log to syslog.
 	LogSyslog OptionsSyslogs `json:"logSyslog,omitempty" yaml:"logSyslog,omitempty" toml:"logSyslog,omitempty" mapstructure:"logSyslog,omitempty"`
 
+	// LogNetworkExtend define if the logNetwork given is in addition of default LogNetwork or a replacement.
+	LogNetworkExtend bool `json:"logNetworkExtend,omitempty" yaml:"logNetworkExtend,omitempty" toml:"logNetworkExtend,omitempty" mapstructure:"logNetworkExtend,omitempty"`
+
+	// LogNetwork define a list of network shipping configuration (gelf, json, loki) to allow log to remote endpoints.
+	LogNetwork OptionsNetworks `json:"logNetwork,omitempty" yaml:"logNetwork,omitempty" toml:"logNetwork,omitempty" mapstructure:"logNetwork,omitempty"`
+
 	// default options
 	opts FuncOpt
 }
@@ -104,6 +110,7 @@ func (o *Options) Clone() Options {
 		Stdout:         s,
 		LogFile:        o.LogFile.Clone(),
 		LogSyslog:      o.LogSyslog.Clone(),
+		LogNetwork:     o.LogNetwork.Clone(),
 	}
 }
 
@@ -134,6 +141,12 @@ func (o *Options) Merge(opt *Options) {
 		if opt.Stdout.EnableAccessLog {
 			o.Stdout.EnableAccessLog = opt.Stdout.EnableAccessLog
 		}
+		if len(opt.Stdout.Format.Type) > 0 {
+			o.Stdout.Format = opt.Stdout.Format.Clone()
+		}
+		if opt.Stdout.Sampling.IsEnabled() {
+			o.Stdout.Sampling = opt.Stdout.Sampling.Clone()
+		}
 	}
 
 	if opt.LogFileExtend {
@@ -148,6 +161,12 @@ func (o *Options) Merge(opt *Options) {
 		o.LogSyslog = opt.LogSyslog
 	}
 
+	if opt.LogNetworkExtend {
+		o.LogNetwork = append(o.LogNetwork, opt.LogNetwork...)
+	} else {
+		o.LogNetwork = opt.LogNetwork
+	}
+
 	if opt.opts != nil {
 		o.opts = opt.opts
 	}
@@ -186,6 +205,12 @@ func (o *Options) Options() *Options {
 		if o.Stdout.EnableAccessLog {
 			no.Stdout.EnableAccessLog = o.Stdout.EnableAccessLog
 		}
+		if len(o.Stdout.Format.Type) > 0 {
+			no.Stdout.Format = o.Stdout.Format.Clone()
+		}
+		if o.Stdout.Sampling.IsEnabled() {
+			no.Stdout.Sampling = o.Stdout.Sampling.Clone()
+		}
 	}
 
 	if o.LogFileExtend {
@@ -200,5 +225,11 @@ func (o *Options) Options() *Options {
 		no.LogSyslog = o.LogSyslog
 	}
 
+	if o.LogNetworkExtend {
+		no.LogNetwork = append(no.LogNetwork, o.LogNetwork...)
+	} else {
+		no.LogNetwork = o.LogNetwork
+	}
+
 	return &no
 }
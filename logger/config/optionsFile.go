@@ -65,6 +65,14 @@ type OptionsFile struct {
 
 	// FileBufferSize define the size for buffer size (by default the buffer size is set to 32KB).
 	FileBufferSize libsiz.Size `json:"file-buffer-size,omitempty" yaml:"file-buffer-size,omitempty" toml:"file-buffer-size,omitempty" mapstructure:"file-buffer-size,omitempty"`
+
+	// Rotate define the rotation, retention and compression of the log file.
+	Rotate OptionsRotate `json:"rotate,omitempty" yaml:"rotate,omitempty" toml:"rotate,omitempty" mapstructure:"rotate,omitempty"`
+	// Format define the output format of the log entries.
+	Format OptionsFormat `json:"format,omitempty" yaml:"format,omitempty" toml:"format,omitempty" mapstructure:"format,omitempty"`
+
+	// Sampling define the sampling and rate limiting of repetitive entries.
+	Sampling OptionsSampling `json:"sampling,omitempty" yaml:"sampling,omitempty" toml:"sampling,omitempty" mapstructure:"sampling,omitempty"`
 }
 
 type OptionsFiles []OptionsFile
@@ -81,6 +89,10 @@ func (o OptionsFile) Clone() OptionsFile {
 		DisableTimestamp: o.DisableTimestamp,
 		EnableTrace:      o.EnableTrace,
 		EnableAccessLog:  o.EnableAccessLog,
+		FileBufferSize:   o.FileBufferSize,
+		Rotate:           o.Rotate.Clone(),
+		Format:           o.Format.Clone(),
+		Sampling:         o.Sampling.Clone(),
 	}
 }
 
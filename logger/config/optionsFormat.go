@@ -0,0 +1,57 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package config
+
+type OptionsFormat struct {
+	// Type define the output format : text (default), json, logfmt or ecs (Elastic Common Schema).
+	Type string `json:"type,omitempty" yaml:"type,omitempty" toml:"type,omitempty" mapstructure:"type,omitempty"`
+
+	// TimestampLayout define the go time layout used for the timestamp (by default RFC3339 with nano seconds).
+	TimestampLayout string `json:"timestampLayout,omitempty" yaml:"timestampLayout,omitempty" toml:"timestampLayout,omitempty" mapstructure:"timestampLayout,omitempty"`
+
+	// FieldMap allow to rename the output fields. The key is the field name of the format (time, level, message, ...)
+	// and the value is the new name of the field. With the ECS format, fields are renamed before the ECS conversion.
+	FieldMap map[string]string `json:"fieldMap,omitempty" yaml:"fieldMap,omitempty" toml:"fieldMap,omitempty" mapstructure:"fieldMap,omitempty"`
+}
+
+func (o OptionsFormat) Clone() OptionsFormat {
+	var m map[string]string
+
+	if o.FieldMap != nil {
+		m = make(map[string]string, len(o.FieldMap))
+		for k, v := range o.FieldMap {
+			m[k] = v
+		}
+	}
+
+	return OptionsFormat{
+		Type:            o.Type,
+		TimestampLayout: o.TimestampLayout,
+		FieldMap:        m,
+	}
+}
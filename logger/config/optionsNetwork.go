@@ -0,0 +1,136 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package config
+
+import (
+	libdur "github.com/nabbar/golib/duration"
+	libsiz "github.com/nabbar/golib/size"
+)
+
+type OptionsNetwork struct {
+	// LogLevel define the allowed level of log for this network hook.
+	LogLevel []string `json:"logLevel,omitempty" yaml:"logLevel,omitempty" toml:"logLevel,omitempty" mapstructure:"logLevel,omitempty"`
+
+	// Protocol define the shipping protocol : gelf, json (newline-delimited json) or loki (loki push api).
+	Protocol string `json:"protocol,omitempty" yaml:"protocol,omitempty" toml:"protocol,omitempty" mapstructure:"protocol,omitempty"`
+
+	// Network define the network used to connect to the remote endpoint (tcp or udp), not used for loki.
+	Network string `json:"network,omitempty" yaml:"network,omitempty" toml:"network,omitempty" mapstructure:"network,omitempty"`
+
+	// Host define the remote endpoint : an address 'host:port' for gelf and json or an URL for loki.
+	Host string `json:"host,omitempty" yaml:"host,omitempty" toml:"host,omitempty" mapstructure:"host,omitempty"`
+
+	// Labels define the static labels of the loki stream or the additional fields of gelf / json messages.
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty" toml:"labels,omitempty" mapstructure:"labels,omitempty"`
+
+	// Timeout define the timeout of connection and write to the remote endpoint (default 5s).
+	Timeout libdur.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty" toml:"timeout,omitempty" mapstructure:"timeout,omitempty"`
+
+	// BufferSize define the number of entries kept in memory before spilling to disk or dropping (default 1024).
+	BufferSize int `json:"bufferSize,omitempty" yaml:"bufferSize,omitempty" toml:"bufferSize,omitempty" mapstructure:"bufferSize,omitempty"`
+
+	// SpillPath define the file used to store entries when the memory buffer is full
+	// or the remote endpoint is not reachable. If empty, these entries are dropped.
+	SpillPath string `json:"spillPath,omitempty" yaml:"spillPath,omitempty" toml:"spillPath,omitempty" mapstructure:"spillPath,omitempty"`
+
+	// SpillMaxSize define the maximum size of the spill file (0 for no limit).
+	SpillMaxSize libsiz.Size `json:"spillMaxSize,omitempty" yaml:"spillMaxSize,omitempty" toml:"spillMaxSize,omitempty" mapstructure:"spillMaxSize,omitempty"`
+
+	// BatchSize define the maximum number of entries sent in one batch (default 100).
+	BatchSize int `json:"batchSize,omitempty" yaml:"batchSize,omitempty" toml:"batchSize,omitempty" mapstructure:"batchSize,omitempty"`
+
+	// BatchInterval define the maximum duration an entry wait before being sent (default 1s).
+	BatchInterval libdur.Duration `json:"batchInterval,omitempty" yaml:"batchInterval,omitempty" toml:"batchInterval,omitempty" mapstructure:"batchInterval,omitempty"`
+
+	// RetryMax define the number of retries of a failed batch before spilling or dropping it (default 3).
+	RetryMax int `json:"retryMax,omitempty" yaml:"retryMax,omitempty" toml:"retryMax,omitempty" mapstructure:"retryMax,omitempty"`
+
+	// RetryBackoff define the first delay between two retries, doubled at each retry (default 500ms).
+	RetryBackoff libdur.Duration `json:"retryBackoff,omitempty" yaml:"retryBackoff,omitempty" toml:"retryBackoff,omitempty" mapstructure:"retryBackoff,omitempty"`
+
+	// RetryBackoffMax define the maximum delay between two retries (default 30s).
+	RetryBackoffMax libdur.Duration `json:"retryBackoffMax,omitempty" yaml:"retryBackoffMax,omitempty" toml:"retryBackoffMax,omitempty" mapstructure:"retryBackoffMax,omitempty"`
+
+	// DisableStack allow to disable the goroutine id before each message.
+	DisableStack bool `json:"disableStack,omitempty" yaml:"disableStack,omitempty" toml:"disableStack,omitempty" mapstructure:"disableStack,omitempty"`
+
+	// DisableTimestamp allow to disable the timestamp before each message.
+	DisableTimestamp bool `json:"disableTimestamp,omitempty" yaml:"disableTimestamp,omitempty" toml:"disableTimestamp,omitempty" mapstructure:"disableTimestamp,omitempty"`
+
+	// EnableTrace allow to add the origin caller/file/line of each message.
+	EnableTrace bool `json:"enableTrace,omitempty" yaml:"enableTrace,omitempty" toml:"enableTrace,omitempty" mapstructure:"enableTrace,omitempty"`
+
+	// Format define the output format of the loki log line.
+	Format OptionsFormat `json:"format,omitempty" yaml:"format,omitempty" toml:"format,omitempty" mapstructure:"format,omitempty"`
+
+	// Sampling define the sampling and rate limiting of repetitive entries.
+	Sampling OptionsSampling `json:"sampling,omitempty" yaml:"sampling,omitempty" toml:"sampling,omitempty" mapstructure:"sampling,omitempty"`
+}
+
+type OptionsNetworks []OptionsNetwork
+
+func (o OptionsNetwork) Clone() OptionsNetwork {
+	var l map[string]string
+
+	if o.Labels != nil {
+		l = make(map[string]string, len(o.Labels))
+		for k, v := range o.Labels {
+			l[k] = v
+		}
+	}
+
+	return OptionsNetwork{
+		LogLevel:         o.LogLevel,
+		Protocol:         o.Protocol,
+		Network:          o.Network,
+		Host:             o.Host,
+		Labels:           l,
+		Timeout:          o.Timeout,
+		BufferSize:       o.BufferSize,
+		SpillPath:        o.SpillPath,
+		SpillMaxSize:     o.SpillMaxSize,
+		BatchSize:        o.BatchSize,
+		BatchInterval:    o.BatchInterval,
+		RetryMax:         o.RetryMax,
+		RetryBackoff:     o.RetryBackoff,
+		RetryBackoffMax:  o.RetryBackoffMax,
+		DisableStack:     o.DisableStack,
+		DisableTimestamp: o.DisableTimestamp,
+		EnableTrace:      o.EnableTrace,
+		Format:           o.Format.Clone(),
+		Sampling:         o.Sampling.Clone(),
+	}
+}
+
+func (o OptionsNetworks) Clone() OptionsNetworks {
+	var c = make([]OptionsNetwork, 0)
+	for _, i := range o {
+		c = append(c, i.Clone())
+	}
+	return c
+}
@@ -0,0 +1,78 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package config
+
+import (
+	libdur "github.com/nabbar/golib/duration"
+	libsiz "github.com/nabbar/golib/size"
+)
+
+type OptionsRotate struct {
+	// MaxSize define the size of the log file triggering a rotation (0 to disable).
+	MaxSize libsiz.Size `json:"maxSize,omitempty" yaml:"maxSize,omitempty" toml:"maxSize,omitempty" mapstructure:"maxSize,omitempty"`
+
+	// Interval define the duration between two rotations of the log file (0 to disable).
+	Interval libdur.Duration `json:"interval,omitempty" yaml:"interval,omitempty" toml:"interval,omitempty" mapstructure:"interval,omitempty"`
+
+	// Daily enable the rotation of the log file every day at the hour defined by DailyHour.
+	Daily bool `json:"daily,omitempty" yaml:"daily,omitempty" toml:"daily,omitempty" mapstructure:"daily,omitempty"`
+
+	// DailyHour define the hour (0 to 23, local time) of the daily rotation.
+	DailyHour uint8 `json:"dailyHour,omitempty" yaml:"dailyHour,omitempty" toml:"dailyHour,omitempty" mapstructure:"dailyHour,omitempty"`
+
+	// MaxBackups define the number of rotated files to keep (0 to keep all).
+	MaxBackups int `json:"maxBackups,omitempty" yaml:"maxBackups,omitempty" toml:"maxBackups,omitempty" mapstructure:"maxBackups,omitempty"`
+
+	// MaxAge define the maximum age of the rotated files to keep (0 to keep all).
+	MaxAge libdur.Duration `json:"maxAge,omitempty" yaml:"maxAge,omitempty" toml:"maxAge,omitempty" mapstructure:"maxAge,omitempty"`
+
+	// Compress enable the gzip compression of the rotated files.
+	Compress bool `json:"compress,omitempty" yaml:"compress,omitempty" toml:"compress,omitempty" mapstructure:"compress,omitempty"`
+
+	// ReopenOnSignal keep the log file opened between writes and reopen it on SIGHUP signal.
+	// This mode is used with an external rotation tool like logrotate in create mode.
+	ReopenOnSignal bool `json:"reopenOnSignal,omitempty" yaml:"reopenOnSignal,omitempty" toml:"reopenOnSignal,omitempty" mapstructure:"reopenOnSignal,omitempty"`
+}
+
+// IsEnabled returns true if at least one rotation trigger is defined.
+func (o OptionsRotate) IsEnabled() bool {
+	return o.MaxSize > 0 || o.Interval > 0 || o.Daily
+}
+
+func (o OptionsRotate) Clone() OptionsRotate {
+	return OptionsRotate{
+		MaxSize:        o.MaxSize,
+		Interval:       o.Interval,
+		Daily:          o.Daily,
+		DailyHour:      o.DailyHour,
+		MaxBackups:     o.MaxBackups,
+		MaxAge:         o.MaxAge,
+		Compress:       o.Compress,
+		ReopenOnSignal: o.ReopenOnSignal,
+	}
+}
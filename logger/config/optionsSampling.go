@@ -0,0 +1,88 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package config
+
+import (
+	libdur "github.com/nabbar/golib/duration"
+)
+
+type OptionsSampling struct {
+	// Interval define the sampling window and the delay between two summaries of suppressed entries (default 1s).
+	Interval libdur.Duration `json:"interval,omitempty" yaml:"interval,omitempty" toml:"interval,omitempty" mapstructure:"interval,omitempty"`
+
+	// First define the number of entries with the same level and message allowed in each interval (0 to disable sampling).
+	First int `json:"first,omitempty" yaml:"first,omitempty" toml:"first,omitempty" mapstructure:"first,omitempty"`
+
+	// Thereafter define that only every Mth entry is allowed after the First entries of the interval (0 to suppress all).
+	Thereafter int `json:"thereafter,omitempty" yaml:"thereafter,omitempty" toml:"thereafter,omitempty" mapstructure:"thereafter,omitempty"`
+
+	// RateLimit define a token bucket limiter by level.
+	RateLimit []OptionsRateLimit `json:"rateLimit,omitempty" yaml:"rateLimit,omitempty" toml:"rateLimit,omitempty" mapstructure:"rateLimit,omitempty"`
+}
+
+type OptionsRateLimit struct {
+	// Level define the level of log limited by this limiter.
+	Level string `json:"level,omitempty" yaml:"level,omitempty" toml:"level,omitempty" mapstructure:"level,omitempty"`
+
+	// Rate define the number of entries allowed by second (0 to disable the limiter).
+	Rate float64 `json:"rate,omitempty" yaml:"rate,omitempty" toml:"rate,omitempty" mapstructure:"rate,omitempty"`
+
+	// Burst define the maximum number of entries allowed at once (by default the rate rounded up).
+	Burst int `json:"burst,omitempty" yaml:"burst,omitempty" toml:"burst,omitempty" mapstructure:"burst,omitempty"`
+}
+
+// IsEnabled returns true if the sampling or at least one rate limiter is defined.
+func (o OptionsSampling) IsEnabled() bool {
+	if o.First > 0 {
+		return true
+	}
+
+	for _, l := range o.RateLimit {
+		if l.Rate > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o OptionsSampling) Clone() OptionsSampling {
+	var l []OptionsRateLimit
+
+	if o.RateLimit != nil {
+		l = make([]OptionsRateLimit, len(o.RateLimit))
+		copy(l, o.RateLimit)
+	}
+
+	return OptionsSampling{
+		Interval:   o.Interval,
+		First:      o.First,
+		Thereafter: o.Thereafter,
+		RateLimit:  l,
+	}
+}
@@ -46,6 +46,11 @@ type OptionsStd struct {
 
 	// EnableAccessLog allow to add all message from api router for access log and error log.
 	EnableAccessLog bool `json:"enableAccessLog,omitempty" yaml:"enableAccessLog,omitempty" toml:"enableAccessLog,omitempty" mapstructure:"enableAccessLog,omitempty"`
+	// Format define the output format of the log entries.
+	Format OptionsFormat `json:"format,omitempty" yaml:"format,omitempty" toml:"format,omitempty" mapstructure:"format,omitempty"`
+
+	// Sampling define the sampling and rate limiting of repetitive entries.
+	Sampling OptionsSampling `json:"sampling,omitempty" yaml:"sampling,omitempty" toml:"sampling,omitempty" mapstructure:"sampling,omitempty"`
 }
 
 func (o *OptionsStd) Clone() *OptionsStd {
@@ -56,5 +61,7 @@ func (o *OptionsStd) Clone() *OptionsStd {
 		EnableTrace:      o.EnableTrace,
 		DisableColor:     o.DisableColor,
 		EnableAccessLog:  o.EnableAccessLog,
+		Format:           o.Format.Clone(),
+		Sampling:         o.Sampling.Clone(),
 	}
 }
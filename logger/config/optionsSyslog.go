@@ -59,6 +59,11 @@ type OptionsSyslog struct {
 
 	// EnableAccessLog allow to add all message from api router for access log and error log.
 	EnableAccessLog bool `json:"enableAccessLog,omitempty" yaml:"enableAccessLog,omitempty" toml:"enableAccessLog,omitempty" mapstructure:"enableAccessLog,omitempty"`
+	// Format define the output format of the log entries.
+	Format OptionsFormat `json:"format,omitempty" yaml:"format,omitempty" toml:"format,omitempty" mapstructure:"format,omitempty"`
+
+	// Sampling define the sampling and rate limiting of repetitive entries.
+	Sampling OptionsSampling `json:"sampling,omitempty" yaml:"sampling,omitempty" toml:"sampling,omitempty" mapstructure:"sampling,omitempty"`
 }
 
 type OptionsSyslogs []OptionsSyslog
@@ -74,6 +79,8 @@ func (o OptionsSyslog) Clone() OptionsSyslog {
 		DisableTimestamp: o.DisableTimestamp,
 		EnableTrace:      o.EnableTrace,
 		EnableAccessLog:  o.EnableAccessLog,
+		Format:           o.Format.Clone(),
+		Sampling:         o.Sampling.Clone(),
 	}
 }
 
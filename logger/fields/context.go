@@ -0,0 +1,105 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package fields
+
+import (
+	"context"
+
+	logtps "github.com/nabbar/golib/logger/types"
+)
+
+// ContextKey is the key used to store the fields into a gin context with Set,
+// as a gin context only retrieve string keys from its own storage.
+const ContextKey = "golib-logger-fields"
+
+type ctxKey struct{}
+
+// ContextWith return a copy of the context with the given field added to the fields stored into the context.
+func ContextWith(ctx context.Context, key string, val interface{}) context.Context {
+	return ContextWithFields(ctx, map[string]interface{}{key: val})
+}
+
+// ContextWithFields return a copy of the context with the given fields added to the fields stored into the context.
+func ContextWithFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var res = FromContext(ctx)
+
+	for k, v := range fields {
+		res[k] = v
+	}
+
+	return context.WithValue(ctx, ctxKey{}, res)
+}
+
+// ContextWithRequestID return a copy of the context with the given request ID as field.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return ContextWith(ctx, logtps.FieldRequestID, id)
+}
+
+// ContextWithTrace return a copy of the context with the given trace ID and span ID as fields.
+// Empty values are not added.
+func ContextWithTrace(ctx context.Context, traceID, spanID string) context.Context {
+	var f = make(map[string]interface{})
+
+	if len(traceID) > 0 {
+		f[logtps.FieldTraceID] = traceID
+	}
+
+	if len(spanID) > 0 {
+		f[logtps.FieldSpanID] = spanID
+	}
+
+	return ContextWithFields(ctx, f)
+}
+
+// FromContext return a copy of the fields stored into the context.
+// The returned map is never nil.
+func FromContext(ctx context.Context) map[string]interface{} {
+	var res = make(map[string]interface{})
+
+	if ctx == nil {
+		return res
+	}
+
+	i := ctx.Value(ctxKey{})
+
+	if i == nil {
+		i = ctx.Value(ContextKey)
+	}
+
+	if m, ok := i.(map[string]interface{}); ok {
+		for k, v := range m {
+			res[k] = v
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,98 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package formatter
+
+import (
+	"strings"
+
+	logcfg "github.com/nabbar/golib/logger/config"
+	"github.com/sirupsen/logrus"
+)
+
+// Format define the output format of log entries.
+type Format uint8
+
+const (
+	// Text is the default logrus text format.
+	Text Format = iota
+	// JSON write each entry as a JSON object on one line.
+	JSON
+	// LogFmt write each entry as key=value pairs on one line.
+	LogFmt
+	// ECS write each entry as a JSON object following the Elastic Common Schema.
+	ECS
+)
+
+// ECSVersion is the version of the Elastic Common Schema used by the ECS format.
+const ECSVersion = "8.11.0"
+
+// Parse return the Format matching the given string. If the string is
+// not a valid format, the Text format is returned.
+func Parse(s string) Format {
+	switch {
+	case strings.EqualFold(s, JSON.String()):
+		return JSON
+	case strings.EqualFold(s, LogFmt.String()):
+		return LogFmt
+	case strings.EqualFold(s, ECS.String()):
+		return ECS
+	default:
+		return Text
+	}
+}
+
+func (f Format) String() string {
+	switch f {
+	case JSON:
+		return "json"
+	case LogFmt:
+		return "logfmt"
+	case ECS:
+		return "ecs"
+	default:
+		return "text"
+	}
+}
+
+// New return a logrus formatter for the given options.
+// The text formatter is used for the Text format: if the options define a
+// timestamp layout or a field map, the text formatter is wrapped to apply them.
+func New(opt logcfg.OptionsFormat, text logrus.Formatter) logrus.Formatter {
+	var f = &frm{
+		f: Parse(opt.Type),
+		l: opt.TimestampLayout,
+		m: opt.FieldMap,
+		t: text,
+	}
+
+	if f.f == Text && len(f.l) < 1 && len(f.m) < 1 {
+		return text
+	}
+
+	return f
+}
@@ -0,0 +1,291 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	logtps "github.com/nabbar/golib/logger/types"
+	"github.com/sirupsen/logrus"
+)
+
+type frm struct {
+	f Format            // output format
+	l string            // timestamp layout
+	m map[string]string // field name mapping
+	t logrus.Formatter  // text formatter
+}
+
+func (o *frm) Format(ent *logrus.Entry) ([]byte, error) {
+	switch o.f {
+	case JSON:
+		return o.json(o.rename(o.fields(ent)))
+	case LogFmt:
+		return o.logfmt(o.rename(o.fields(ent)))
+	case ECS:
+		return o.json(o.ecs(o.rename(o.fields(ent))))
+	default:
+		return o.text(ent)
+	}
+}
+
+// fields return a copy of the entry data with the timestamp formatted with the layout.
+// The level and message are retrieved from the logrus entry if missing from data.
+func (o *frm) fields(ent *logrus.Entry) map[string]interface{} {
+	var res = make(map[string]interface{}, len(ent.Data)+2)
+
+	for k, v := range ent.Data {
+		if e, ok := v.(error); ok {
+			res[k] = e.Error()
+		} else {
+			res[k] = v
+		}
+	}
+
+	if _, ok := res[logtps.FieldLevel]; !ok {
+		res[logtps.FieldLevel] = ent.Level.String()
+	}
+
+	if _, ok := res[logtps.FieldMessage]; !ok && len(ent.Message) > 0 {
+		res[logtps.FieldMessage] = ent.Message
+	}
+
+	if v, ok := res[logtps.FieldTime]; ok {
+		res[logtps.FieldTime] = o.time(v)
+	}
+
+	return res
+}
+
+func (o *frm) time(v interface{}) interface{} {
+	var (
+		t time.Time
+		e error
+		l = o.l
+	)
+
+	if len(l) < 1 {
+		l = time.RFC3339Nano
+	}
+
+	switch s := v.(type) {
+	case time.Time:
+		t = s
+	case string:
+		if t, e = time.Parse(time.RFC3339Nano, s); e != nil {
+			return s
+		}
+	default:
+		return v
+	}
+
+	return t.Format(l)
+}
+
+func (o *frm) rename(f map[string]interface{}) map[string]interface{} {
+	if len(o.m) < 1 {
+		return f
+	}
+
+	var res = make(map[string]interface{}, len(f))
+
+	for k, v := range f {
+		if n, ok := o.m[k]; ok && len(n) > 0 {
+			res[n] = v
+		} else {
+			res[k] = v
+		}
+	}
+
+	return res
+}
+
+func (o *frm) name(key string) string {
+	if n, ok := o.m[key]; ok && len(n) > 0 {
+		return n
+	}
+
+	return key
+}
+
+func (o *frm) text(ent *logrus.Entry) ([]byte, error) {
+	var dup = ent.Dup()
+	dup.Level = ent.Level
+	dup.Message = ent.Message
+
+	if v, ok := dup.Data[logtps.FieldTime]; ok {
+		dup.Data[logtps.FieldTime] = o.time(v)
+	}
+
+	dup.Data = o.rename(dup.Data)
+
+	if o.t != nil {
+		return o.t.Format(dup)
+	}
+
+	return dup.Bytes()
+}
+
+func (o *frm) json(f map[string]interface{}) ([]byte, error) {
+	var (
+		buf = bytes.NewBuffer(make([]byte, 0, 512))
+		enc = json.NewEncoder(buf)
+	)
+
+	enc.SetEscapeHTML(false)
+
+	if e := enc.Encode(f); e != nil {
+		return nil, fmt.Errorf("failed to marshal fields to JSON, %w", e)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (o *frm) logfmt(f map[string]interface{}) ([]byte, error) {
+	var (
+		buf = bytes.NewBuffer(make([]byte, 0, 512))
+		key = make([]string, 0, len(f))
+		fst = []string{
+			o.name(logtps.FieldTime),
+			o.name(logtps.FieldLevel),
+			o.name(logtps.FieldMessage),
+		}
+	)
+
+	for k := range f {
+		if k != fst[0] && k != fst[1] && k != fst[2] {
+			key = append(key, k)
+		}
+	}
+
+	sort.Strings(key)
+
+	for _, k := range append(fst, key...) {
+		v, ok := f[k]
+
+		if !ok {
+			continue
+		}
+
+		if buf.Len() > 0 {
+			buf.WriteByte(' ')
+		}
+
+		buf.WriteString(logfmtKey(k))
+		buf.WriteByte('=')
+		buf.WriteString(logfmtValue(v))
+	}
+
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+func logfmtKey(k string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' {
+			return '_'
+		}
+		return r
+	}, k)
+}
+
+func logfmtValue(v interface{}) string {
+	var s string
+
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		s = t
+	case bool:
+		return strconv.FormatBool(t)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(t)
+	case error:
+		s = t.Error()
+	case fmt.Stringer:
+		s = t.String()
+	default:
+		if p, e := json.Marshal(t); e == nil {
+			s = string(p)
+		} else {
+			s = fmt.Sprintf("%v", t)
+		}
+	}
+
+	if s == "" {
+		return `""`
+	}
+
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || !unicode.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+	}
+
+	return s
+}
+
+// ecs convert the fields name to the Elastic Common Schema fields.
+func (o *frm) ecs(f map[string]interface{}) map[string]interface{} {
+	var res = make(map[string]interface{}, len(f)+1)
+
+	res["ecs.version"] = ECSVersion
+
+	for k, v := range f {
+		switch k {
+		case logtps.FieldTime:
+			res["@timestamp"] = v
+		case logtps.FieldLevel:
+			res["log.level"] = strings.ToLower(fmt.Sprint(v))
+		case logtps.FieldMessage:
+			res["message"] = v
+		case logtps.FieldError:
+			res["error.message"] = v
+		case logtps.FieldCaller:
+			res["log.origin.function"] = v
+		case logtps.FieldFile:
+			res["log.origin.file.name"] = v
+		case logtps.FieldLine:
+			res["log.origin.file.line"] = v
+		case logtps.FieldStack:
+			res["process.thread.id"] = v
+		default:
+			res[k] = v
+		}
+	}
+
+	return res
+}
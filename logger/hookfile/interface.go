@@ -31,6 +31,7 @@ import (
 	"io"
 	"os"
 	"sync/atomic"
+	"time"
 
 	libiot "github.com/nabbar/golib/ioutils"
 	logcfg "github.com/nabbar/golib/logger/config"
@@ -64,7 +65,8 @@ func New(opt logcfg.OptionsFile, format logrus.Formatter) (HookFile, error) {
 		LVLs = logrus.AllLevels
 	}
 
-	if opt.Create {
+	// the log file must be recreated after a rotation
+	if opt.Create || opt.Rotate.IsEnabled() {
 		flags = os.O_CREATE | flags
 	}
 
@@ -92,9 +94,13 @@ func New(opt logcfg.OptionsFile, format logrus.Formatter) (HookFile, error) {
 			filepath:         opt.Filepath,
 			fileMode:         opt.FileMode.FileMode(),
 			pathMode:         opt.PathMode.FileMode(),
+			rotate:           opt.Rotate.Clone(),
 		},
+		r: &rtt{},
 	}
 
+	n.r.n = n.nextRotate(time.Now())
+
 	if opt.FileBufferSize <= libsiz.SizeKilo {
 		n.b.Store(opt.FileBufferSize.Int64())
 	} else {
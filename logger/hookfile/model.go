@@ -32,6 +32,7 @@ import (
 	"strings"
 	"sync/atomic"
 
+	logcfg "github.com/nabbar/golib/logger/config"
 	logtps "github.com/nabbar/golib/logger/types"
 	"github.com/sirupsen/logrus"
 )
@@ -48,6 +49,7 @@ type ohkf struct {
 	filepath         string
 	fileMode         os.FileMode
 	pathMode         os.FileMode
+	rotate           logcfg.OptionsRotate
 }
 
 type hkf struct {
@@ -55,6 +57,7 @@ type hkf struct {
 	d *atomic.Value // channel data []byte
 	o ohkf          // config data
 	b *atomic.Int64 // buffer size
+	r *rtt          // rotation state, only used by the run function
 }
 
 func (o *hkf) Levels() []logrus.Level {
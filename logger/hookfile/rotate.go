@@ -0,0 +1,303 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package hookfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	arccmp "github.com/nabbar/golib/archive/compress"
+	libiot "github.com/nabbar/golib/ioutils"
+	logcfg "github.com/nabbar/golib/logger/config"
+	libsrv "github.com/nabbar/golib/server"
+)
+
+const rotateFormat = "20060102-150405"
+
+var rotateSuffix = regexp.MustCompile(`^\d{8}-\d{6}(-\d+)?(\.gz)?$`)
+
+type rtt struct {
+	h *os.File   // log file kept opened between writes (reopen on signal mode)
+	n time.Time  // next time based rotation
+	m sync.Mutex // serialize compression and retention of rotated files
+}
+
+func (o *hkf) getRotate() logcfg.OptionsRotate {
+	return o.o.rotate
+}
+
+func (o *hkf) keepOpen() bool {
+	return o.o.rotate.ReopenOnSignal
+}
+
+func (o *hkf) openFile() (*os.File, error) {
+	if o.r.h != nil {
+		return o.r.h, nil
+	}
+
+	var (
+		p = o.getFilepath()
+		m = o.getFileMode()
+		n = o.getPathMode()
+	)
+
+	if o.getCreatePath() {
+		if e := libiot.PathCheckCreate(true, p, m, n); e != nil {
+			return nil, e
+		}
+	}
+
+	// #nosec
+	h, e := os.OpenFile(p, o.getFlags(), m)
+
+	if e != nil {
+		return nil, e
+	} else if o.keepOpen() {
+		o.r.h = h
+	}
+
+	return h, nil
+}
+
+func (o *hkf) closeFile() {
+	if o.r.h != nil {
+		_ = o.r.h.Close()
+		o.r.h = nil
+	}
+}
+
+func (o *hkf) isRotateSize(size int64, n int) bool {
+	var m = o.getRotate().MaxSize
+	return m > 0 && size > 0 && uint64(size)+uint64(n) > m.Uint64()
+}
+
+func (o *hkf) isRotateTime(now time.Time) bool {
+	return !o.r.n.IsZero() && !now.Before(o.r.n)
+}
+
+func (o *hkf) nextRotate(now time.Time) time.Time {
+	var (
+		r = o.getRotate()
+		n time.Time
+	)
+
+	if r.Interval > 0 {
+		n = now.Add(r.Interval.Time())
+	}
+
+	if r.Daily {
+		d := time.Date(now.Year(), now.Month(), now.Day(), int(r.DailyHour%24), 0, 0, 0, now.Location())
+
+		if !d.After(now) {
+			d = d.AddDate(0, 0, 1)
+		}
+
+		if n.IsZero() || d.Before(n) {
+			n = d
+		}
+	}
+
+	return n
+}
+
+// rotate renames the current log file with a timestamp suffix and starts
+// the compression and retention of rotated files in background.
+func (o *hkf) rotate() error {
+	var (
+		now = time.Now()
+		pth = o.getFilepath()
+	)
+
+	o.closeFile()
+	o.r.n = o.nextRotate(now)
+
+	if i, e := os.Stat(pth); errors.Is(e, fs.ErrNotExist) {
+		return nil
+	} else if e != nil {
+		return e
+	} else if i.Size() < 1 {
+		return nil
+	}
+
+	dst := o.rotateName(pth, now)
+
+	if e := os.Rename(pth, dst); e != nil {
+		return e
+	}
+
+	go o.rotateClean(dst)
+	return nil
+}
+
+func (o *hkf) rotateName(pth string, now time.Time) string {
+	var (
+		b = pth + "." + now.Format(rotateFormat)
+		n = b
+	)
+
+	for i := 1; o.rotateExists(n); i++ {
+		n = b + "-" + strconv.Itoa(i)
+	}
+
+	return n
+}
+
+func (o *hkf) rotateExists(pth string) bool {
+	if _, e := os.Stat(pth); e == nil {
+		return true
+	} else if _, e = os.Stat(pth + arccmp.Gzip.Extension()); e == nil {
+		return true
+	}
+
+	return false
+}
+
+func (o *hkf) rotateClean(pth string) {
+	defer func() {
+		libsrv.RecoveryCaller("golib/logger/hookfile/rotate", recover(), fmt.Sprintf("log file: %s", o.getFilepath()))
+	}()
+
+	o.r.m.Lock()
+	defer o.r.m.Unlock()
+
+	if o.getRotate().Compress {
+		if e := o.rotateCompress(pth); e != nil {
+			fmt.Println(e.Error())
+		}
+	}
+
+	if e := o.rotatePurge(time.Now()); e != nil {
+		fmt.Println(e.Error())
+	}
+}
+
+func (o *hkf) rotateCompress(pth string) error {
+	var (
+		e error
+		s *os.File
+		d *os.File
+		w io.WriteCloser
+		n = pth + arccmp.Gzip.Extension()
+	)
+
+	// #nosec
+	if s, e = os.Open(pth); e != nil {
+		return e
+	}
+
+	defer func() {
+		_ = s.Close()
+	}()
+
+	// #nosec
+	if d, e = os.OpenFile(n, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, o.getFileMode()); e != nil {
+		return e
+	}
+
+	if w, e = arccmp.Gzip.Writer(d); e == nil {
+		if _, e = io.Copy(w, s); e == nil {
+			e = w.Close()
+		} else {
+			_ = w.Close()
+		}
+	}
+
+	if er := d.Close(); e == nil {
+		e = er
+	}
+
+	if e != nil {
+		_ = os.Remove(n)
+		return e
+	}
+
+	_ = s.Close()
+	return os.Remove(pth)
+}
+
+// rotatePurge removes the rotated files exceeding the max backups count or the max age.
+func (o *hkf) rotatePurge(now time.Time) error {
+	var (
+		r = o.getRotate()
+		p = o.getFilepath()
+		b = filepath.Base(p) + "."
+		l []fs.DirEntry
+		e error
+	)
+
+	if r.MaxBackups < 1 && r.MaxAge < 1 {
+		return nil
+	} else if l, e = os.ReadDir(filepath.Dir(p)); e != nil {
+		return e
+	}
+
+	var res = make([]fs.DirEntry, 0)
+
+	for _, f := range l {
+		if f.IsDir() || !strings.HasPrefix(f.Name(), b) {
+			continue
+		} else if !rotateSuffix.MatchString(strings.TrimPrefix(f.Name(), b)) {
+			continue
+		}
+
+		res = append(res, f)
+	}
+
+	// newest first, as the suffix is a sortable timestamp
+	sort.Slice(res, func(i, j int) bool {
+		return strings.TrimSuffix(res[i].Name(), arccmp.Gzip.Extension()) > strings.TrimSuffix(res[j].Name(), arccmp.Gzip.Extension())
+	})
+
+	for k, f := range res {
+		var del = r.MaxBackups > 0 && k >= r.MaxBackups
+
+		if !del && r.MaxAge > 0 {
+			if i, err := f.Info(); err == nil && now.Sub(i.ModTime()) > r.MaxAge.Time() {
+				del = true
+			}
+		}
+
+		if del {
+			if err := os.Remove(filepath.Join(filepath.Dir(p), f.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				e = err
+			}
+		}
+	}
+
+	return e
+}
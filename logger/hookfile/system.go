@@ -34,9 +34,10 @@ import (
 	"io"
 	"math"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
-	libiot "github.com/nabbar/golib/ioutils"
 	libsrv "github.com/nabbar/golib/server"
 )
 
@@ -68,38 +69,47 @@ func (o *hkf) writeBuffer(buf *bytes.Buffer) error {
 	var (
 		e error
 		h *os.File
-		p = o.getFilepath()
-		m = o.getFileMode()
-		n = o.getPathMode()
-		f = o.getFlags()
+		s int64
 		b = o.newBuffer(0)
 	)
 
-	if o.getCreatePath() {
-		if e = libiot.PathCheckCreate(true, p, m, n); e != nil {
-			return e
-		}
-	}
-
 	defer func() {
 		libsrv.RecoveryCaller("golib/logger/hookfile/system", recover())
-		if h != nil {
+		if e != nil && o.keepOpen() {
+			o.closeFile()
+		} else if h != nil && !o.keepOpen() {
 			_ = h.Close()
 		}
 	}()
 
-	// #nosec
-	h, e = os.OpenFile(p, f, m)
-
-	if e != nil {
+	if h, e = o.openFile(); e != nil {
 		return e
-	} else if _, e = h.Seek(0, io.SeekEnd); e != nil {
+	} else if s, e = h.Seek(0, io.SeekEnd); e != nil {
 		return e
-	} else if _, e = h.Write(buf.Bytes()); e != nil {
+	} else if o.isRotateSize(s, buf.Len()) {
+		if !o.keepOpen() {
+			_ = h.Close()
+		}
+
+		h = nil
+
+		if e = o.rotate(); e != nil {
+			return e
+		} else if h, e = o.openFile(); e != nil {
+			return e
+		}
+	}
+
+	if _, e = h.Write(buf.Bytes()); e != nil {
 		return e
 	}
 
 	*buf = *b
+
+	if o.keepOpen() {
+		return nil
+	}
+
 	e = h.Close()
 	h = nil
 
@@ -140,8 +150,16 @@ func (o *hkf) Run(ctx context.Context) {
 			}
 			b.Reset()
 		}
+		o.closeFile()
 	}()
 
+	var sig = make(chan os.Signal, 1)
+
+	if o.keepOpen() {
+		signal.Notify(sig, syscall.SIGHUP)
+		defer signal.Stop(sig)
+	}
+
 	o.prepareChan()
 	//fmt.Printf("starting hook for log file '%s'\n", o.getFilepath())
 
@@ -153,7 +171,22 @@ func (o *hkf) Run(ctx context.Context) {
 		case <-o.Done():
 			return
 
-		case <-t.C:
+		case <-sig:
+			// file will be reopened on next write
+			o.closeFile()
+
+		case n := <-t.C:
+			if o.isRotateTime(n) {
+				if b.Len() > 0 {
+					if e = o.writeBuffer(b); e != nil {
+						fmt.Println(e.Error())
+					}
+				}
+				if e = o.rotate(); e != nil {
+					fmt.Println(e.Error())
+				}
+			}
+
 			if b.Len() < 1 {
 				continue
 			} else if e = o.writeBuffer(b); e != nil {
@@ -0,0 +1,157 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package hooknetwork
+
+import (
+	"encoding/json"
+	"strings"
+
+	logtps "github.com/nabbar/golib/logger/types"
+	"github.com/sirupsen/logrus"
+)
+
+type encJSON struct {
+	l map[string]string
+}
+
+func newJSON(labels map[string]string) encoder {
+	return &encJSON{
+		l: labels,
+	}
+}
+
+func (o *encJSON) encode(r *rec) ([]byte, error) {
+	var f = make(map[string]interface{}, len(r.F)+len(o.l))
+
+	for k, v := range o.l {
+		f[k] = v
+	}
+
+	for k, v := range r.F {
+		f[k] = v
+	}
+
+	return json.Marshal(f)
+}
+
+func (o *encJSON) delimiter() byte {
+	return '\n'
+}
+
+func (o *encJSON) chunk(p []byte) ([][]byte, error) {
+	return [][]byte{append(p, '\n')}, nil
+}
+
+type encGELF struct {
+	h string
+	l map[string]string
+}
+
+func newGelf(hostname string, labels map[string]string) encoder {
+	return &encGELF{
+		h: hostname,
+		l: labels,
+	}
+}
+
+func (o *encGELF) encode(r *rec) ([]byte, error) {
+	var (
+		m = r.message()
+		f = make(map[string]interface{}, len(r.F)+len(o.l)+5)
+	)
+
+	if len(m) < 1 {
+		m = r.L.String()
+	}
+
+	for k, v := range o.l {
+		f[gelfKey(k)] = v
+	}
+
+	for k, v := range r.F {
+		switch k {
+		case logtps.FieldTime, logtps.FieldLevel, logtps.FieldMessage:
+			continue
+		default:
+			f[gelfKey(k)] = v
+		}
+	}
+
+	f["version"] = "1.1"
+	f["host"] = o.h
+	f["short_message"] = m
+	f["timestamp"] = float64(r.T/1e6) / 1e3
+	f["level"] = gelfLevel(r.L)
+
+	return json.Marshal(f)
+}
+
+func (o *encGELF) delimiter() byte {
+	return 0
+}
+
+func (o *encGELF) chunk(p []byte) ([][]byte, error) {
+	return gelfChunk(p)
+}
+
+// gelfKey return the name of an additional field : prefixed by an underscore
+// and with only allowed chars. The field '_id' is reserved by GELF.
+func gelfKey(k string) string {
+	k = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, k)
+
+	if k == "id" {
+		return "_id_"
+	}
+
+	return "_" + k
+}
+
+// gelfLevel return the syslog severity of the level.
+func gelfLevel(l logrus.Level) int {
+	switch l {
+	case logrus.PanicLevel:
+		return 1
+	case logrus.FatalLevel:
+		return 2
+	case logrus.ErrorLevel:
+		return 3
+	case logrus.WarnLevel:
+		return 4
+	case logrus.InfoLevel:
+		return 6
+	default:
+		return 7
+	}
+}
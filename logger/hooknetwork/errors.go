@@ -0,0 +1,49 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package hooknetwork
+
+import "fmt"
+
+var (
+	errStreamClosed    = fmt.Errorf("stream is closed")
+	errMissingHost     = fmt.Errorf("missing remote host")
+	errInvalidNetwork  = fmt.Errorf("invalid network, must be tcp or udp")
+	errInvalidEndpoint = fmt.Errorf("invalid loki endpoint, must be an http or https URL")
+	errSpillFull       = fmt.Errorf("spill file is full")
+	errMessageTooLarge = fmt.Errorf("message too large to be chunked")
+)
+
+// errPermanent is returned by a sender when the batch is refused by the remote
+// endpoint and must not be retried.
+type errPermanent struct {
+	error
+}
+
+func (e errPermanent) Unwrap() error {
+	return e.error
+}
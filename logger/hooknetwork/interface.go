@@ -0,0 +1,186 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package hooknetwork
+
+import (
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	logcfg "github.com/nabbar/golib/logger/config"
+	loglvl "github.com/nabbar/golib/logger/level"
+	logtps "github.com/nabbar/golib/logger/types"
+	libptc "github.com/nabbar/golib/network/protocol"
+	"github.com/sirupsen/logrus"
+)
+
+// Protocol define the shipping protocol of the network hook.
+type Protocol uint8
+
+const (
+	// ProtocolJSON ship each entry as a newline-delimited JSON object over TCP or UDP.
+	ProtocolJSON Protocol = iota
+	// ProtocolGELF ship each entry as a GELF 1.1 message over TCP (null byte delimited) or UDP (chunked).
+	ProtocolGELF
+	// ProtocolLoki ship batches of entries to a Loki push API endpoint over HTTP.
+	ProtocolLoki
+)
+
+const (
+	defaultTimeout         = 5 * time.Second
+	defaultBufferSize      = 1024
+	defaultBatchSize       = 100
+	defaultBatchInterval   = time.Second
+	defaultRetryMax        = 3
+	defaultRetryBackoff    = 500 * time.Millisecond
+	defaultRetryBackoffMax = 30 * time.Second
+)
+
+// ParseProtocol return the protocol matching the given string or ProtocolJSON if not matching.
+func ParseProtocol(s string) Protocol {
+	switch {
+	case strings.EqualFold(s, ProtocolGELF.String()):
+		return ProtocolGELF
+	case strings.EqualFold(s, ProtocolLoki.String()):
+		return ProtocolLoki
+	default:
+		return ProtocolJSON
+	}
+}
+
+func (p Protocol) String() string {
+	switch p {
+	case ProtocolGELF:
+		return "gelf"
+	case ProtocolLoki:
+		return "loki"
+	default:
+		return "json"
+	}
+}
+
+// Stats is a snapshot of the counters of the network hook.
+type Stats struct {
+	// Sent is the number of entries successfully shipped.
+	Sent uint64
+	// Retried is the number of retries of failed batches.
+	Retried uint64
+	// Spilled is the number of entries written into the spill file.
+	Spilled uint64
+	// Dropped is the number of entries lost because the buffer and spill file were full
+	// or the batch failed after all retries without spill file.
+	Dropped uint64
+}
+
+type HookNetwork interface {
+	logtps.Hook
+
+	Done() <-chan struct{}
+	Stats() Stats
+}
+
+func New(opt logcfg.OptionsNetwork, format logrus.Formatter) (HookNetwork, error) {
+	var (
+		LVLs = make([]logrus.Level, 0)
+		prt  = ParseProtocol(opt.Protocol)
+		ntw  = libptc.Parse(opt.Network)
+	)
+
+	if len(opt.Host) < 1 {
+		return nil, errMissingHost
+	}
+
+	if prt == ProtocolLoki {
+		if u, e := url.Parse(opt.Host); e != nil {
+			return nil, e
+		} else if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, errInvalidEndpoint
+		}
+	} else {
+		switch ntw {
+		case libptc.NetworkTCP, libptc.NetworkTCP4, libptc.NetworkTCP6, libptc.NetworkUDP, libptc.NetworkUDP4, libptc.NetworkUDP6:
+		case libptc.NetworkEmpty:
+			ntw = libptc.NetworkTCP
+		default:
+			return nil, errInvalidNetwork
+		}
+	}
+
+	if len(opt.LogLevel) > 0 {
+		for _, ls := range opt.LogLevel {
+			LVLs = append(LVLs, loglvl.Parse(ls).Logrus())
+		}
+	} else {
+		LVLs = logrus.AllLevels
+	}
+
+	n := &hkn{
+		s: new(atomic.Value),
+		c: new(atomic.Bool),
+		o: ohkn{
+			format:           format,
+			levels:           LVLs,
+			disableStack:     opt.DisableStack,
+			disableTimestamp: opt.DisableTimestamp,
+			enableTrace:      opt.EnableTrace,
+			protocol:         prt,
+			network:          ntw,
+			endpoint:         opt.Host,
+			labels:           opt.Clone().Labels,
+			timeout:          opt.Timeout.Time(),
+			bufferSize:       opt.BufferSize,
+			batchSize:        opt.BatchSize,
+			batchInterval:    opt.BatchInterval.Time(),
+			retryMax:         opt.RetryMax,
+			retryBackoff:     opt.RetryBackoff.Time(),
+			retryBackoffMax:  opt.RetryBackoffMax.Time(),
+		},
+	}
+
+	n.o.setDefault()
+
+	if h, e := os.Hostname(); e == nil {
+		n.o.hostname = h
+	}
+
+	if len(opt.SpillPath) > 0 {
+		if p, e := newSpill(opt.SpillPath, opt.SpillMaxSize.Int64()); e != nil {
+			return nil, e
+		} else {
+			n.p = p
+		}
+	}
+
+	n.q = make(chan *rec, n.o.bufferSize)
+	n.w = n.o.newSender()
+	n.s.Store(make(chan struct{}))
+
+	return n, nil
+}
@@ -0,0 +1,166 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package hooknetwork
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const lokiPushPath = "/loki/api/v1/push"
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+type lokiPush struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type loki struct {
+	u string
+	l map[string]string
+	c *http.Client
+}
+
+func newLoki(endpoint string, labels map[string]string, tmo time.Duration) sender {
+	if u, e := url.Parse(endpoint); e == nil && (u.Path == "" || u.Path == "/") {
+		u.Path = lokiPushPath
+		endpoint = u.String()
+	}
+
+	return &loki{
+		u: endpoint,
+		l: labels,
+		c: &http.Client{
+			Timeout: tmo,
+		},
+	}
+}
+
+// push build the loki push request with one stream by level.
+func (o *loki) push(b []*rec) lokiPush {
+	var (
+		res = lokiPush{}
+		idx = make(map[string]int)
+	)
+
+	for _, r := range b {
+		var (
+			l = r.L.String()
+			m = r.M
+		)
+
+		if len(m) < 1 {
+			m = r.message()
+		}
+
+		i, ok := idx[l]
+
+		if !ok {
+			var s = make(map[string]string, len(o.l)+1)
+
+			for k, v := range o.l {
+				s[k] = v
+			}
+
+			s["level"] = l
+
+			i = len(res.Streams)
+			idx[l] = i
+			res.Streams = append(res.Streams, lokiStream{Stream: s})
+		}
+
+		res.Streams[i].Values = append(res.Streams[i].Values, [2]string{strconv.FormatInt(r.T, 10), m})
+	}
+
+	for i := range res.Streams {
+		v := res.Streams[i].Values
+		sort.SliceStable(v, func(a, b int) bool {
+			return len(v[a][0]) < len(v[b][0]) || (len(v[a][0]) == len(v[b][0]) && v[a][0] < v[b][0])
+		})
+	}
+
+	return res
+}
+
+func (o *loki) send(b []*rec) error {
+	p, e := json.Marshal(o.push(b))
+
+	if e != nil {
+		return errPermanent{e}
+	}
+
+	req, e := http.NewRequestWithContext(context.Background(), http.MethodPost, o.u, bytes.NewReader(p))
+
+	if e != nil {
+		return errPermanent{e}
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	rsp, e := o.c.Do(req)
+
+	if e != nil {
+		return e
+	}
+
+	defer func() {
+		_ = rsp.Body.Close()
+	}()
+
+	if rsp.StatusCode >= 200 && rsp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, rsp.Body)
+		return nil
+	}
+
+	m, _ := io.ReadAll(io.LimitReader(rsp.Body, 512))
+	e = fmt.Errorf("loki push refused with status %d: %s", rsp.StatusCode, strings.TrimSpace(string(m)))
+
+	// client errors except too many request will fail again
+	if rsp.StatusCode >= 400 && rsp.StatusCode < 500 && rsp.StatusCode != http.StatusTooManyRequests {
+		return errPermanent{e}
+	}
+
+	return e
+}
+
+func (o *loki) close() error {
+	o.c.CloseIdleConnections()
+	return nil
+}
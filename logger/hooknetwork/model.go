@@ -0,0 +1,255 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package hooknetwork
+
+import (
+	"bytes"
+	"sync/atomic"
+	"time"
+
+	logtps "github.com/nabbar/golib/logger/types"
+	libptc "github.com/nabbar/golib/network/protocol"
+	"github.com/sirupsen/logrus"
+)
+
+var closeStruct = make(chan struct{})
+
+func init() {
+	close(closeStruct)
+}
+
+type ohkn struct {
+	format           logrus.Formatter
+	levels           []logrus.Level
+	disableStack     bool
+	disableTimestamp bool
+	enableTrace      bool
+
+	protocol Protocol
+	network  libptc.NetworkProtocol
+	endpoint string
+	labels   map[string]string
+	hostname string
+	timeout  time.Duration
+
+	bufferSize      int
+	batchSize       int
+	batchInterval   time.Duration
+	retryMax        int
+	retryBackoff    time.Duration
+	retryBackoffMax time.Duration
+}
+
+func (o *ohkn) setDefault() {
+	if o.timeout <= 0 {
+		o.timeout = defaultTimeout
+	}
+
+	if o.bufferSize <= 0 {
+		o.bufferSize = defaultBufferSize
+	}
+
+	if o.batchSize <= 0 {
+		o.batchSize = defaultBatchSize
+	}
+
+	if o.batchInterval <= 0 {
+		o.batchInterval = defaultBatchInterval
+	}
+
+	if o.retryMax == 0 {
+		o.retryMax = defaultRetryMax
+	} else if o.retryMax < 0 {
+		o.retryMax = 0
+	}
+
+	if o.retryBackoff <= 0 {
+		o.retryBackoff = defaultRetryBackoff
+	}
+
+	if o.retryBackoffMax <= 0 {
+		o.retryBackoffMax = defaultRetryBackoffMax
+	}
+
+	if o.retryBackoffMax < o.retryBackoff {
+		o.retryBackoffMax = o.retryBackoff
+	}
+}
+
+func (o *ohkn) newSender() sender {
+	switch o.protocol {
+	case ProtocolLoki:
+		return newLoki(o.endpoint, o.labels, o.timeout)
+	case ProtocolGELF:
+		return newStream(o.network, o.endpoint, o.timeout, newGelf(o.hostname, o.labels))
+	default:
+		return newStream(o.network, o.endpoint, o.timeout, newJSON(o.labels))
+	}
+}
+
+type hkn struct {
+	s *atomic.Value // channel stop struct{}
+	c *atomic.Bool  // is closed
+	o ohkn          // config data
+	q chan *rec     // memory buffer
+	p *spill        // disk spill buffer, nil if disabled
+	w sender        // protocol sender
+
+	cntSent    atomic.Uint64
+	cntRetried atomic.Uint64
+	cntSpilled atomic.Uint64
+	cntDropped atomic.Uint64
+}
+
+func (o *hkn) Levels() []logrus.Level {
+	return o.o.levels
+}
+
+func (o *hkn) RegisterHook(log *logrus.Logger) {
+	log.AddHook(o)
+}
+
+func (o *hkn) Fire(entry *logrus.Entry) error {
+	ent := entry.Dup()
+	ent.Level = entry.Level
+
+	if o.o.disableStack {
+		ent.Data = o.filterKey(ent.Data, logtps.FieldStack)
+	}
+
+	if o.o.disableTimestamp {
+		ent.Data = o.filterKey(ent.Data, logtps.FieldTime)
+	}
+
+	if !o.o.enableTrace {
+		ent.Data = o.filterKey(ent.Data, logtps.FieldCaller)
+		ent.Data = o.filterKey(ent.Data, logtps.FieldFile)
+		ent.Data = o.filterKey(ent.Data, logtps.FieldLine)
+	}
+
+	if len(ent.Data) < 1 {
+		return nil
+	}
+
+	var (
+		r = newRec(ent)
+		p []byte
+		e error
+	)
+
+	if o.o.protocol == ProtocolLoki {
+		if f := o.o.format; f != nil {
+			p, e = f.Format(ent)
+		} else {
+			p, e = ent.Bytes()
+		}
+
+		if e != nil {
+			return e
+		}
+
+		r.M = string(bytes.TrimRight(p, "\r\n"))
+	}
+
+	return o.push(r)
+}
+
+func (o *hkn) Write(p []byte) (n int, err error) {
+	if e := o.push(newRecMessage(logrus.InfoLevel, string(bytes.TrimRight(p, "\r\n")))); e != nil {
+		return 0, e
+	}
+
+	return len(p), nil
+}
+
+func (o *hkn) Close() error {
+	if o.c.Swap(true) {
+		return nil
+	}
+
+	o.s.Store(closeStruct)
+	return nil
+}
+
+func (o *hkn) Done() <-chan struct{} {
+	c := o.s.Load()
+
+	if c != nil {
+		return c.(chan struct{})
+	}
+
+	return closeStruct
+}
+
+func (o *hkn) Stats() Stats {
+	return Stats{
+		Sent:    o.cntSent.Load(),
+		Retried: o.cntRetried.Load(),
+		Spilled: o.cntSpilled.Load(),
+		Dropped: o.cntDropped.Load(),
+	}
+}
+
+// push add the record into the memory buffer, or into the spill file if the
+// memory buffer is full. If both are full, the record is dropped.
+func (o *hkn) push(r *rec) error {
+	if o.c.Load() {
+		return errStreamClosed
+	}
+
+	select {
+	case o.q <- r:
+	default:
+		o.spill(r)
+	}
+
+	return nil
+}
+
+func (o *hkn) spill(r ...*rec) {
+	for _, i := range r {
+		if o.p != nil && o.p.push(i) == nil {
+			o.cntSpilled.Add(1)
+		} else {
+			o.cntDropped.Add(1)
+		}
+	}
+}
+
+func (o *hkn) filterKey(f logrus.Fields, key string) logrus.Fields {
+	if len(f) < 1 {
+		return f
+	}
+
+	if _, ok := f[key]; !ok {
+		return f
+	} else {
+		delete(f, key)
+		return f
+	}
+}
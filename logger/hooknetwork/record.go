@@ -0,0 +1,106 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package hooknetwork
+
+import (
+	"time"
+
+	logtps "github.com/nabbar/golib/logger/types"
+	"github.com/sirupsen/logrus"
+)
+
+// rec is a log entry waiting to be shipped. It is stored as a JSON line into the spill file.
+type rec struct {
+	T int64                  `json:"t"`           // timestamp in unix nano seconds
+	L logrus.Level           `json:"l"`           // level of the entry
+	M string                 `json:"m,omitempty"` // formatted line (loki only)
+	F map[string]interface{} `json:"f"`           // fields of the entry
+}
+
+func newRec(ent *logrus.Entry) *rec {
+	var r = &rec{
+		L: ent.Level,
+		F: make(map[string]interface{}, len(ent.Data)),
+	}
+
+	if !ent.Time.IsZero() {
+		r.T = ent.Time.UnixNano()
+	}
+
+	for k, v := range ent.Data {
+		if e, ok := v.(error); ok {
+			r.F[k] = e.Error()
+		} else {
+			r.F[k] = v
+		}
+	}
+
+	if s, ok := r.F[logtps.FieldTime].(string); ok {
+		if t, e := time.Parse(time.RFC3339Nano, s); e == nil {
+			r.T = t.UnixNano()
+		}
+	}
+
+	if r.T == 0 {
+		r.T = time.Now().UnixNano()
+	}
+
+	if _, ok := r.F[logtps.FieldLevel]; !ok {
+		r.F[logtps.FieldLevel] = ent.Level.String()
+	}
+
+	if _, ok := r.F[logtps.FieldMessage]; !ok && len(ent.Message) > 0 {
+		r.F[logtps.FieldMessage] = ent.Message
+	}
+
+	return r
+}
+
+func newRecMessage(lvl logrus.Level, msg string) *rec {
+	var t = time.Now()
+
+	return &rec{
+		T: t.UnixNano(),
+		L: lvl,
+		M: msg,
+		F: map[string]interface{}{
+			logtps.FieldTime:    t.Format(time.RFC3339Nano),
+			logtps.FieldLevel:   lvl.String(),
+			logtps.FieldMessage: msg,
+		},
+	}
+}
+
+// message return the message of the record.
+func (r *rec) message() string {
+	if s, ok := r.F[logtps.FieldMessage].(string); ok && len(s) > 0 {
+		return s
+	}
+
+	return r.M
+}
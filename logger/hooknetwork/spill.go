@@ -0,0 +1,180 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package hooknetwork
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// spill is a file buffer used to store the records that cannot be kept in memory
+// or sent to the remote endpoint. Records are stored as JSON lines and read back
+// in the same order. The file is kept between two runs to not lose records.
+type spill struct {
+	m sync.Mutex
+	f *os.File
+	x int64 // max size of the file (0 for no limit)
+	r int64 // read offset
+	s int64 // size of the file
+}
+
+func newSpill(path string, max int64) (*spill, error) {
+	f, e := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+
+	if e != nil {
+		return nil, e
+	}
+
+	i, e := f.Stat()
+
+	if e != nil {
+		_ = f.Close()
+		return nil, e
+	}
+
+	return &spill{
+		f: f,
+		x: max,
+		s: i.Size(),
+	}, nil
+}
+
+func (o *spill) push(r *rec) error {
+	p, e := json.Marshal(r)
+
+	if e != nil {
+		return e
+	}
+
+	p = append(p, '\n')
+
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if o.f == nil {
+		return errStreamClosed
+	} else if o.x > 0 && o.s+int64(len(p)) > o.x {
+		return errSpillFull
+	}
+
+	n, e := o.f.WriteAt(p, o.s)
+	o.s += int64(n)
+
+	return e
+}
+
+// pop read at most n records from the spill file. The file is truncated when all records have been read.
+func (o *spill) pop(n int) []*rec {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if o.f == nil || o.r >= o.s {
+		return nil
+	}
+
+	var (
+		res = make([]*rec, 0, n)
+		buf = bufio.NewReader(io.NewSectionReader(o.f, o.r, o.s-o.r))
+	)
+
+	for len(res) < n {
+		p, e := buf.ReadBytes('\n')
+		o.r += int64(len(p))
+
+		if len(p) > 0 {
+			var r = &rec{}
+
+			if json.Unmarshal(p, r) == nil {
+				res = append(res, r)
+			}
+		}
+
+		if e != nil {
+			// incomplete or unreadable data are skipped
+			o.r = o.s
+			break
+		}
+	}
+
+	if o.r >= o.s {
+		if o.f.Truncate(0) == nil {
+			o.r, o.s = 0, 0
+		}
+	}
+
+	return res
+}
+
+func (o *spill) len() int64 {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	return o.s - o.r
+}
+
+// close save the records not yet read and close the file.
+func (o *spill) close() error {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if o.f == nil {
+		return nil
+	}
+
+	defer func() {
+		o.f = nil
+	}()
+
+	if o.r > 0 {
+		if e := o.compact(); e != nil {
+			_ = o.f.Close()
+			return e
+		}
+	}
+
+	return o.f.Close()
+}
+
+// compact move the records not yet read at the beginning of the file.
+func (o *spill) compact() error {
+	var buf = make([]byte, o.s-o.r)
+
+	if _, e := o.f.ReadAt(buf, o.r); e != nil && e != io.EOF {
+		return e
+	} else if _, e = o.f.WriteAt(buf, 0); e != nil {
+		return e
+	} else if e = o.f.Truncate(int64(len(buf))); e != nil {
+		return e
+	}
+
+	o.r, o.s = 0, int64(len(buf))
+	return nil
+}
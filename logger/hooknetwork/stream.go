@@ -0,0 +1,210 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package hooknetwork
+
+import (
+	"bytes"
+	"crypto/rand"
+	"net"
+	"sync"
+	"time"
+
+	libptc "github.com/nabbar/golib/network/protocol"
+)
+
+const (
+	// gelfChunkSize is the maximum size of a GELF UDP datagram.
+	gelfChunkSize = 1420
+	// gelfChunkMax is the maximum number of chunks of a GELF UDP message.
+	gelfChunkMax = 128
+	// gelfChunkHead is the size of the header of a GELF UDP chunk.
+	gelfChunkHead = 12
+)
+
+type sender interface {
+	// send ship the batch of records to the remote endpoint.
+	send(b []*rec) error
+	// close release the resources of the sender.
+	close() error
+}
+
+// encoder convert a record into a message of the stream protocol.
+type encoder interface {
+	// encode return the message of the record without framing.
+	encode(r *rec) ([]byte, error)
+	// delimiter return the byte appended to each message on stream connection.
+	delimiter() byte
+	// chunk split a message in datagrams for packet connection.
+	chunk(p []byte) ([][]byte, error)
+}
+
+type stream struct {
+	m sync.Mutex
+	n libptc.NetworkProtocol
+	a string
+	t time.Duration
+	e encoder
+	c net.Conn
+}
+
+func newStream(ntw libptc.NetworkProtocol, adr string, tmo time.Duration, enc encoder) sender {
+	return &stream{
+		n: ntw,
+		a: adr,
+		t: tmo,
+		e: enc,
+	}
+}
+
+func (o *stream) isPacket() bool {
+	switch o.n {
+	case libptc.NetworkUDP, libptc.NetworkUDP4, libptc.NetworkUDP6:
+		return true
+	default:
+		return false
+	}
+}
+
+func (o *stream) conn() (net.Conn, error) {
+	if o.c != nil {
+		return o.c, nil
+	}
+
+	c, e := net.DialTimeout(o.n.Code(), o.a, o.t)
+
+	if e != nil {
+		return nil, e
+	}
+
+	o.c = c
+	return c, nil
+}
+
+func (o *stream) send(b []*rec) error {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	var (
+		buf = bytes.NewBuffer(make([]byte, 0, 4096))
+		pkt = make([][]byte, 0, len(b))
+	)
+
+	for _, r := range b {
+		p, e := o.e.encode(r)
+
+		if e != nil {
+			return errPermanent{e}
+		}
+
+		if !o.isPacket() {
+			buf.Write(p)
+			buf.WriteByte(o.e.delimiter())
+		} else if l, e := o.e.chunk(p); e != nil {
+			return errPermanent{e}
+		} else {
+			pkt = append(pkt, l...)
+		}
+	}
+
+	c, e := o.conn()
+
+	if e != nil {
+		return e
+	}
+
+	if !o.isPacket() {
+		pkt = append(pkt, buf.Bytes())
+	}
+
+	for _, p := range pkt {
+		_ = c.SetWriteDeadline(time.Now().Add(o.t))
+
+		if _, e = c.Write(p); e != nil {
+			_ = c.Close()
+			o.c = nil
+			return e
+		}
+	}
+
+	return nil
+}
+
+func (o *stream) close() error {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if o.c == nil {
+		return nil
+	}
+
+	e := o.c.Close()
+	o.c = nil
+
+	return e
+}
+
+// gelfChunk split a GELF message into chunks if the message is bigger than the max datagram size.
+func gelfChunk(p []byte) ([][]byte, error) {
+	if len(p) <= gelfChunkSize {
+		return [][]byte{p}, nil
+	}
+
+	var (
+		siz = gelfChunkSize - gelfChunkHead
+		num = (len(p) + siz - 1) / siz
+		mid = make([]byte, 8)
+		res = make([][]byte, 0, num)
+	)
+
+	if num > gelfChunkMax {
+		return nil, errMessageTooLarge
+	} else if _, e := rand.Read(mid); e != nil {
+		return nil, e
+	}
+
+	for i := 0; i < num; i++ {
+		var (
+			s = i * siz
+			e = s + siz
+			c = make([]byte, 0, gelfChunkSize)
+		)
+
+		if e > len(p) {
+			e = len(p)
+		}
+
+		c = append(c, 0x1e, 0x0f)
+		c = append(c, mid...)
+		c = append(c, byte(i), byte(num))
+		c = append(c, p[s:e]...)
+
+		res = append(res, c)
+	}
+
+	return res, nil
+}
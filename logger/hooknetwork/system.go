@@ -0,0 +1,160 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package hooknetwork
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	libsrv "github.com/nabbar/golib/server"
+)
+
+func (o *hkn) Run(ctx context.Context) {
+	var (
+		t = time.NewTicker(o.o.batchInterval)
+		b = make([]*rec, 0, o.o.batchSize)
+	)
+
+	defer func() {
+		libsrv.RecoveryCaller("golib/logger/hooknetwork/system", recover())
+		t.Stop()
+		o.flush(b)
+		_ = o.w.close()
+
+		if o.p != nil {
+			_ = o.p.close()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-o.Done():
+			return
+
+		case r := <-o.q:
+			if b = append(b, r); len(b) >= o.o.batchSize {
+				o.send(ctx, b, true)
+				b = make([]*rec, 0, o.o.batchSize)
+			}
+
+		case <-t.C:
+			if len(b) > 0 {
+				o.send(ctx, b, true)
+				b = make([]*rec, 0, o.o.batchSize)
+			} else if len(o.q) < 1 {
+				o.unspill(ctx)
+			}
+		}
+	}
+}
+
+// unspill send the records stored into the spill file until the file is empty or a batch fail.
+func (o *hkn) unspill(ctx context.Context) {
+	if o.p == nil {
+		return
+	}
+
+	for o.p.len() > 0 {
+		if l := o.p.pop(o.o.batchSize); len(l) < 1 {
+			return
+		} else if !o.send(ctx, l, true) {
+			return
+		} else if len(o.q) > 0 {
+			return
+		}
+	}
+}
+
+// flush send the records in buffer without retry before closing the hook.
+func (o *hkn) flush(b []*rec) {
+	for {
+		select {
+		case r := <-o.q:
+			b = append(b, r)
+			continue
+		default:
+		}
+
+		break
+	}
+
+	for len(b) > 0 {
+		n := o.o.batchSize
+
+		if n > len(b) {
+			n = len(b)
+		}
+
+		o.send(context.Background(), b[:n], false)
+		b = b[n:]
+	}
+}
+
+// send ship the batch with retries and exponential backoff. If the batch still fail,
+// the records are stored into the spill file if defined or dropped.
+func (o *hkn) send(ctx context.Context, b []*rec, retry bool) bool {
+	var (
+		d = o.o.retryBackoff
+		e error
+	)
+
+	for i := 0; ; i++ {
+		if e = o.w.send(b); e == nil {
+			o.cntSent.Add(uint64(len(b)))
+			return true
+		} else if errors.As(e, &errPermanent{}) {
+			o.cntDropped.Add(uint64(len(b)))
+			return false
+		} else if !retry || i >= o.o.retryMax {
+			break
+		}
+
+		o.cntRetried.Add(1)
+
+		select {
+		case <-ctx.Done():
+			o.spill(b...)
+			return false
+		case <-o.Done():
+			o.spill(b...)
+			return false
+		case <-time.After(d):
+		}
+
+		if d *= 2; d > o.o.retryBackoffMax {
+			d = o.o.retryBackoffMax
+		}
+	}
+
+	o.spill(b...)
+	return false
+}
@@ -0,0 +1,65 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package hookslog
+
+import (
+	"log/slog"
+	"sync/atomic"
+
+	logtps "github.com/nabbar/golib/logger/types"
+	"github.com/sirupsen/logrus"
+)
+
+type HookSlog interface {
+	logtps.Hook
+
+	// SetHandler replaces the log/slog handler used by the hook.
+	// A nil handler disables the hook without unregistering it.
+	SetHandler(h slog.Handler)
+}
+
+// New return a hook sending all log entries to the given log/slog handler.
+// If no level is given, all levels are used.
+func New(h slog.Handler, lvls []logrus.Level) (HookSlog, error) {
+	if h == nil {
+		return nil, errMissingHandler
+	}
+
+	if len(lvls) < 1 {
+		lvls = logrus.AllLevels
+	}
+
+	o := &hkslg{
+		h: new(atomic.Value),
+		l: lvls,
+	}
+
+	o.SetHandler(h)
+
+	return o, nil
+}
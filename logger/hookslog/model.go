@@ -0,0 +1,170 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package hookslog
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	logtps "github.com/nabbar/golib/logger/types"
+	"github.com/sirupsen/logrus"
+)
+
+type hkslg struct {
+	h *atomic.Value // slgHdl
+	l []logrus.Level
+}
+
+// slgHdl wraps the handler as an atomic value must always store the same concrete type.
+type slgHdl struct {
+	slog.Handler
+}
+
+func (o *hkslg) SetHandler(h slog.Handler) {
+	o.h.Store(slgHdl{Handler: h})
+}
+
+func (o *hkslg) handler() slog.Handler {
+	if i, k := o.h.Load().(slgHdl); !k {
+		return nil
+	} else {
+		return i.Handler
+	}
+}
+
+func (o *hkslg) Run(ctx context.Context) {
+	return
+}
+
+func (o *hkslg) Levels() []logrus.Level {
+	return o.l
+}
+
+func (o *hkslg) RegisterHook(log *logrus.Logger) {
+	log.AddHook(o)
+}
+
+func (o *hkslg) Write(p []byte) (n int, err error) {
+	var (
+		ctx = context.Background()
+		msg = strings.TrimSuffix(string(p), "\n")
+		hdl = o.handler()
+	)
+
+	if hdl == nil || !hdl.Enabled(ctx, slog.LevelInfo) {
+		return len(p), nil
+	}
+
+	if err = hdl.Handle(ctx, slog.NewRecord(time.Now(), slog.LevelInfo, msg, 0)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func (o *hkslg) Close() error {
+	return nil
+}
+
+func (o *hkslg) Fire(entry *logrus.Entry) error {
+	var (
+		ctx = entry.Context
+		lvl = o.level(entry.Level)
+		msg = entry.Message
+		tim = entry.Time
+		key = make([]string, 0, len(entry.Data))
+		hdl = o.handler()
+	)
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if hdl == nil || !hdl.Enabled(ctx, lvl) {
+		return nil
+	}
+
+	for k := range entry.Data {
+		key = append(key, k)
+	}
+
+	sort.Strings(key)
+
+	var att = make([]slog.Attr, 0, len(key))
+
+	for _, k := range key {
+		v := entry.Data[k]
+
+		switch k {
+		case logtps.FieldLevel:
+			continue
+		case logtps.FieldMessage:
+			if s, ok := v.(string); ok {
+				msg = s
+			}
+			continue
+		case logtps.FieldTime:
+			if s, ok := v.(string); !ok {
+				continue
+			} else if t, e := time.Parse(time.RFC3339Nano, s); e == nil {
+				tim = t
+			}
+			continue
+		}
+
+		att = append(att, slog.Any(k, v))
+	}
+
+	rec := slog.NewRecord(tim, lvl, strings.TrimSuffix(msg, "\n"), 0)
+	rec.AddAttrs(att...)
+
+	return hdl.Handle(ctx, rec)
+}
+
+func (o *hkslg) level(lvl logrus.Level) slog.Level {
+	switch lvl {
+	case logrus.PanicLevel:
+		return slog.LevelError + 8
+	case logrus.FatalLevel:
+		return slog.LevelError + 4
+	case logrus.ErrorLevel:
+		return slog.LevelError
+	case logrus.WarnLevel:
+		return slog.LevelWarn
+	case logrus.InfoLevel:
+		return slog.LevelInfo
+	case logrus.DebugLevel:
+		return slog.LevelDebug
+	default:
+		return slog.LevelDebug - 4
+	}
+}
@@ -28,8 +28,10 @@
 package logger
 
 import (
+	"context"
 	"io"
 	"log"
+	"log/slog"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -81,6 +83,10 @@ type Logger interface {
 	//Clone allow to duplicate the logger with a copy of the logger
 	Clone() Logger
 
+	//WithContext return a copy of the logger with the fields stored into the given context (request ID, trace ID, ...)
+	// merged into the default fields. See logfld.ContextWith to store fields into a context.
+	WithContext(ctx context.Context) Logger
+
 	//SetSPF13Level allow to plus spf13 logger (jww) to this logger
 	SetSPF13Level(lvl loglvl.Level, log *jww.Notepad)
 
@@ -90,6 +96,19 @@ type Logger interface {
 	//SetStdLogger force the default golang log.logger instance linked with this main logger.
 	SetStdLogger(lvl loglvl.Level, logFlags int)
 
+	//GetSlogHandler return a log/slog handler linked with this main logger.
+	GetSlogHandler() slog.Handler
+
+	//GetSlog return a log/slog logger linked with this main logger.
+	GetSlog() *slog.Logger
+
+	//SetSlog force the default log/slog logger to be linked with this main logger.
+	SetSlog()
+
+	//SetSlogHandler allow to send all entries of this logger to the given log/slog handler.
+	// The handler is kept for the next calls of SetOptions. Giving a nil handler will remove it on next call of SetOptions.
+	SetSlogHandler(h slog.Handler)
+
 	//Debug add an entry with DebugLevel to the logger
 	Debug(message string, data interface{}, args ...interface{})
 
@@ -0,0 +1,93 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+
+	liblog "github.com/nabbar/golib/logger"
+	logfld "github.com/nabbar/golib/logger/fields"
+	logtps "github.com/nabbar/golib/logger/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Logger Context", func() {
+	Context("Store fields into a context", func() {
+		It("Must merge the fields without changing the parent context", func() {
+			p := logfld.ContextWithRequestID(context.Background(), "req-1")
+			c := logfld.ContextWithTrace(p, "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7")
+			c = logfld.ContextWith(c, "tenant", "acme")
+
+			Expect(logfld.FromContext(p)).To(Equal(map[string]interface{}{
+				logtps.FieldRequestID: "req-1",
+			}))
+			Expect(logfld.FromContext(c)).To(Equal(map[string]interface{}{
+				logtps.FieldRequestID: "req-1",
+				logtps.FieldTraceID:   "4bf92f3577b34da6a3ce929d0e0e4736",
+				logtps.FieldSpanID:    "00f067aa0ba902b7",
+				"tenant":              "acme",
+			}))
+			Expect(logfld.FromContext(context.Background())).To(BeEmpty())
+		})
+	})
+
+	Context("Create a logger with the fields of a context", func() {
+		It("Must add the context fields to each entry", func() {
+			var buf = bytes.NewBuffer(make([]byte, 0))
+
+			log := liblog.NewFromSlog(GetContext, slog.NewJSONHandler(buf, nil))
+			defer func() {
+				Expect(log.Close()).ToNot(HaveOccurred())
+			}()
+
+			log.SetFields(log.GetFields().Add("lib", "test"))
+
+			c := logfld.ContextWithRequestID(context.Background(), "req-42")
+			c = logfld.ContextWithTrace(c, "4bf92f3577b34da6a3ce929d0e0e4736", "")
+
+			log.WithContext(c).Info("with context", nil)
+			log.Info("without context", nil)
+
+			rec := slogRecords(buf)
+			Expect(rec).To(HaveLen(2))
+
+			Expect(rec[0]["msg"]).To(Equal("with context"))
+			Expect(rec[0]["lib"]).To(Equal("test"))
+			Expect(rec[0][logtps.FieldRequestID]).To(Equal("req-42"))
+			Expect(rec[0][logtps.FieldTraceID]).To(Equal("4bf92f3577b34da6a3ce929d0e0e4736"))
+			Expect(rec[0]).ToNot(HaveKey(logtps.FieldSpanID))
+
+			Expect(rec[1]["msg"]).To(Equal("without context"))
+			Expect(rec[1]["lib"]).To(Equal("test"))
+			Expect(rec[1]).ToNot(HaveKey(logtps.FieldRequestID))
+		})
+	})
+})
@@ -0,0 +1,155 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package logger_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	logcfg "github.com/nabbar/golib/logger/config"
+	logfrm "github.com/nabbar/golib/logger/formatter"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+)
+
+func formatEntry() *logrus.Entry {
+	return &logrus.Entry{
+		Logger: logrus.New(),
+		Level:  logrus.WarnLevel,
+		Data: logrus.Fields{
+			"time":    "2024-05-06T07:08:09.123456789Z",
+			"level":   "warning",
+			"message": "hello world",
+			"error":   errors.New("boom"),
+			"caller":  "main.run",
+			"file":    "main.go",
+			"line":    42,
+			"key":     "some value",
+		},
+	}
+}
+
+var _ = Describe("Logger Formatter", func() {
+	Context("Parse the format name", func() {
+		It("Must return the matching format or text", func() {
+			Expect(logfrm.Parse("JSON")).To(Equal(logfrm.JSON))
+			Expect(logfrm.Parse("logfmt")).To(Equal(logfrm.LogFmt))
+			Expect(logfrm.Parse("ecs")).To(Equal(logfrm.ECS))
+			Expect(logfrm.Parse("")).To(Equal(logfrm.Text))
+			Expect(logfrm.Parse("unknown")).To(Equal(logfrm.Text))
+		})
+	})
+
+	Context("Format an entry as JSON", func() {
+		It("Must write one JSON object with mapped fields and layout", func() {
+			f := logfrm.New(logcfg.OptionsFormat{
+				Type:            "json",
+				TimestampLayout: "2006-01-02 15:04:05",
+				FieldMap:        map[string]string{"message": "msg"},
+			}, nil)
+
+			p, e := f.Format(formatEntry())
+			Expect(e).ToNot(HaveOccurred())
+			Expect(p).To(HaveSuffix("\n"))
+
+			var r = make(map[string]interface{})
+			Expect(json.Unmarshal(p, &r)).ToNot(HaveOccurred())
+			Expect(r["time"]).To(Equal("2024-05-06 07:08:09"))
+			Expect(r["msg"]).To(Equal("hello world"))
+			Expect(r["error"]).To(Equal("boom"))
+			Expect(r["key"]).To(Equal("some value"))
+			Expect(r).ToNot(HaveKey("message"))
+		})
+	})
+
+	Context("Format an entry as logfmt", func() {
+		It("Must write ordered key=value pairs", func() {
+			f := logfrm.New(logcfg.OptionsFormat{Type: "logfmt"}, nil)
+
+			p, e := f.Format(formatEntry())
+			Expect(e).ToNot(HaveOccurred())
+			Expect(string(p)).To(Equal(strings.Join([]string{
+				"time=2024-05-06T07:08:09.123456789Z",
+				"level=warning",
+				`message="hello world"`,
+				"caller=main.run",
+				"error=boom",
+				"file=main.go",
+				"key=\"some value\"",
+				"line=42",
+			}, " ") + "\n"))
+		})
+	})
+
+	Context("Format an entry as ECS", func() {
+		It("Must write the Elastic Common Schema fields", func() {
+			f := logfrm.New(logcfg.OptionsFormat{Type: "ecs"}, nil)
+
+			p, e := f.Format(formatEntry())
+			Expect(e).ToNot(HaveOccurred())
+
+			var r = make(map[string]interface{})
+			Expect(json.Unmarshal(p, &r)).ToNot(HaveOccurred())
+			Expect(r["@timestamp"]).To(Equal("2024-05-06T07:08:09.123456789Z"))
+			Expect(r["log.level"]).To(Equal("warning"))
+			Expect(r["message"]).To(Equal("hello world"))
+			Expect(r["error.message"]).To(Equal("boom"))
+			Expect(r["log.origin.function"]).To(Equal("main.run"))
+			Expect(r["log.origin.file.name"]).To(Equal("main.go"))
+			Expect(r["log.origin.file.line"]).To(BeNumerically("==", 42))
+			Expect(r["ecs.version"]).To(Equal(logfrm.ECSVersion))
+			Expect(r["key"]).To(Equal("some value"))
+		})
+		It("Must apply the field map before the ECS conversion", func() {
+			f := logfrm.New(logcfg.OptionsFormat{
+				Type:     "ecs",
+				FieldMap: map[string]string{"caller": "function", "key": "labels.key"},
+			}, nil)
+
+			p, e := f.Format(formatEntry())
+			Expect(e).ToNot(HaveOccurred())
+
+			var r = make(map[string]interface{})
+			Expect(json.Unmarshal(p, &r)).ToNot(HaveOccurred())
+			Expect(r["function"]).To(Equal("main.run"))
+			Expect(r).ToNot(HaveKey("log.origin.function"))
+			Expect(r["labels.key"]).To(Equal("some value"))
+			Expect(r).ToNot(HaveKey("key"))
+			Expect(r["message"]).To(Equal("hello world"))
+		})
+	})
+
+	Context("Format an entry as text", func() {
+		It("Must keep the given text formatter without options", func() {
+			t := &logrus.TextFormatter{}
+			Expect(logfrm.New(logcfg.OptionsFormat{}, t)).To(BeIdenticalTo(t))
+		})
+	})
+})
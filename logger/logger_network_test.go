@@ -0,0 +1,310 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package logger_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	libdur "github.com/nabbar/golib/duration"
+	liblog "github.com/nabbar/golib/logger"
+	logcfg "github.com/nabbar/golib/logger/config"
+	lognet "github.com/nabbar/golib/logger/hooknetwork"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+)
+
+// stubLoki is a local loki push endpoint storing the received requests.
+type stubLoki struct {
+	m sync.Mutex
+	s atomic.Int32 // status code returned
+	r []map[string]interface{}
+}
+
+func (o *stubLoki) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var b = make(map[string]interface{})
+
+	if r.URL.Path != "/loki/api/v1/push" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if s := int(o.s.Load()); s != http.StatusNoContent {
+		w.WriteHeader(s)
+		return
+	} else if e := json.NewDecoder(r.Body).Decode(&b); e != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	o.m.Lock()
+	o.r = append(o.r, b)
+	o.m.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// values return the log lines received by the stub.
+func (o *stubLoki) values() []string {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	var res = make([]string, 0)
+
+	for _, r := range o.r {
+		for _, s := range r["streams"].([]interface{}) {
+			for _, v := range s.(map[string]interface{})["values"].([]interface{}) {
+				res = append(res, v.([]interface{})[1].(string))
+			}
+		}
+	}
+
+	return res
+}
+
+func networkEntry(h lognet.HookNetwork, msg string) {
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	h.RegisterHook(l)
+
+	l.WithFields(logrus.Fields{
+		"time":    time.Now().Format(time.RFC3339Nano),
+		"level":   "info",
+		"message": msg,
+		"key":     "value",
+	}).Info()
+}
+
+var _ = Describe("Logger HookNetwork", func() {
+	Context("Ship entries as newline-delimited JSON over TCP", func() {
+		It("Must receive each entry as a JSON line", func() {
+			lst, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).ToNot(HaveOccurred())
+			defer func() {
+				_ = lst.Close()
+			}()
+
+			var rcv = make(chan map[string]interface{}, 10)
+
+			go func() {
+				c, e := lst.Accept()
+				if e != nil {
+					return
+				}
+
+				s := bufio.NewScanner(c)
+				for s.Scan() {
+					var m = make(map[string]interface{})
+					if json.Unmarshal(s.Bytes(), &m) == nil {
+						rcv <- m
+					}
+				}
+			}()
+
+			log := liblog.New(GetContext)
+			defer func() {
+				Expect(log.Close()).ToNot(HaveOccurred())
+			}()
+
+			Expect(log.SetOptions(&logcfg.Options{
+				LogNetwork: logcfg.OptionsNetworks{
+					{
+						Protocol:      "json",
+						Network:       "tcp",
+						Host:          lst.Addr().String(),
+						Labels:        map[string]string{"app": "test"},
+						BatchInterval: libdur.ParseDuration(50 * time.Millisecond),
+					},
+				},
+			})).ToNot(HaveOccurred())
+
+			log.Info("hello network", nil)
+
+			var m map[string]interface{}
+			Eventually(rcv, 5*time.Second).Should(Receive(&m))
+			Expect(m["message"]).To(Equal("hello network"))
+			Expect(m["level"]).To(Equal("Info"))
+			Expect(m["app"]).To(Equal("test"))
+		})
+	})
+
+	Context("Ship entries as GELF over UDP", func() {
+		It("Must receive GELF messages and chunk the large ones", func() {
+			pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+			Expect(err).ToNot(HaveOccurred())
+			defer func() {
+				_ = pc.Close()
+			}()
+
+			hook, err := lognet.New(logcfg.OptionsNetwork{
+				Protocol:      "gelf",
+				Network:       "udp",
+				Host:          pc.LocalAddr().String(),
+				BatchInterval: libdur.ParseDuration(50 * time.Millisecond),
+			}, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			go hook.Run(ctx)
+			defer func() {
+				Expect(hook.Close()).ToNot(HaveOccurred())
+			}()
+
+			networkEntry(hook, "short message")
+
+			var buf = make([]byte, 65536)
+
+			_ = pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+			n, _, err := pc.ReadFrom(buf)
+			Expect(err).ToNot(HaveOccurred())
+
+			var m = make(map[string]interface{})
+			Expect(json.Unmarshal(buf[:n], &m)).ToNot(HaveOccurred())
+			Expect(m["version"]).To(Equal("1.1"))
+			Expect(m["short_message"]).To(Equal("short message"))
+			Expect(m["level"]).To(BeNumerically("==", 6))
+			Expect(m["_key"]).To(Equal("value"))
+
+			long := strings.Repeat("x", 5000)
+			networkEntry(hook, long)
+
+			var (
+				chk = make(map[byte][]byte)
+				cnt byte
+			)
+
+			for len(chk) < 1 || len(chk) < int(cnt) {
+				_ = pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+				n, _, err = pc.ReadFrom(buf)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(buf[:2]).To(Equal([]byte{0x1e, 0x0f}))
+
+				cnt = buf[11]
+				chk[buf[10]] = append([]byte{}, buf[12:n]...)
+			}
+
+			var msg = bytes.NewBuffer(nil)
+			for i := byte(0); i < cnt; i++ {
+				msg.Write(chk[i])
+			}
+
+			m = make(map[string]interface{})
+			Expect(json.Unmarshal(msg.Bytes(), &m)).ToNot(HaveOccurred())
+			Expect(m["short_message"]).To(Equal(long))
+		})
+	})
+
+	Context("Ship entries to a loki push endpoint", func() {
+		It("Must spill the entries while the endpoint fail and send them later", func() {
+			var (
+				stb = &stubLoki{}
+				srv = httptest.NewServer(stb)
+				fsp = filepath.Join(GinkgoT().TempDir(), "spill.log")
+			)
+
+			defer srv.Close()
+			stb.s.Store(http.StatusServiceUnavailable)
+
+			hook, err := lognet.New(logcfg.OptionsNetwork{
+				Protocol:      "loki",
+				Host:          srv.URL,
+				Labels:        map[string]string{"app": "test"},
+				SpillPath:     fsp,
+				BatchInterval: libdur.ParseDuration(50 * time.Millisecond),
+				RetryMax:      -1,
+				Format:        logcfg.OptionsFormat{Type: "logfmt"},
+			}, &logrus.TextFormatter{})
+			Expect(err).ToNot(HaveOccurred())
+
+			go hook.Run(ctx)
+			defer func() {
+				Expect(hook.Close()).ToNot(HaveOccurred())
+			}()
+
+			networkEntry(hook, "first")
+			networkEntry(hook, "second")
+
+			Eventually(func() uint64 {
+				return hook.Stats().Spilled
+			}, 5*time.Second, 20*time.Millisecond).Should(BeNumerically(">=", 2))
+
+			stb.s.Store(http.StatusNoContent)
+
+			Eventually(stb.values, 5*time.Second, 20*time.Millisecond).Should(HaveLen(2))
+			Expect(stb.values()[0]).To(ContainSubstring("first"))
+			Expect(stb.values()[1]).To(ContainSubstring("second"))
+			Eventually(func() uint64 {
+				return hook.Stats().Sent
+			}, 5*time.Second, 20*time.Millisecond).Should(BeNumerically("==", 2))
+
+			stb.m.Lock()
+			s := stb.r[0]["streams"].([]interface{})[0].(map[string]interface{})["stream"].(map[string]interface{})
+			stb.m.Unlock()
+
+			Expect(s["app"]).To(Equal("test"))
+			Expect(s["level"]).To(Equal("info"))
+		})
+
+		It("Must drop the entries refused by the endpoint without spill file", func() {
+			var (
+				stb = &stubLoki{}
+				srv = httptest.NewServer(stb)
+			)
+
+			defer srv.Close()
+			stb.s.Store(http.StatusBadRequest)
+
+			hook, err := lognet.New(logcfg.OptionsNetwork{
+				Protocol:      "loki",
+				Host:          srv.URL,
+				BatchInterval: libdur.ParseDuration(50 * time.Millisecond),
+			}, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			go hook.Run(ctx)
+			defer func() {
+				Expect(hook.Close()).ToNot(HaveOccurred())
+			}()
+
+			networkEntry(hook, "refused")
+
+			Eventually(func() uint64 {
+				return hook.Stats().Dropped
+			}, 5*time.Second, 20*time.Millisecond).Should(BeNumerically("==", 1))
+			Expect(hook.Stats().Retried).To(BeZero())
+		})
+	})
+})
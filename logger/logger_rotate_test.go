@@ -0,0 +1,117 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package logger_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	logcfg "github.com/nabbar/golib/logger/config"
+	logfil "github.com/nabbar/golib/logger/hookfile"
+	libsiz "github.com/nabbar/golib/size"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func rotatedFiles(dir, base string) []string {
+	var res = make([]string, 0)
+
+	l, err := os.ReadDir(dir)
+	Expect(err).ToNot(HaveOccurred())
+
+	for _, f := range l {
+		if f.Name() != base && strings.HasPrefix(f.Name(), base+".") {
+			res = append(res, filepath.Join(dir, f.Name()))
+		}
+	}
+
+	return res
+}
+
+var _ = Describe("Logger HookFile Rotation", func() {
+	Context("Create a file hook with rotation by size", func() {
+		It("Must rotate, compress and purge the log file", func() {
+			var (
+				dir = GinkgoT().TempDir()
+				fsp = filepath.Join(dir, "rotate.log")
+				msg = []byte(strings.Repeat("a", 199) + "\n")
+			)
+
+			hook, err := logfil.New(logcfg.OptionsFile{
+				Filepath: fsp,
+				Create:   true,
+				Rotate: logcfg.OptionsRotate{
+					MaxSize:    libsiz.Size(300),
+					MaxBackups: 2,
+					Compress:   true,
+				},
+			}, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			go hook.Run(ctx)
+			defer func() {
+				Expect(hook.Close()).ToNot(HaveOccurred())
+			}()
+
+			for i := 0; i < 4; i++ {
+				Eventually(func() error {
+					_, e := hook.Write(msg)
+					return e
+				}).ShouldNot(HaveOccurred())
+
+				// wait the buffer to be flushed into the file
+				time.Sleep(1500 * time.Millisecond)
+			}
+
+			Eventually(func() []string {
+				return rotatedFiles(dir, "rotate.log")
+			}, 3*time.Second, 100*time.Millisecond).Should(HaveLen(2))
+
+			for _, f := range rotatedFiles(dir, "rotate.log") {
+				Expect(f).To(HaveSuffix(".gz"))
+
+				h, e := os.Open(f)
+				Expect(e).ToNot(HaveOccurred())
+
+				r, e := gzip.NewReader(h)
+				Expect(e).ToNot(HaveOccurred())
+
+				b, e := io.ReadAll(r)
+				Expect(e).ToNot(HaveOccurred())
+				Expect(bytes.Equal(b, msg)).To(BeTrue())
+
+				_ = h.Close()
+			}
+		})
+	})
+})
@@ -0,0 +1,173 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package logger_test
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	libdur "github.com/nabbar/golib/duration"
+	logcfg "github.com/nabbar/golib/logger/config"
+	logsmp "github.com/nabbar/golib/logger/sampler"
+	logtps "github.com/nabbar/golib/logger/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+)
+
+// captureHook is a hook storing the message of each entry fired.
+type captureHook struct {
+	m sync.Mutex
+	l []string
+}
+
+func (o *captureHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (o *captureHook) Fire(entry *logrus.Entry) error {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	o.l = append(o.l, entry.Data["message"].(string))
+	return nil
+}
+
+func (o *captureHook) messages() []string {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	return append(make([]string, 0, len(o.l)), o.l...)
+}
+
+func (o *captureHook) Write(p []byte) (n int, err error) {
+	return len(p), nil
+}
+
+func (o *captureHook) Close() error {
+	return nil
+}
+
+func (o *captureHook) RegisterHook(log *logrus.Logger) {
+	log.AddHook(o)
+}
+
+func (o *captureHook) Run(ctx context.Context) {
+	<-ctx.Done()
+}
+
+func samplerLogger(opt logcfg.OptionsSampling) (*captureHook, logtps.Hook, *logrus.Logger) {
+	var (
+		hcp = &captureHook{}
+		hks = logsmp.New(hcp, opt)
+		log = logrus.New()
+	)
+
+	log.SetOutput(io.Discard)
+	log.SetLevel(logrus.DebugLevel)
+	hks.RegisterHook(log)
+
+	return hcp, hks, log
+}
+
+var _ = Describe("Logger Sampler", func() {
+	Context("Create a sampler without options", func() {
+		It("Must return the given hook", func() {
+			hcp := &captureHook{}
+			Expect(logsmp.New(hcp, logcfg.OptionsSampling{})).To(BeIdenticalTo(hcp))
+		})
+	})
+
+	Context("Sample the repetitive entries", func() {
+		It("Must allow the first entries then every Mth and send a summary", func() {
+			hcp, hook, log := samplerLogger(logcfg.OptionsSampling{
+				Interval:   libdur.ParseDuration(200 * time.Millisecond),
+				First:      2,
+				Thereafter: 3,
+			})
+
+			for i := 0; i < 10; i++ {
+				log.WithField("message", "upstream failure").Error()
+			}
+			log.WithField("message", "other message").Error()
+
+			// entries 1, 2, 5 and 8 of the same message are allowed
+			Expect(hcp.messages()).To(HaveLen(5))
+
+			x, cnl := context.WithCancel(ctx)
+			defer cnl()
+
+			go hook.Run(x)
+			defer func() {
+				Expect(hook.Close()).ToNot(HaveOccurred())
+			}()
+
+			Eventually(hcp.messages, 2*time.Second, 20*time.Millisecond).Should(ContainElement("suppressed 6 similar messages: upstream failure"))
+			Expect(hcp.messages()).To(HaveLen(6))
+
+			// counters are reset after each interval
+			log.WithField("message", "upstream failure").Error()
+			Expect(hcp.messages()).To(HaveLen(7))
+		})
+	})
+
+	Context("Limit the rate of entries by level", func() {
+		It("Must allow only the burst of the limited level", func() {
+			hcp, _, log := samplerLogger(logcfg.OptionsSampling{
+				RateLimit: []logcfg.OptionsRateLimit{
+					{
+						Level: "Debug",
+						Rate:  0.5,
+						Burst: 2,
+					},
+				},
+			})
+
+			for i := 0; i < 5; i++ {
+				log.WithField("message", "debug message").Debug()
+				log.WithField("message", "info message").Info()
+			}
+
+			var dbg, inf int
+
+			for _, m := range hcp.messages() {
+				switch m {
+				case "debug message":
+					dbg++
+				case "info message":
+					inf++
+				}
+			}
+
+			Expect(dbg).To(Equal(2))
+			Expect(inf).To(Equal(5))
+		})
+	})
+})
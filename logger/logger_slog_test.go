@@ -0,0 +1,170 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	liblog "github.com/nabbar/golib/logger"
+	logfld "github.com/nabbar/golib/logger/fields"
+	loglvl "github.com/nabbar/golib/logger/level"
+	logtps "github.com/nabbar/golib/logger/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func slogRecords(buf *bytes.Buffer) []map[string]interface{} {
+	var res = make([]map[string]interface{}, 0)
+
+	for _, l := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		if len(l) < 1 {
+			continue
+		}
+
+		var r = make(map[string]interface{})
+		Expect(json.Unmarshal(l, &r)).ToNot(HaveOccurred())
+		res = append(res, r)
+	}
+
+	return res
+}
+
+var _ = Describe("Logger log/slog", func() {
+	Context("Create a logger emitting through a slog handler", func() {
+		It("Must send entries to the slog handler", func() {
+			var buf = bytes.NewBuffer(make([]byte, 0))
+
+			log := liblog.NewFromSlog(GetContext, slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			defer func() {
+				Expect(log.Close()).ToNot(HaveOccurred())
+			}()
+
+			log.Info("hello %s", nil, "world")
+			log.Debug("filtered by the logger level", nil)
+			log.Entry(loglvl.WarnLevel, "warning").FieldAdd("key", "value").Log()
+
+			rec := slogRecords(buf)
+			Expect(rec).To(HaveLen(2))
+
+			Expect(rec[0]["msg"]).To(Equal("hello world"))
+			Expect(rec[0]["level"]).To(Equal("INFO"))
+
+			Expect(rec[1]["msg"]).To(Equal("warning"))
+			Expect(rec[1]["level"]).To(Equal("WARN"))
+			Expect(rec[1]["key"]).To(Equal("value"))
+		})
+	})
+
+	Context("Create a slog logger backed by the logger", func() {
+		It("Must convert attributes and groups to fields", func() {
+			var buf = bytes.NewBuffer(make([]byte, 0))
+
+			log := liblog.NewFromSlog(GetContext, slog.NewJSONHandler(buf, nil))
+			defer func() {
+				Expect(log.Close()).ToNot(HaveOccurred())
+			}()
+
+			slg := log.GetSlog()
+			Expect(slg.Enabled(GetContext(), slog.LevelDebug)).To(BeFalse())
+			Expect(slg.Enabled(GetContext(), slog.LevelWarn)).To(BeTrue())
+
+			slg.With("a", 1).WithGroup("g").Error("failure", "b", 2, slog.Group("s", "c", true))
+			slg.Info("with error", "err", errors.New("boom"))
+			slg.Debug("filtered by the logger level")
+
+			rec := slogRecords(buf)
+			Expect(rec).To(HaveLen(2))
+
+			Expect(rec[0]["msg"]).To(Equal("failure"))
+			Expect(rec[0]["level"]).To(Equal("ERROR"))
+			Expect(rec[0]["a"]).To(BeEquivalentTo(1))
+			Expect(rec[0]["g"]).To(Equal(map[string]interface{}{
+				"b": float64(2),
+				"s": map[string]interface{}{"c": true},
+			}))
+
+			Expect(rec[1]["msg"]).To(Equal("with error"))
+			Expect(rec[1]["error"]).To(Equal("boom"))
+		})
+	})
+
+	Context("Replace the slog handler of a configured logger", func() {
+		It("Must send entries only to the last handler", func() {
+			var (
+				old = bytes.NewBuffer(make([]byte, 0))
+				cur = bytes.NewBuffer(make([]byte, 0))
+			)
+
+			log := liblog.NewFromSlog(GetContext, slog.NewJSONHandler(old, nil))
+			defer func() {
+				Expect(log.Close()).ToNot(HaveOccurred())
+			}()
+
+			log.Info("first", nil)
+			log.SetSlogHandler(slog.NewJSONHandler(cur, nil))
+			log.Info("second", nil)
+			log.SetSlogHandler(nil)
+			log.Info("third", nil)
+
+			rec := slogRecords(old)
+			Expect(rec).To(HaveLen(1))
+			Expect(rec[0]["msg"]).To(Equal("first"))
+
+			rec = slogRecords(cur)
+			Expect(rec).To(HaveLen(1))
+			Expect(rec[0]["msg"]).To(Equal("second"))
+		})
+	})
+
+	Context("Log with a context through the slog handler of the logger", func() {
+		It("Must add the fields stored into the context", func() {
+			var buf = bytes.NewBuffer(make([]byte, 0))
+
+			log := liblog.NewFromSlog(GetContext, slog.NewJSONHandler(buf, nil))
+			defer func() {
+				Expect(log.Close()).ToNot(HaveOccurred())
+			}()
+
+			ctx := logfld.ContextWithRequestID(context.Background(), "req-7")
+			ctx = logfld.ContextWithTrace(ctx, "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7")
+
+			log.GetSlog().InfoContext(ctx, "with context", "key", "value")
+
+			rec := slogRecords(buf)
+			Expect(rec).To(HaveLen(1))
+			Expect(rec[0]["key"]).To(Equal("value"))
+			Expect(rec[0][logtps.FieldRequestID]).To(Equal("req-7"))
+			Expect(rec[0][logtps.FieldTraceID]).To(Equal("4bf92f3577b34da6a3ce929d0e0e4736"))
+			Expect(rec[0][logtps.FieldSpanID]).To(Equal("00f067aa0ba902b7"))
+		})
+	})
+})
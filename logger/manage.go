@@ -37,11 +37,15 @@ import (
 	iotclo "github.com/nabbar/golib/ioutils/mapCloser"
 	logcfg "github.com/nabbar/golib/logger/config"
 	logfld "github.com/nabbar/golib/logger/fields"
+	logfrm "github.com/nabbar/golib/logger/formatter"
 	logfil "github.com/nabbar/golib/logger/hookfile"
+	lognet "github.com/nabbar/golib/logger/hooknetwork"
+	logslg "github.com/nabbar/golib/logger/hookslog"
 	logerr "github.com/nabbar/golib/logger/hookstderr"
 	logout "github.com/nabbar/golib/logger/hookstdout"
 	logsys "github.com/nabbar/golib/logger/hooksyslog"
 	loglvl "github.com/nabbar/golib/logger/level"
+	logsmp "github.com/nabbar/golib/logger/sampler"
 	logtps "github.com/nabbar/golib/logger/types"
 	"github.com/sirupsen/logrus"
 )
@@ -87,6 +91,26 @@ func (o *logger) Clone() Logger {
 	return l
 }
 
+func (o *logger) WithContext(ctx context.Context) Logger {
+	if o == nil {
+		return nil
+	}
+
+	l := o.Clone()
+
+	if f := logfld.FromContext(ctx); len(f) > 0 {
+		var fld = o.GetFields()
+
+		for k, v := range f {
+			fld.Add(k, v)
+		}
+
+		l.SetFields(fld)
+	}
+
+	return l
+}
+
 func (o *logger) RegisterFuncUpdateLogger(fct func(log Logger)) {
 	o.x.Store(keyFctUpdLog, fct)
 }
@@ -175,7 +199,7 @@ func (o *logger) SetOptions(opt *logcfg.Options) error {
 	obj.SetOutput(io.Discard) // Send all logs to nowhere by default
 
 	if opt.Stdout != nil && !opt.Stdout.DisableStandard {
-		f := o.defaultFormatter(opt.Stdout)
+		f := logfrm.New(opt.Stdout.Format, o.defaultFormatter(opt.Stdout))
 		l := []logrus.Level{
 			logrus.InfoLevel,
 			logrus.DebugLevel,
@@ -185,7 +209,7 @@ func (o *logger) SetOptions(opt *logcfg.Options) error {
 		if h, e := logout.New(opt.Stdout, l, f); e != nil {
 			return e
 		} else {
-			hkl = append(hkl, h)
+			hkl = append(hkl, logsmp.New(h, opt.Stdout.Sampling))
 		}
 
 		l = []logrus.Level{
@@ -198,30 +222,49 @@ func (o *logger) SetOptions(opt *logcfg.Options) error {
 		if h, e := logerr.New(opt.Stdout, l, f); e != nil {
 			return e
 		} else {
-			hkl = append(hkl, h)
+			hkl = append(hkl, logsmp.New(h, opt.Stdout.Sampling))
 		}
 	}
 
 	if len(opt.LogFile) > 0 {
 		for _, f := range opt.LogFile {
-			if h, e := logfil.New(f, o.defaultFormatterNoColor()); e != nil {
+			if h, e := logfil.New(f, logfrm.New(f.Format, o.defaultFormatterNoColor())); e != nil {
 				return e
 			} else {
-				hkl = append(hkl, h)
+				hkl = append(hkl, logsmp.New(h, f.Sampling))
 			}
 		}
 	}
 
 	if len(opt.LogSyslog) > 0 {
 		for _, s := range opt.LogSyslog {
-			if h, e := logsys.New(s, o.defaultFormatterNoColor()); e != nil {
+			if h, e := logsys.New(s, logfrm.New(s.Format, o.defaultFormatterNoColor())); e != nil {
 				return e
 			} else {
-				hkl = append(hkl, h)
+				hkl = append(hkl, logsmp.New(h, s.Sampling))
 			}
 		}
 	}
 
+	if len(opt.LogNetwork) > 0 {
+		for _, n := range opt.LogNetwork {
+			if h, e := lognet.New(n, logfrm.New(n.Format, o.defaultFormatterNoColor())); e != nil {
+				return e
+			} else {
+				hkl = append(hkl, logsmp.New(h, n.Sampling))
+			}
+		}
+	}
+
+	if h := o.getSlogHandler(); h == nil {
+		o.x.Delete(keySlogHook)
+	} else if k, e := logslg.New(h, nil); e != nil {
+		return e
+	} else {
+		hkl = append(hkl, k)
+		o.x.Store(keySlogHook, k)
+	}
+
 	var clo = o.newCloser()
 
 	for _, h := range hkl {
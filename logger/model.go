@@ -57,6 +57,8 @@ const (
 	keyFilter
 	keyFctUpdLog
 	keyFctUpdLvl
+	keySlogHandler
+	keySlogHook
 
 	_TraceFilterMod    = "/pkg/mod/"
 	_TraceFilterVendor = "/vendor/"
@@ -0,0 +1,118 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package sampler
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	logcfg "github.com/nabbar/golib/logger/config"
+	loglvl "github.com/nabbar/golib/logger/level"
+	logtps "github.com/nabbar/golib/logger/types"
+	"github.com/sirupsen/logrus"
+)
+
+const defaultInterval = time.Second
+
+// New return a hook wrapping the given hook to sample and rate limit the repetitive entries.
+// Entries are sampled by level and message : the first entries of each interval are allowed,
+// then only every Mth entry. The rate limiters are token buckets by level.
+// At each interval, a summary entry is sent for each level and message with suppressed entries.
+// If the options do not enable any sampling or limiter, the given hook is returned.
+func New(h logtps.Hook, opt logcfg.OptionsSampling) logtps.Hook {
+	if h == nil || !opt.IsEnabled() {
+		return h
+	}
+
+	s := &smp{
+		h: h,
+		i: opt.Interval.Time(),
+		f: uint64(opt.First),
+		m: uint64(opt.Thereafter),
+		l: make(map[logrus.Level]*bkt),
+		k: make(map[key]*cnt),
+		c: make(chan struct{}),
+	}
+
+	if s.i <= 0 {
+		s.i = defaultInterval
+	}
+
+	if opt.First < 0 {
+		s.f = 0
+	}
+
+	if opt.Thereafter < 0 {
+		s.m = 0
+	}
+
+	for _, l := range opt.RateLimit {
+		if l.Rate <= 0 {
+			continue
+		}
+
+		b := &bkt{
+			r: l.Rate,
+			b: float64(l.Burst),
+		}
+
+		if b.b < 1 {
+			b.b = math.Max(1, math.Ceil(l.Rate))
+		}
+
+		b.t = b.b
+		s.l[loglvl.Parse(l.Level).Logrus()] = b
+	}
+
+	return s
+}
+
+type key struct {
+	l logrus.Level
+	m string
+}
+
+type cnt struct {
+	n uint64         // number of entries in the interval
+	s uint64         // number of suppressed entries in the interval
+	g *logrus.Logger // logger of the last entry
+	v interface{}    // level field of the last entry
+}
+
+type smp struct {
+	x sync.Mutex
+	h logtps.Hook
+	i time.Duration         // interval
+	f uint64                // first
+	m uint64                // thereafter
+	l map[logrus.Level]*bkt // rate limiters
+	k map[key]*cnt          // counters of the current interval
+	c chan struct{}         // closed channel
+	o sync.Once
+}
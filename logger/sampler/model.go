@@ -0,0 +1,196 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package sampler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	logtps "github.com/nabbar/golib/logger/types"
+	"github.com/sirupsen/logrus"
+)
+
+// bkt is a token bucket : tokens are refilled at rate r by second up to the burst b.
+type bkt struct {
+	r float64   // rate by second
+	b float64   // burst
+	t float64   // available tokens
+	l time.Time // last refill
+}
+
+func (o *bkt) allow(now time.Time) bool {
+	if !o.l.IsZero() {
+		if o.t += now.Sub(o.l).Seconds() * o.r; o.t > o.b {
+			o.t = o.b
+		}
+	}
+
+	o.l = now
+
+	if o.t >= 1 {
+		o.t--
+		return true
+	}
+
+	return false
+}
+
+func (o *smp) Levels() []logrus.Level {
+	return o.h.Levels()
+}
+
+func (o *smp) RegisterHook(log *logrus.Logger) {
+	log.AddHook(o)
+}
+
+func (o *smp) Fire(entry *logrus.Entry) error {
+	if !o.allow(entry) {
+		return nil
+	}
+
+	return o.h.Fire(entry)
+}
+
+func (o *smp) Write(p []byte) (n int, err error) {
+	return o.h.Write(p)
+}
+
+func (o *smp) Close() error {
+	o.o.Do(func() {
+		close(o.c)
+	})
+
+	return o.h.Close()
+}
+
+func (o *smp) Run(ctx context.Context) {
+	go o.h.Run(ctx)
+
+	var t = time.NewTicker(o.i)
+
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-o.c:
+			return
+		case <-t.C:
+			o.summary()
+		}
+	}
+}
+
+// allow return true if the entry must be sent to the hook.
+func (o *smp) allow(ent *logrus.Entry) bool {
+	var k = key{
+		l: ent.Level,
+		m: message(ent),
+	}
+
+	o.x.Lock()
+	defer o.x.Unlock()
+
+	c, ok := o.k[k]
+
+	if !ok {
+		c = &cnt{}
+		o.k[k] = c
+	}
+
+	c.n++
+	c.g = ent.Logger
+
+	if v, ok := ent.Data[logtps.FieldLevel]; ok {
+		c.v = v
+	} else {
+		c.v = ent.Level.String()
+	}
+
+	if !o.sample(c.n) {
+		c.s++
+		return false
+	}
+
+	if b, ok := o.l[ent.Level]; ok && !b.allow(time.Now()) {
+		c.s++
+		return false
+	}
+
+	return true
+}
+
+// sample apply the first N then every Mth rule on the nth entry of the interval.
+func (o *smp) sample(n uint64) bool {
+	if o.f < 1 || n <= o.f {
+		return true
+	} else if o.m < 1 {
+		return false
+	}
+
+	return (n-o.f)%o.m == 0
+}
+
+// summary send one entry for each level and message with suppressed entries and reset the counters.
+func (o *smp) summary() {
+	o.x.Lock()
+	var l = o.k
+	o.k = make(map[key]*cnt)
+	o.x.Unlock()
+
+	for k, c := range l {
+		if c.s < 1 {
+			continue
+		}
+
+		var (
+			now = time.Now()
+			ent = logrus.NewEntry(c.g)
+		)
+
+		ent.Time = now
+		ent.Level = k.l
+		ent.Data = logrus.Fields{
+			logtps.FieldTime:    now.Format(time.RFC3339Nano),
+			logtps.FieldLevel:   c.v,
+			logtps.FieldMessage: fmt.Sprintf("suppressed %d similar messages: %s", c.s, k.m),
+		}
+
+		_ = o.h.Fire(ent)
+	}
+}
+
+func message(ent *logrus.Entry) string {
+	if s, ok := ent.Data[logtps.FieldMessage].(string); ok {
+		return s
+	}
+
+	return ent.Message
+}
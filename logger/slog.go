@@ -0,0 +1,255 @@
+/***********************************************************************************************************************
+ *
+ *   MIT License
+ *
+ *   Copyright (c) 2024 Nicolas JUHEL
+ *
+ *   Permission is hereby granted, free of charge, to any person obtaining a copy
+ *   of this software and associated documentation files (the "Software"), to deal
+ *   in the Software without restriction, including without limitation the rights
+ *   to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *   copies of the Software, and to permit persons to whom the Software is
+ *   furnished to do so, subject to the following conditions:
+ *
+ *   The above copyright notice and this permission notice shall be included in all
+ *   copies or substantial portions of the Software.
+ *
+ *   THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *   IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *   FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *   AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *   LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *   OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *   SOFTWARE.
+ *
+ *
+ **********************************************************************************************************************/
+
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	libctx "github.com/nabbar/golib/context"
+	logcfg "github.com/nabbar/golib/logger/config"
+	logfld "github.com/nabbar/golib/logger/fields"
+	logslg "github.com/nabbar/golib/logger/hookslog"
+	loglvl "github.com/nabbar/golib/logger/level"
+)
+
+// NewFromSlog return a new logger interface sending all entries to the given log/slog handler.
+// Other hooks (stdout, file, syslog, ...) could be added by calling the SetOptions function.
+func NewFromSlog(ctx libctx.FuncContext, h slog.Handler) Logger {
+	l := New(ctx)
+	l.SetSlogHandler(h)
+	_ = l.SetOptions(&logcfg.Options{})
+	return l
+}
+
+func (o *logger) GetSlogHandler() slog.Handler {
+	return &slgHandler{
+		l: o,
+		a: make([]slgAttr, 0),
+		g: make([]string, 0),
+	}
+}
+
+func (o *logger) GetSlog() *slog.Logger {
+	return slog.New(o.GetSlogHandler())
+}
+
+func (o *logger) SetSlog() {
+	slog.SetDefault(o.GetSlog())
+}
+
+func (o *logger) SetSlogHandler(h slog.Handler) {
+	if h == nil {
+		o.x.Delete(keySlogHandler)
+	} else {
+		o.x.Store(keySlogHandler, h)
+	}
+
+	// replace the handler of the hook already registered
+	if k := o.getSlogHook(); k != nil {
+		k.SetHandler(h)
+		return
+	} else if h == nil {
+		return
+	}
+
+	// apply immediately if the logger is already configured
+	if l := o.getLogrus(); l == nil {
+		return
+	} else if k, e := logslg.New(h, nil); e != nil {
+		return
+	} else if c := o.getCloser(); c != nil {
+		c.Add(k)
+		k.RegisterHook(l)
+		o.x.Store(keySlogHook, k)
+		go k.Run(o.x.GetContext())
+	}
+}
+
+func (o *logger) getSlogHook() logslg.HookSlog {
+	if i, l := o.x.Load(keySlogHook); !l {
+		return nil
+	} else if k, ok := i.(logslg.HookSlog); !ok {
+		return nil
+	} else {
+		return k
+	}
+}
+
+func (o *logger) getSlogHandler() slog.Handler {
+	if i, l := o.x.Load(keySlogHandler); !l {
+		return nil
+	} else if h, k := i.(slog.Handler); !k {
+		return nil
+	} else {
+		return h
+	}
+}
+
+type slgAttr struct {
+	g []string  // group path of the attribute
+	a slog.Attr // attribute
+}
+
+// slgHandler is a log/slog handler sending all records to the logger.
+// Attributes are converted to fields and groups to nested fields.
+type slgHandler struct {
+	l *logger
+	a []slgAttr
+	g []string
+}
+
+func (h *slgHandler) Enabled(ctx context.Context, lvl slog.Level) bool {
+	if h.l == nil {
+		return false
+	} else if cur := h.l.GetLevel(); cur == loglvl.NilLevel {
+		return false
+	} else {
+		return loglvl.ParseSlog(lvl) <= cur
+	}
+}
+
+func (h *slgHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.l == nil {
+		return nil
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var (
+		ent = h.l.newEntry(loglvl.ParseSlog(r.Level), r.Message, nil, nil, nil)
+		fld = logfld.New(func() context.Context {
+			return ctx
+		})
+		val = logfld.FromContext(ctx)
+	)
+
+	// fields stored into the context (request ID, trace ID, ...) are overridden by the attributes
+	for _, a := range h.a {
+		slgInsert(val, a.g, a.a)
+	}
+
+	r.Attrs(func(a slog.Attr) bool {
+		if e, k := a.Value.Any().(error); k && len(h.g) < 1 {
+			ent.ErrorAdd(true, e)
+		} else {
+			slgInsert(val, h.g, a)
+		}
+		return true
+	})
+
+	for k, v := range val {
+		fld.Add(k, v)
+	}
+
+	ent.FieldMerge(fld)
+
+	if r.PC != 0 {
+		f, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		ent.SetEntryContext(r.Time, h.l.getStack(), f.Function, f.File, uint64(f.Line), r.Message)
+	}
+
+	ent.Log()
+	return nil
+}
+
+func (h *slgHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) < 1 {
+		return h
+	}
+
+	var n = h.clone()
+
+	for _, a := range attrs {
+		n.a = append(n.a, slgAttr{
+			g: n.g,
+			a: a,
+		})
+	}
+
+	return n
+}
+
+func (h *slgHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	var n = h.clone()
+	n.g = append(n.g, name)
+
+	return n
+}
+
+func (h *slgHandler) clone() *slgHandler {
+	return &slgHandler{
+		l: h.l,
+		a: append(make([]slgAttr, 0, len(h.a)), h.a...),
+		g: append(make([]string, 0, len(h.g)), h.g...),
+	}
+}
+
+// slgInsert stores the given attribute into the map, following the group path as nested map.
+func slgInsert(m map[string]interface{}, g []string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+
+	if a.Equal(slog.Attr{}) {
+		return
+	} else if a.Value.Kind() == slog.KindGroup && len(a.Value.Group()) < 1 {
+		return
+	}
+
+	for _, k := range g {
+		s, ok := m[k].(map[string]interface{})
+
+		if !ok {
+			s = make(map[string]interface{})
+			m[k] = s
+		}
+
+		m = s
+	}
+
+	if a.Value.Kind() != slog.KindGroup {
+		m[a.Key] = a.Value.Any()
+		return
+	}
+
+	var p []string
+
+	if a.Key != "" {
+		p = []string{a.Key}
+	}
+
+	for _, i := range a.Value.Group() {
+		slgInsert(m, p, i)
+	}
+}
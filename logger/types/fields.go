@@ -37,4 +37,8 @@ const (
 	FieldMessage = "message"
 	FieldError   = "error"
 	FieldData    = "data"
+
+	FieldRequestID = "request_id"
+	FieldTraceID   = "trace_id"
+	FieldSpanID    = "span_id"
 )
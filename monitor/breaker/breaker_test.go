@@ -0,0 +1,126 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package breaker_test
+
+import (
+	"time"
+
+	libdur "github.com/nabbar/golib/duration"
+	monbrk "github.com/nabbar/golib/monitor/breaker"
+	monsts "github.com/nabbar/golib/monitor/status"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func newBreaker(window, coolDown time.Duration) monbrk.Breaker {
+	cfg := monbrk.DefaultConfig("test")
+	cfg.MinRequests = 2
+	cfg.Window = libdur.ParseDuration(window)
+	cfg.CoolDown = libdur.ParseDuration(coolDown)
+
+	b, e := monbrk.New(cfg)
+	Expect(e).ToNot(HaveOccurred())
+
+	return b
+}
+
+var _ = Describe("Monitor Breaker", func() {
+	Context("Feed the breaker with a monitor status", func() {
+		It("Must open on KO and wait the cool-down even on OK", func() {
+			b := newBreaker(time.Second, 100*time.Millisecond)
+			Expect(b.State()).To(Equal(monbrk.Closed))
+
+			b.Feed(monsts.KO)
+			Expect(b.State()).To(Equal(monbrk.Open))
+			Expect(b.Allow()).To(HaveOccurred())
+
+			b.Feed(monsts.OK)
+			Expect(b.State()).To(Equal(monbrk.Open))
+			Expect(b.Allow()).To(HaveOccurred())
+
+			time.Sleep(150 * time.Millisecond)
+			Expect(b.State()).To(Equal(monbrk.HalfOpen))
+			Expect(b.Allow()).ToNot(HaveOccurred())
+			Expect(b.Allow()).To(HaveOccurred())
+
+			b.Success()
+			Expect(b.State()).To(Equal(monbrk.Closed))
+			Expect(b.Counts().TotalRejected).To(BeNumerically("==", 3))
+		})
+
+		It("Must open again on KO while half-open", func() {
+			b := newBreaker(time.Second, 50*time.Millisecond)
+
+			b.Feed(monsts.KO)
+			time.Sleep(80 * time.Millisecond)
+			Expect(b.State()).To(Equal(monbrk.HalfOpen))
+
+			b.Feed(monsts.KO)
+			Expect(b.State()).To(Equal(monbrk.Open))
+		})
+	})
+
+	Context("Record calls into the rolling window", func() {
+		It("Must open when the failure ratio is reached", func() {
+			b := newBreaker(time.Second, time.Minute)
+
+			b.Success()
+			b.Failure()
+			Expect(b.State()).To(Equal(monbrk.Open))
+
+			c := b.Counts()
+			Expect(c.Requests).To(BeNumerically("==", 2))
+			Expect(c.Failures).To(BeNumerically("==", 1))
+		})
+
+		It("Must forget the calls older than the window while polled", func() {
+			b := newBreaker(400*time.Millisecond, time.Minute)
+			b.Success()
+
+			// polling faster than a bucket must not delay the expiry of the calls
+			for t := time.Now(); time.Since(t) < 500*time.Millisecond; {
+				time.Sleep(30 * time.Millisecond)
+				_ = b.Counts()
+			}
+
+			Expect(b.Counts().Requests).To(BeNumerically("==", 0))
+			Expect(b.Counts().TotalSuccess).To(BeNumerically("==", 1))
+		})
+
+		It("Must forget all calls after a long idle time", func() {
+			b := newBreaker(100*time.Millisecond, time.Minute)
+			b.Success()
+			b.Success()
+
+			time.Sleep(250 * time.Millisecond)
+			Expect(b.Counts().Requests).To(BeNumerically("==", 0))
+
+			b.Success()
+			Expect(b.Counts().Requests).To(BeNumerically("==", 1))
+		})
+	})
+})
@@ -0,0 +1,126 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package breaker
+
+import (
+	"fmt"
+	"time"
+
+	libval "github.com/go-playground/validator/v10"
+	libdur "github.com/nabbar/golib/duration"
+	liberr "github.com/nabbar/golib/errors"
+)
+
+const (
+	defaultWindow       = 10 * time.Second
+	defaultMinRequests  = 10
+	defaultFailureRatio = 0.5
+	defaultCoolDown     = 30 * time.Second
+	defaultHalfOpen     = 1
+	windowBuckets       = 10
+)
+
+type Config struct {
+	// Name define the name of the circuit breaker.
+	Name string `json:"name" yaml:"name" toml:"name" mapstructure:"name" validate:"required"`
+
+	// Window define the rolling window used to compute the failure ratio. Default is 10 seconds.
+	Window libdur.Duration `json:"window" yaml:"window" toml:"window" mapstructure:"window"`
+
+	// MinRequests define the minimal number of calls into the window before computing the failure ratio. Default is 10.
+	MinRequests uint32 `json:"min-requests" yaml:"min-requests" toml:"min-requests" mapstructure:"min-requests"`
+
+	// FailureRatio define the ratio of failed calls into the window opening the circuit (0 to 1). Default is 0.5.
+	FailureRatio float64 `json:"failure-ratio" yaml:"failure-ratio" toml:"failure-ratio" mapstructure:"failure-ratio" validate:"gte=0,lte=1"`
+
+	// CoolDown define the time the circuit stay open before allowing trial calls. Default is 30 seconds.
+	CoolDown libdur.Duration `json:"cool-down" yaml:"cool-down" toml:"cool-down" mapstructure:"cool-down"`
+
+	// HalfOpenRequests define the number of successful trial calls needed to close the circuit. Default is 1.
+	HalfOpenRequests uint32 `json:"half-open-requests" yaml:"half-open-requests" toml:"half-open-requests" mapstructure:"half-open-requests"`
+}
+
+func (o Config) Validate() liberr.Error {
+	var e = ErrorValidatorError.Error(nil)
+
+	if err := libval.New().Struct(o); err != nil {
+		if er, ok := err.(*libval.InvalidValidationError); ok {
+			e.Add(er)
+		}
+
+		for _, er := range err.(libval.ValidationErrors) {
+			//nolint #goerr113
+			e.Add(fmt.Errorf("config field '%s' is not validated by constraint '%s'", er.Namespace(), er.ActualTag()))
+		}
+	}
+
+	if !e.HasParent() {
+		e = nil
+	}
+
+	return e
+}
+
+func (o Config) window() time.Duration {
+	if d := o.Window.Time(); d > 0 {
+		return d
+	}
+
+	return defaultWindow
+}
+
+func (o Config) minRequests() uint64 {
+	if o.MinRequests > 0 {
+		return uint64(o.MinRequests)
+	}
+
+	return defaultMinRequests
+}
+
+func (o Config) failureRatio() float64 {
+	if o.FailureRatio > 0 {
+		return o.FailureRatio
+	}
+
+	return defaultFailureRatio
+}
+
+func (o Config) coolDown() time.Duration {
+	if d := o.CoolDown.Time(); d > 0 {
+		return d
+	}
+
+	return defaultCoolDown
+}
+
+func (o Config) halfOpen() uint64 {
+	if o.HalfOpenRequests > 0 {
+		return uint64(o.HalfOpenRequests)
+	}
+
+	return defaultHalfOpen
+}
@@ -0,0 +1,142 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package breaker
+
+import (
+	"context"
+	"sync"
+
+	libdur "github.com/nabbar/golib/duration"
+	liberr "github.com/nabbar/golib/errors"
+	monsts "github.com/nabbar/golib/monitor/status"
+	montps "github.com/nabbar/golib/monitor/types"
+	libprm "github.com/nabbar/golib/prometheus"
+)
+
+// State is the state of the circuit breaker.
+type State uint8
+
+const (
+	// Closed let all calls pass and count the failures.
+	Closed State = iota
+	// HalfOpen let a limited number of trial calls pass after the cool-down.
+	HalfOpen
+	// Open reject all calls until the end of the cool-down.
+	Open
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "open"
+	}
+}
+
+// Counts is a snapshot of the counters of the circuit breaker.
+type Counts struct {
+	// Requests is the number of calls into the current window.
+	Requests uint64
+	// Failures is the number of failed calls into the current window.
+	Failures uint64
+	// TotalSuccess is the total number of succeeded calls.
+	TotalSuccess uint64
+	// TotalFailure is the total number of failed calls.
+	TotalFailure uint64
+	// TotalRejected is the total number of calls rejected while the circuit is open.
+	TotalRejected uint64
+}
+
+type Breaker interface {
+	// Name return the name of the circuit breaker.
+	Name() string
+
+	// State return the current state of the circuit breaker.
+	State() State
+
+	// Counts return a snapshot of the counters of the circuit breaker.
+	Counts() Counts
+
+	// Allow check if a call can be done. If the circuit is open, an ErrorBreakerOpen error is returned.
+	// Otherwise, the result of the call must be reported with Success or Failure.
+	Allow() liberr.Error
+
+	// Success report a succeeded call.
+	Success()
+
+	// Failure report a failed call.
+	Failure()
+
+	// Execute run the given function if the circuit is not open and report its result.
+	Execute(ctx context.Context, fct montps.HealthCheck) error
+
+	// Wrap return a function running the given function through the circuit breaker.
+	Wrap(fct montps.HealthCheck) montps.HealthCheck
+
+	// SetMonitor link the circuit breaker with the status of a monitor : while the monitor
+	// is KO, the circuit is open. Giving nil will remove the link.
+	SetMonitor(mon montps.MonitorStatus)
+
+	// Feed update the circuit breaker with a status : a KO status open the circuit,
+	// other status are ignored as an open circuit allow trial calls only after the cool-down.
+	Feed(sts monsts.Status)
+
+	// Reset close the circuit and reset the counters of the window.
+	Reset()
+
+	// RegisterMetrics register the prometheus metrics of the circuit breaker (state and calls).
+	RegisterMetrics(prm libprm.FuncGetPrometheus) error
+}
+
+func New(cfg Config) (Breaker, liberr.Error) {
+	if e := cfg.Validate(); e != nil {
+		return nil, e
+	}
+
+	var o = &brk{
+		m: sync.Mutex{},
+		c: cfg,
+		w: newWindow(cfg.window()),
+	}
+
+	return o, nil
+}
+
+// DefaultConfig return a config with the given name and the default values.
+func DefaultConfig(name string) Config {
+	return Config{
+		Name:             name,
+		MinRequests:      defaultMinRequests,
+		FailureRatio:     defaultFailureRatio,
+		HalfOpenRequests: defaultHalfOpen,
+		Window:           libdur.ParseDuration(defaultWindow),
+		CoolDown:         libdur.ParseDuration(defaultCoolDown),
+	}
+}
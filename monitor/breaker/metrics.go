@@ -0,0 +1,128 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package breaker
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	libprm "github.com/nabbar/golib/prometheus"
+	libmet "github.com/nabbar/golib/prometheus/metrics"
+	prmtps "github.com/nabbar/golib/prometheus/types"
+)
+
+const (
+	metricBaseName = "breaker"
+	metricState    = "state"
+	metricCalls    = "calls"
+	metricResult   = "result"
+
+	resultSuccess  = "success"
+	resultFailure  = "failure"
+	resultRejected = "rejected"
+)
+
+func (o *brk) normalizeName(name string) string {
+	name = strings.ToLower(name)
+
+	name = strings.Replace(name, " ", "_", -1)
+	name = strings.Replace(name, "-", "_", -1)
+	name = strings.Replace(name, ".", "", -1)
+
+	for strings.Contains(name, "__") {
+		name = strings.Replace(name, "__", "_", -1)
+	}
+
+	return name
+}
+
+// getMetricName return the metric name, including the breaker name
+// to allow registering several breakers into the same prometheus instance.
+func (o *brk) getMetricName(metric string) string {
+	return strings.Join([]string{metricBaseName, o.normalizeName(o.Name()), o.normalizeName(metric)}, "_")
+}
+
+func (o *brk) RegisterMetrics(fct libprm.FuncGetPrometheus) error {
+	var prm libprm.Prometheus
+
+	if fct == nil {
+		return ErrorParamEmpty.Error(nil)
+	} else if prm = fct(); prm == nil {
+		return ErrorParamEmpty.Error(nil)
+	}
+
+	sta := libmet.NewMetrics(o.getMetricName(metricState), prmtps.Gauge)
+	sta.SetDesc("the state of the circuit breaker (0 = closed, 1 = half-open, 2 = open)")
+	sta.AddLabel(metricBaseName)
+	sta.SetCollect(o.collectMetricState)
+
+	if e := prm.AddMetric(false, sta); e != nil {
+		return e
+	}
+
+	cal := libmet.NewMetrics(o.getMetricName(metricCalls), prmtps.Counter)
+	cal.SetDesc("the total number of calls through the circuit breaker by result")
+	cal.AddLabel(metricBaseName, metricResult)
+	cal.SetCollect(o.newCollectMetricCalls())
+
+	return prm.AddMetric(false, cal)
+}
+
+func (o *brk) collectMetricState(ctx context.Context, m libmet.Metric) {
+	_ = m.SetGaugeValue([]string{o.Name()}, float64(o.State()))
+}
+
+// newCollectMetricCalls return a collect func adding to the counters
+// the calls done since the last collect.
+func (o *brk) newCollectMetricCalls() libmet.FuncCollect {
+	var (
+		mux  = sync.Mutex{}
+		last Counts
+	)
+
+	return func(ctx context.Context, m libmet.Metric) {
+		mux.Lock()
+		defer mux.Unlock()
+
+		cur := o.Counts()
+
+		if d := cur.TotalSuccess - last.TotalSuccess; d > 0 {
+			_ = m.Add([]string{o.Name(), resultSuccess}, float64(d))
+		}
+
+		if d := cur.TotalFailure - last.TotalFailure; d > 0 {
+			_ = m.Add([]string{o.Name(), resultFailure}, float64(d))
+		}
+
+		if d := cur.TotalRejected - last.TotalRejected; d > 0 {
+			_ = m.Add([]string{o.Name(), resultRejected}, float64(d))
+		}
+
+		last = cur
+	}
+}
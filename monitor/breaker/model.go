@@ -0,0 +1,227 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package breaker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	liberr "github.com/nabbar/golib/errors"
+	monsts "github.com/nabbar/golib/monitor/status"
+	montps "github.com/nabbar/golib/monitor/types"
+)
+
+type brk struct {
+	m sync.Mutex
+	c Config
+	w *window
+
+	s State     // current state
+	o time.Time // time of the last opening
+	h uint64    // number of trial calls given in half-open state
+	k uint64    // number of succeeded trial calls in half-open state
+
+	p montps.MonitorStatus // linked monitor
+
+	ts uint64 // total success
+	tf uint64 // total failure
+	tr uint64 // total rejected
+}
+
+func (o *brk) Name() string {
+	return o.c.Name
+}
+
+func (o *brk) State() State {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	o.pull()
+	o.expire(time.Now())
+
+	return o.s
+}
+
+func (o *brk) Counts() Counts {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	r, f := o.w.sum(time.Now())
+
+	return Counts{
+		Requests:      r,
+		Failures:      f,
+		TotalSuccess:  o.ts,
+		TotalFailure:  o.tf,
+		TotalRejected: o.tr,
+	}
+}
+
+func (o *brk) Allow() liberr.Error {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	o.pull()
+	o.expire(time.Now())
+
+	switch o.s {
+	case Closed:
+		return nil
+	case HalfOpen:
+		if o.h < o.c.halfOpen() {
+			o.h++
+			return nil
+		}
+	}
+
+	o.tr++
+	return ErrorBreakerOpen.Error(nil)
+}
+
+func (o *brk) Success() {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	o.ts++
+
+	switch o.s {
+	case HalfOpen:
+		if o.k++; o.k >= o.c.halfOpen() {
+			o.close()
+		}
+	case Closed:
+		o.w.add(time.Now(), false)
+	}
+}
+
+func (o *brk) Failure() {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	var now = time.Now()
+
+	o.tf++
+
+	switch o.s {
+	case HalfOpen:
+		o.open(now)
+	case Closed:
+		o.w.add(now, true)
+
+		if r, f := o.w.sum(now); r >= o.c.minRequests() && float64(f)/float64(r) >= o.c.failureRatio() {
+			o.open(now)
+		}
+	}
+}
+
+func (o *brk) Execute(ctx context.Context, fct montps.HealthCheck) error {
+	if fct == nil {
+		return ErrorMissingHealthCheck.Error(nil)
+	} else if e := o.Allow(); e != nil {
+		return e
+	}
+
+	if e := fct(ctx); e != nil {
+		o.Failure()
+		return e
+	}
+
+	o.Success()
+	return nil
+}
+
+func (o *brk) Wrap(fct montps.HealthCheck) montps.HealthCheck {
+	return func(ctx context.Context) error {
+		return o.Execute(ctx, fct)
+	}
+}
+
+func (o *brk) SetMonitor(mon montps.MonitorStatus) {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	o.p = mon
+}
+
+func (o *brk) Feed(sts monsts.Status) {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	o.feed(sts)
+}
+
+func (o *brk) Reset() {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	o.close()
+}
+
+// pull feed the breaker with the status of the linked monitor if any.
+func (o *brk) pull() {
+	if o.p == nil {
+		return
+	}
+
+	o.feed(o.p.Status())
+}
+
+// feed open the circuit on a KO status. Other status are ignored : an open
+// circuit only allow trial calls once the cool-down is over.
+func (o *brk) feed(sts monsts.Status) {
+	if sts == monsts.KO && o.s != Open {
+		o.open(time.Now())
+	}
+}
+
+// expire move an open circuit to half-open when the cool-down is over.
+func (o *brk) expire(now time.Time) {
+	if o.s == Open && now.Sub(o.o) >= o.c.coolDown() {
+		o.half()
+	}
+}
+
+func (o *brk) open(now time.Time) {
+	o.s = Open
+	o.o = now
+	o.h = 0
+	o.k = 0
+}
+
+func (o *brk) half() {
+	o.s = HalfOpen
+	o.h = 0
+	o.k = 0
+}
+
+func (o *brk) close() {
+	o.s = Closed
+	o.h = 0
+	o.k = 0
+	o.w.reset()
+}
@@ -0,0 +1,100 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package breaker
+
+import "time"
+
+type bucket struct {
+	r uint64
+	f uint64
+}
+
+// window is a rolling window split into buckets of the same duration.
+type window struct {
+	d time.Duration
+	b [windowBuckets]bucket
+	i int
+	t time.Time
+}
+
+func newWindow(d time.Duration) *window {
+	return &window{
+		d: d / windowBuckets,
+		t: time.Now(),
+	}
+}
+
+func (w *window) roll(now time.Time) {
+	if w.d <= 0 {
+		return
+	}
+
+	var n = int(now.Sub(w.t) / w.d)
+
+	if n <= 0 {
+		return
+	} else if n >= windowBuckets {
+		w.b = [windowBuckets]bucket{}
+		w.i = 0
+		w.t = now
+		return
+	}
+
+	for k := 0; k < n; k++ {
+		w.i = (w.i + 1) % windowBuckets
+		w.b[w.i] = bucket{}
+	}
+
+	// keep the bucket boundaries aligned on the start of the window
+	w.t = w.t.Add(time.Duration(n) * w.d)
+}
+
+func (w *window) add(now time.Time, failure bool) {
+	w.roll(now)
+	w.b[w.i].r++
+
+	if failure {
+		w.b[w.i].f++
+	}
+}
+
+func (w *window) sum(now time.Time) (req uint64, fail uint64) {
+	w.roll(now)
+
+	for _, b := range w.b {
+		req += b.r
+		fail += b.f
+	}
+
+	return req, fail
+}
+
+func (w *window) reset() {
+	w.b = [windowBuckets]bucket{}
+	w.i = 0
+	w.t = time.Now()
+}
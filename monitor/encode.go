@@ -40,6 +40,7 @@ const (
 	encTextSepStatus = ": "
 	encTextSepPart   = " | "
 	encTextSepTime   = " / "
+	encTextFlapping  = "flapping"
 )
 
 type Encode interface {
@@ -57,6 +58,9 @@ type encodeModel struct {
 	Downtime string
 
 	Message string
+
+	Flapping bool
+	History  []moninf.HistoryItem
 }
 
 func (e *encodeModel) Bytes() []byte {
@@ -92,6 +96,10 @@ func (e *encodeModel) stringPart() string {
 	item = append(item, e.stringName())
 	item = append(item, e.stringDuration())
 
+	if e.Flapping {
+		item = append(item, encTextFlapping)
+	}
+
 	if len(e.Message) > 0 {
 		item = append(item, e.Message)
 	}
@@ -108,6 +116,8 @@ func (o *mon) getEncodeModel() Encode {
 		Uptime:   o.Uptime().Truncate(time.Second).String(),
 		Downtime: o.Downtime().Truncate(time.Second).String(),
 		Message:  o.Message(),
+		Flapping: o.IsFlapping(),
+		History:  o.History(),
 	}
 }
 
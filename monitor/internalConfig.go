@@ -46,6 +46,9 @@ type runCfg struct {
 	fallCountWarn uint8
 	riseCountKO   uint8
 	riseCountWarn uint8
+	historySize   uint16
+	flapWindow    time.Duration
+	flapThreshold uint8
 }
 
 func (o *mon) defConfig() *runCfg {
@@ -83,6 +86,14 @@ func (o *mon) defConfig() *runCfg {
 		cfg.riseCountWarn = 1
 	}
 
+	if cfg.historySize < 1 {
+		cfg.historySize = defaultHistorySize
+	}
+
+	if cfg.flapWindow < time.Second {
+		cfg.flapWindow = defaultFlapFactor * cfg.intervalCheck
+	}
+
 	o.x.Store(keyConfig, cfg)
 	return cfg
 }
@@ -125,6 +136,9 @@ func (o *mon) SetConfig(ctx libctx.FuncContext, cfg montps.Config) liberr.Error
 		fallCountWarn: cfg.FallCountWarn,
 		riseCountKO:   cfg.RiseCountKO,
 		riseCountWarn: cfg.RiseCountWarn,
+		historySize:   cfg.HistorySize,
+		flapWindow:    cfg.FlapWindow.Time(),
+		flapThreshold: cfg.FlapThreshold,
 	}
 
 	if cnf.checkTimeout < 5*time.Second {
@@ -159,6 +173,14 @@ func (o *mon) SetConfig(ctx libctx.FuncContext, cfg montps.Config) liberr.Error
 		cnf.riseCountWarn = 1
 	}
 
+	if cnf.historySize < 1 {
+		cnf.historySize = defaultHistorySize
+	}
+
+	if cnf.flapWindow < time.Second {
+		cnf.flapWindow = defaultFlapFactor * cnf.intervalCheck
+	}
+
 	o.x.Store(keyConfig, cnf)
 
 	var n liblog.Logger
@@ -201,6 +223,9 @@ func (o *mon) GetConfig() montps.Config {
 		FallCountWarn: cfg.fallCountWarn,
 		RiseCountKO:   cfg.riseCountKO,
 		RiseCountWarn: cfg.riseCountWarn,
+		HistorySize:   cfg.historySize,
+		FlapWindow:    libdur.ParseDuration(cfg.flapWindow),
+		FlapThreshold: cfg.flapThreshold,
 		Logger:        *opt,
 	}
 }
@@ -31,7 +31,9 @@ import (
 	"sync"
 	"time"
 
+	libdur "github.com/nabbar/golib/duration"
 	monsts "github.com/nabbar/golib/monitor/status"
+	montps "github.com/nabbar/golib/monitor/types"
 )
 
 type lastRun struct {
@@ -53,6 +55,12 @@ type lastRun struct {
 	latency  time.Duration
 
 	err error
+
+	hist []montps.HistoryItem // ring buffer of last results
+	hidx int                  // next position into the ring buffer
+	hcnt int                  // number of items into the ring buffer
+	flip []time.Time          // time of the last status changes
+	flap bool
 }
 
 func newLastRun() *lastRun {
@@ -70,6 +78,11 @@ func newLastRun() *lastRun {
 		fallTime: 0,
 		latency:  0,
 		err:      fmt.Errorf("no healcheck still run"),
+		hist:     nil,
+		hidx:     0,
+		hcnt:     0,
+		flip:     make([]time.Time, 0),
+		flap:     false,
 	}
 }
 
@@ -127,10 +140,34 @@ func (o *lastRun) Error() error {
 	return o.err
 }
 
+func (o *lastRun) History() []montps.HistoryItem {
+	o.m.RLock()
+	defer o.m.RUnlock()
+
+	var (
+		res = make([]montps.HistoryItem, 0, o.hcnt)
+		siz = len(o.hist)
+	)
+
+	for i := 0; i < o.hcnt; i++ {
+		res = append(res, o.hist[(o.hidx-o.hcnt+i+siz)%siz])
+	}
+
+	return res
+}
+
+func (o *lastRun) IsFlapping() bool {
+	o.m.RLock()
+	defer o.m.RUnlock()
+	return o.flap
+}
+
 func (o *lastRun) setStatus(err error, dur time.Duration, cfg *runCfg) {
 	o.m.Lock()
 	defer o.m.Unlock()
 
+	var sts = o.status
+
 	o.latency = dur
 
 	if err != nil {
@@ -140,6 +177,73 @@ func (o *lastRun) setStatus(err error, dur time.Duration, cfg *runCfg) {
 		o.err = nil
 		o.setStatusRise(cfg)
 	}
+
+	if cfg == nil {
+		return
+	}
+
+	o.addHistory(cfg)
+	o.setFlapping(sts != o.status, cfg)
+}
+
+func (o *lastRun) addHistory(cfg *runCfg) {
+	var itm = montps.HistoryItem{
+		Time:    o.runtime,
+		Status:  o.status,
+		Latency: libdur.ParseDuration(o.latency),
+	}
+
+	if o.err != nil {
+		itm.Message = o.err.Error()
+	}
+
+	if siz := int(cfg.historySize); len(o.hist) != siz {
+		// size changed : keep the newest items into a new buffer
+		old := o.hist
+		cnt := o.hcnt
+		idx := o.hidx
+
+		o.hist = make([]montps.HistoryItem, siz)
+		o.hidx = 0
+		o.hcnt = 0
+
+		if cnt > siz {
+			cnt = siz
+		}
+
+		for i := cnt; i > 0; i-- {
+			o.hist[o.hidx] = old[(idx-i+len(old))%len(old)]
+			o.hidx = (o.hidx + 1) % siz
+			o.hcnt++
+		}
+	}
+
+	o.hist[o.hidx] = itm
+	o.hidx = (o.hidx + 1) % len(o.hist)
+
+	if o.hcnt < len(o.hist) {
+		o.hcnt++
+	}
+}
+
+func (o *lastRun) setFlapping(changed bool, cfg *runCfg) {
+	var (
+		now = o.runtime
+		lst = make([]time.Time, 0, len(o.flip)+1)
+	)
+
+	if changed {
+		o.flip = append(o.flip, now)
+	}
+
+	for _, t := range o.flip {
+		if now.Sub(t) <= cfg.flapWindow {
+			lst = append(lst, t)
+		}
+	}
+
+	o.flip = lst
+	o.flap = cfg.flapThreshold > 0 && len(o.flip) >= int(cfg.flapThreshold)
 }
 
 func (o *lastRun) setStatusFall(cfg *runCfg) {
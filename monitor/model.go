@@ -38,6 +38,8 @@ import (
 
 const (
 	defaultMonitorName = "not named"
+	defaultHistorySize = 10
+	defaultFlapFactor  = 10
 
 	keyName        = "keyName"
 	keyConfig      = "keyConfig"
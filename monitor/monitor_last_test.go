@@ -0,0 +1,178 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2022 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package monitor
+
+import (
+	"errors"
+	"time"
+
+	monsts "github.com/nabbar/golib/monitor/status"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// the history and flapping detection are internal to the last run, so this file is part of the package.
+
+func lastRunConfig(size uint16, window time.Duration, threshold uint8) *runCfg {
+	return &runCfg{
+		fallCountKO:   1,
+		fallCountWarn: 1,
+		riseCountKO:   1,
+		riseCountWarn: 1,
+		historySize:   size,
+		flapWindow:    window,
+		flapThreshold: threshold,
+	}
+}
+
+func historyLatency(o *lastRun) []time.Duration {
+	var res = make([]time.Duration, 0)
+
+	for _, i := range o.History() {
+		res = append(res, i.Latency.Time())
+	}
+
+	return res
+}
+
+var _ = Describe("Monitor Last Run", func() {
+	Context("Keep the history of results", func() {
+		It("Must keep all results while the buffer is not full", func() {
+			var (
+				o = newLastRun()
+				c = lastRunConfig(3, time.Minute, 0)
+			)
+
+			Expect(o.History()).To(BeEmpty())
+
+			o.setStatus(nil, time.Millisecond, c)
+			o.setStatus(errors.New("boom"), 2*time.Millisecond, c)
+
+			h := o.History()
+			Expect(h).To(HaveLen(2))
+			Expect(h[0].Status).To(Equal(monsts.Warn))
+			Expect(h[0].Message).To(BeEmpty())
+			Expect(h[1].Status).To(Equal(monsts.KO))
+			Expect(h[1].Message).To(Equal("boom"))
+			Expect(h[1].Time).ToNot(BeTemporally("<", h[0].Time))
+		})
+
+		It("Must keep the newest results in order when wrapping around", func() {
+			var (
+				o = newLastRun()
+				c = lastRunConfig(3, time.Minute, 0)
+			)
+
+			for i := 1; i <= 7; i++ {
+				o.setStatus(nil, time.Duration(i)*time.Millisecond, c)
+			}
+
+			Expect(historyLatency(o)).To(Equal([]time.Duration{
+				5 * time.Millisecond, 6 * time.Millisecond, 7 * time.Millisecond,
+			}))
+		})
+
+		It("Must keep the newest results when the size changes", func() {
+			var (
+				o = newLastRun()
+				c = lastRunConfig(3, time.Minute, 0)
+			)
+
+			for i := 1; i <= 4; i++ {
+				o.setStatus(nil, time.Duration(i)*time.Millisecond, c)
+			}
+
+			c.historySize = 2
+			o.setStatus(nil, 5*time.Millisecond, c)
+			Expect(historyLatency(o)).To(Equal([]time.Duration{
+				4 * time.Millisecond, 5 * time.Millisecond,
+			}))
+
+			c.historySize = 4
+			o.setStatus(nil, 6*time.Millisecond, c)
+			Expect(historyLatency(o)).To(Equal([]time.Duration{
+				4 * time.Millisecond, 5 * time.Millisecond, 6 * time.Millisecond,
+			}))
+
+			o.setStatus(nil, 7*time.Millisecond, c)
+			o.setStatus(nil, 8*time.Millisecond, c)
+			Expect(historyLatency(o)).To(Equal([]time.Duration{
+				5 * time.Millisecond, 6 * time.Millisecond, 7 * time.Millisecond, 8 * time.Millisecond,
+			}))
+		})
+	})
+
+	Context("Detect a flapping status", func() {
+		It("Must flap when the status changes reach the threshold", func() {
+			var (
+				o = newLastRun()
+				c = lastRunConfig(10, time.Minute, 3)
+			)
+
+			o.setStatus(nil, 0, c) // KO -> Warn
+			o.setStatus(nil, 0, c) // Warn -> OK
+			Expect(o.IsFlapping()).To(BeFalse())
+
+			o.setStatus(errors.New("boom"), 0, c) // OK -> Warn
+			Expect(o.IsFlapping()).To(BeTrue())
+		})
+
+		It("Must never flap without threshold", func() {
+			var (
+				o = newLastRun()
+				c = lastRunConfig(10, time.Minute, 0)
+			)
+
+			for i := 0; i < 10; i++ {
+				if i%2 == 0 {
+					o.setStatus(nil, 0, c)
+				} else {
+					o.setStatus(errors.New("boom"), 0, c)
+				}
+			}
+
+			Expect(o.IsFlapping()).To(BeFalse())
+		})
+
+		It("Must stop flapping when the changes are older than the window", func() {
+			var (
+				o = newLastRun()
+				c = lastRunConfig(10, 100*time.Millisecond, 2)
+			)
+
+			o.setStatus(nil, 0, c) // KO -> Warn
+			o.setStatus(nil, 0, c) // Warn -> OK
+			Expect(o.IsFlapping()).To(BeTrue())
+
+			time.Sleep(150 * time.Millisecond)
+
+			o.setStatus(nil, 0, c) // still OK
+			Expect(o.Status()).To(Equal(monsts.OK))
+			Expect(o.IsFlapping()).To(BeFalse())
+		})
+	})
+})
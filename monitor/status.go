@@ -97,6 +97,14 @@ func (o *mon) Downtime() time.Duration {
 	return o.getLastCheck().DownTime()
 }
 
+func (o *mon) History() []montps.HistoryItem {
+	return o.getLastCheck().History()
+}
+
+func (o *mon) IsFlapping() bool {
+	return o.getLastCheck().IsFlapping()
+}
+
 func (o *mon) mdlStatus(m middleWare) error {
 	ts := time.Now()
 	err := m.Next()
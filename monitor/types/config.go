@@ -48,6 +48,9 @@ var _defaultConfig = []byte(`{
   "fall-count-warn": "",
   "rise-count-ko": "",
   "rise-count-warn": "",
+  "history-size": 0,
+  "flap-window": "",
+  "flap-threshold": 0,
   "logger": ` + string(logcfg.DefaultConfig(cfgtps.JSONIndent+cfgtps.JSONIndent)) + `
 }`)
 
@@ -92,6 +95,15 @@ type Config struct {
 	// RiseCountWarn define the number of OK when status is Warn before considerate the component as up.
 	RiseCountWarn uint8 `json:"rise-count-warn" yaml:"rise-count-warn" toml:"rise-count-warn" mapstructure:"rise-count-warn"`
 
+	// HistorySize define the number of healthcheck results kept into the history. Default is 10.
+	HistorySize uint16 `json:"history-size" yaml:"history-size" toml:"history-size" mapstructure:"history-size"`
+
+	// FlapWindow define the window used to count the status changes for flapping detection. Default is 10 times the interval check.
+	FlapWindow libdur.Duration `json:"flap-window" yaml:"flap-window" toml:"flap-window" mapstructure:"flap-window"`
+
+	// FlapThreshold define the number of status changes into the flapping window to considerate the component as unstable. Zero disable the flapping detection.
+	FlapThreshold uint8 `json:"flap-threshold" yaml:"flap-threshold" toml:"flap-threshold" mapstructure:"flap-threshold"`
+
 	// Logger define the logger options for current monitor log
 	Logger logcfg.Options `json:"logger" yaml:"logger" toml:"logger" mapstructure:"logger"`
 }
@@ -128,6 +140,9 @@ func (o Config) Clone() Config {
 		FallCountWarn: o.FallCountWarn,
 		RiseCountKO:   o.RiseCountKO,
 		RiseCountWarn: o.RiseCountWarn,
+		HistorySize:   o.HistorySize,
+		FlapWindow:    o.FlapWindow,
+		FlapThreshold: o.FlapThreshold,
 		Logger:        o.Logger.Clone(),
 	}
 }
@@ -0,0 +1,49 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+
+package types
+
+import (
+	"time"
+
+	libdur "github.com/nabbar/golib/duration"
+	monsts "github.com/nabbar/golib/monitor/status"
+)
+
+// HistoryItem is the result of one healthcheck run kept into the history of a monitor.
+type HistoryItem struct {
+	// Time is the time of the end of the healthcheck.
+	Time time.Time `json:"time" yaml:"time" toml:"time" mapstructure:"time"`
+
+	// Status is the status of the monitor after the healthcheck.
+	Status monsts.Status `json:"status" yaml:"status" toml:"status" mapstructure:"status"`
+
+	// Latency is the duration of the healthcheck.
+	Latency libdur.Duration `json:"latency" yaml:"latency" toml:"latency" mapstructure:"latency"`
+
+	// Message is the error returned by the healthcheck if any.
+	Message string `json:"message,omitempty" yaml:"message,omitempty" toml:"message,omitempty" mapstructure:"message,omitempty"`
+}
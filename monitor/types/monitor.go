@@ -69,6 +69,12 @@ type MonitorStatus interface {
 
 	// Downtime return the total duration of downtime (KO status)
 	Downtime() time.Duration
+
+	// History return the last healthcheck results, from the oldest to the newest.
+	History() []HistoryItem
+
+	// IsFlapping return true if the status changed too often into the flapping window.
+	IsFlapping() bool
 }
 
 type MonitorMetrics interface {
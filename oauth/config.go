@@ -0,0 +1,176 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+package oauth
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	libval "github.com/go-playground/validator/v10"
+	libdur "github.com/nabbar/golib/duration"
+	liberr "github.com/nabbar/golib/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	StoreNone   = "none"
+	StoreMemory = "memory"
+	StoreFile   = "file"
+
+	AuthStyleAuto   = "auto"
+	AuthStyleHeader = "header"
+	AuthStyleParams = "params"
+)
+
+type ConfigStore struct {
+	// Type define the store of tokens : none, memory (default) or file.
+	// A custom store (like a KV driver) can be given with the SetStore func.
+	Type string `json:"type,omitempty" yaml:"type,omitempty" toml:"type,omitempty" mapstructure:"type,omitempty" validate:"omitempty,oneof=none memory file"`
+
+	// Path define the path of the json file used by the file store.
+	Path string `json:"path,omitempty" yaml:"path,omitempty" toml:"path,omitempty" mapstructure:"path,omitempty"`
+}
+
+type Config struct {
+	// ClientID is the identifier of the application.
+	ClientID string `json:"client-id" yaml:"client-id" toml:"client-id" mapstructure:"client-id" validate:"required"`
+
+	// ClientSecret is the secret of the application, empty for public client (with PKCE).
+	ClientSecret string `json:"client-secret,omitempty" yaml:"client-secret,omitempty" toml:"client-secret,omitempty" mapstructure:"client-secret,omitempty"`
+
+	// AuthURL is the authorization endpoint used for the authorization code grant.
+	AuthURL string `json:"auth-url,omitempty" yaml:"auth-url,omitempty" toml:"auth-url,omitempty" mapstructure:"auth-url,omitempty" validate:"omitempty,url"`
+
+	// TokenURL is the token endpoint.
+	TokenURL string `json:"token-url" yaml:"token-url" toml:"token-url" mapstructure:"token-url" validate:"required,url"`
+
+	// DeviceAuthURL is the device authorization endpoint used for the device code grant.
+	DeviceAuthURL string `json:"device-auth-url,omitempty" yaml:"device-auth-url,omitempty" toml:"device-auth-url,omitempty" mapstructure:"device-auth-url,omitempty" validate:"omitempty,url"`
+
+	// IntrospectURL is the token introspection endpoint (RFC 7662).
+	IntrospectURL string `json:"introspect-url,omitempty" yaml:"introspect-url,omitempty" toml:"introspect-url,omitempty" mapstructure:"introspect-url,omitempty" validate:"omitempty,url"`
+
+	// RedirectURL is the callback url of the authorization code grant.
+	RedirectURL string `json:"redirect-url,omitempty" yaml:"redirect-url,omitempty" toml:"redirect-url,omitempty" mapstructure:"redirect-url,omitempty" validate:"omitempty,url"`
+
+	// Scopes define the list of scopes requested.
+	Scopes []string `json:"scopes,omitempty" yaml:"scopes,omitempty" toml:"scopes,omitempty" mapstructure:"scopes,omitempty"`
+
+	// Params define additional parameters sent to the token endpoint with the client credentials grant (like audience or resource).
+	Params map[string]string `json:"params,omitempty" yaml:"params,omitempty" toml:"params,omitempty" mapstructure:"params,omitempty"`
+
+	// AuthStyle define how the client id and secret are sent to the token endpoint : auto (default), header or params.
+	AuthStyle string `json:"auth-style,omitempty" yaml:"auth-style,omitempty" toml:"auth-style,omitempty" mapstructure:"auth-style,omitempty" validate:"omitempty,oneof=auto header params"`
+
+	// PKCE enable the Proof Key for Code Exchange (RFC 7636) with the authorization code grant.
+	PKCE bool `json:"pkce,omitempty" yaml:"pkce,omitempty" toml:"pkce,omitempty" mapstructure:"pkce,omitempty"`
+
+	// ExpiryDelta define the duration before the expiration of a client credentials token to request a new one.
+	// Default is 10 seconds.
+	ExpiryDelta libdur.Duration `json:"expiry-delta,omitempty" yaml:"expiry-delta,omitempty" toml:"expiry-delta,omitempty" mapstructure:"expiry-delta,omitempty"`
+
+	// Store define where tokens are persisted to keep refreshed tokens.
+	Store ConfigStore `json:"store,omitempty" yaml:"store,omitempty" toml:"store,omitempty" mapstructure:"store,omitempty"`
+}
+
+func (c Config) Validate() liberr.Error {
+	var e = ErrorValidatorError.Error(nil)
+
+	if er := libval.New().Struct(c); er != nil {
+		if err, ok := er.(*libval.InvalidValidationError); ok {
+			e.Add(err)
+		}
+
+		for _, err := range er.(libval.ValidationErrors) {
+			//nolint #goerr113
+			e.Add(fmt.Errorf("config field '%s' is not validated by constraint '%s'", err.Namespace(), err.ActualTag()))
+		}
+	}
+
+	if strings.EqualFold(c.Store.Type, StoreFile) && len(c.Store.Path) < 1 {
+		//nolint #goerr113
+		e.Add(fmt.Errorf("config field '%s' is required with the file store", "Config.Store.Path"))
+	}
+
+	if !e.HasParent() {
+		e = nil
+	}
+
+	return e
+}
+
+func (c Config) authStyle() oauth2.AuthStyle {
+	switch strings.ToLower(c.AuthStyle) {
+	case AuthStyleHeader:
+		return oauth2.AuthStyleInHeader
+	case AuthStyleParams:
+		return oauth2.AuthStyleInParams
+	default:
+		return oauth2.AuthStyleAutoDetect
+	}
+}
+
+// OAuth2 return the oauth2 config for the authorization code, the refresh token and the device code grants.
+func (c Config) OAuth2() *oauth2.Config {
+	var cfg = NewConfigOAuth(c.ClientID, c.ClientSecret, c.TokenURL, c.AuthURL, c.RedirectURL, c.Scopes)
+
+	cfg.Endpoint.DeviceAuthURL = c.DeviceAuthURL
+	cfg.Endpoint.AuthStyle = c.authStyle()
+
+	return cfg
+}
+
+// ClientCredentials return the config for the client credentials grant.
+func (c Config) ClientCredentials() *clientcredentials.Config {
+	var prm url.Values
+
+	if len(c.Params) > 0 {
+		prm = make(url.Values)
+
+		for k, v := range c.Params {
+			prm.Set(k, v)
+		}
+	}
+
+	var cfg = NewConfigClientCredentials(c.ClientID, c.ClientSecret, c.TokenURL, c.Scopes, prm)
+	cfg.AuthStyle = c.authStyle()
+
+	return cfg
+}
+
+// NewStore return the token store defined into the config, or nil for the none store.
+func (c Config) NewStore() TokenStore {
+	switch strings.ToLower(c.Store.Type) {
+	case StoreNone:
+		return nil
+	case StoreFile:
+		return NewStoreFile(c.Store.Path)
+	default:
+		return NewStoreMemory()
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	liberr "github.com/nabbar/golib/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+func NewConfigClientCredentials(clientID, clientSecret, endpointToken string, scopes []string, params url.Values) *clientcredentials.Config {
+	return &clientcredentials.Config{
+		ClientID:       clientID,
+		ClientSecret:   clientSecret,
+		TokenURL:       endpointToken,
+		Scopes:         scopes,
+		EndpointParams: params,
+	}
+}
+
+// NewClientCredentialsTokenSource return a token source for the client credentials grant.
+// The token is kept in cache and a new one is requested only when the current token
+// will expire within the given early duration (the oauth2 default of 10 seconds if zero).
+func NewClientCredentialsTokenSource(ctx context.Context, httpcli *http.Client, cc *clientcredentials.Config, early time.Duration) oauth2.TokenSource {
+	return newClientCredentialsSource(ctx, httpcli, cc, nil, early)
+}
+
+func newClientCredentialsSource(ctx context.Context, httpcli *http.Client, cc *clientcredentials.Config, tok *oauth2.Token, early time.Duration) oauth2.TokenSource {
+	if httpcli != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, httpcli)
+	}
+
+	var src = &ccSource{
+		x: ctx,
+		c: cc,
+	}
+
+	if early > 0 {
+		return oauth2.ReuseTokenSourceWithExpiry(tok, src, early)
+	}
+
+	return oauth2.ReuseTokenSource(tok, src)
+}
+
+// ccSource request a new token on each call, the cache is done by the reuse token source wrapping it.
+type ccSource struct {
+	x context.Context
+	c *clientcredentials.Config
+}
+
+func (s *ccSource) Token() (*oauth2.Token, error) {
+	if s.c == nil {
+		return nil, ErrorEmptyParams.Error(nil)
+	} else if t, e := s.c.Token(s.x); e != nil {
+		return nil, ErrorOAuthToken.Error(e)
+	} else {
+		return t, nil
+	}
+}
+
+// ConfigClientCredentialsToken request a new token with the client credentials grant.
+func ConfigClientCredentialsToken(cc *clientcredentials.Config, ctx context.Context, httpcli *http.Client) (*oauth2.Token, liberr.Error) {
+	if cc == nil {
+		return nil, ErrorEmptyParams.Error(nil)
+	}
+
+	if httpcli != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, httpcli)
+	}
+
+	if tok, err := cc.Token(ctx); err != nil {
+		return nil, ErrorOAuthToken.Error(err)
+	} else {
+		return tok, nil
+	}
+}
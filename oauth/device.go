@@ -0,0 +1,72 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+package oauth
+
+import (
+	"context"
+	"net/http"
+
+	liberr "github.com/nabbar/golib/errors"
+	"golang.org/x/oauth2"
+)
+
+// ConfigDeviceAuth start the device authorization grant (RFC 8628).
+// The endpoint DeviceAuthURL of the config must be defined.
+// The UserCode and the VerificationURI of the response must be displayed to the user.
+func ConfigDeviceAuth(oa *oauth2.Config, ctx context.Context, httpcli *http.Client, opts ...oauth2.AuthCodeOption) (*oauth2.DeviceAuthResponse, liberr.Error) {
+	if oa == nil || len(oa.Endpoint.DeviceAuthURL) < 1 {
+		return nil, ErrorEmptyParams.Error(nil)
+	}
+
+	if httpcli != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, httpcli)
+	}
+
+	if res, err := oa.DeviceAuth(ctx, opts...); err != nil {
+		return nil, ErrorDeviceAuth.Error(err)
+	} else {
+		return res, nil
+	}
+}
+
+// ConfigDeviceToken poll the token endpoint until the user grant or deny the device authorization.
+// The polling respect the interval given by the server (increased on slow_down response)
+// and stop at the expiration of the device code or when the context is done.
+func ConfigDeviceToken(oa *oauth2.Config, ctx context.Context, httpcli *http.Client, da *oauth2.DeviceAuthResponse, opts ...oauth2.AuthCodeOption) (*oauth2.Token, liberr.Error) {
+	if oa == nil || da == nil || len(da.DeviceCode) < 1 {
+		return nil, ErrorEmptyParams.Error(nil)
+	}
+
+	if httpcli != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, httpcli)
+	}
+
+	if tok, err := oa.DeviceAccessToken(ctx, da, opts...); err != nil {
+		return nil, ErrorDeviceToken.Error(err)
+	} else {
+		return tok, nil
+	}
+}
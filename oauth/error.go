@@ -35,6 +35,17 @@ import (
 const (
 	ErrorEmptyParams liberr.CodeError = iota + liberr.MinPkgOAuth
 	ErrorOAuthExchange
+	ErrorParamInvalid
+	ErrorValidatorError
+	ErrorPKCEVerifier
+	ErrorOAuthToken
+	ErrorDeviceAuth
+	ErrorDeviceToken
+	ErrorIntrospect
+	ErrorIntrospectStatus
+	ErrorStoreNotFound
+	ErrorStoreLoad
+	ErrorStoreSave
 )
 
 func init() {
@@ -50,6 +61,28 @@ func getMessage(code liberr.CodeError) (message string) {
 		return "given parameters is empty"
 	case ErrorOAuthExchange:
 		return "code seems to be invalid when trying to get token from it"
+	case ErrorParamInvalid:
+		return "at least one given parameters is invalid"
+	case ErrorValidatorError:
+		return "oauth : invalid config"
+	case ErrorPKCEVerifier:
+		return "pkce code verifier does not match the code challenge"
+	case ErrorOAuthToken:
+		return "cannot retrieve a token from the token endpoint"
+	case ErrorDeviceAuth:
+		return "cannot start the device authorization"
+	case ErrorDeviceToken:
+		return "device authorization has not been granted"
+	case ErrorIntrospect:
+		return "cannot introspect the token"
+	case ErrorIntrospectStatus:
+		return "introspection endpoint returns an invalid status"
+	case ErrorStoreNotFound:
+		return "token is not found into the store"
+	case ErrorStoreLoad:
+		return "cannot load the token from the store"
+	case ErrorStoreSave:
+		return "cannot save the token into the store"
 	}
 
 	return liberr.NullMessage
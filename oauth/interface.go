@@ -0,0 +1,95 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	liberr "github.com/nabbar/golib/errors"
+	"golang.org/x/oauth2"
+)
+
+// KeyClientCredentials is the prefix of the key used to store the token of the client credentials grant.
+const KeyClientCredentials = "client-credentials/"
+
+type OAuth interface {
+	// Config return a copy of the current config.
+	Config() Config
+
+	// SetHTTPClient define the http client used for all requests sent to the server.
+	SetHTTPClient(cli *http.Client)
+	// SetStore replace the token store defined into the config (by example, with NewStoreKV).
+	SetStore(store TokenStore)
+	// Store return the current token store or nil.
+	Store() TokenStore
+
+	// AuthCodeURL return the url of the consent page of the authorization code grant.
+	// If the PKCE is enabled, the generated PKCE is returned and its Verifier must be kept until the Exchange.
+	AuthCodeURL(state string, online bool) (string, PKCE)
+	// Exchange the authorization code for a token and save it into the store with the given key if not empty.
+	// The verifier is the PKCE Verifier given by AuthCodeURL if the PKCE is enabled.
+	Exchange(ctx context.Context, key, code, verifier string) (*oauth2.Token, liberr.Error)
+
+	// ClientCredentials return the cached token source of the client credentials grant.
+	// The token is saved into the store with the KeyClientCredentials prefix.
+	ClientCredentials() oauth2.TokenSource
+
+	// DeviceAuth start a device authorization grant.
+	DeviceAuth(ctx context.Context) (*oauth2.DeviceAuthResponse, liberr.Error)
+	// DeviceToken poll the token endpoint until the device authorization is granted
+	// and save the token into the store with the given key if not empty.
+	DeviceToken(ctx context.Context, key string, da *oauth2.DeviceAuthResponse) (*oauth2.Token, liberr.Error)
+
+	// TokenSource return a token source for the token stored with the given key.
+	// The token is refreshed with its refresh token when expired and the new token is saved into the store.
+	TokenSource(key string) (oauth2.TokenSource, liberr.Error)
+	// Client return a http client authenticated with the token stored with the given key.
+	Client(key string) (*http.Client, liberr.Error)
+
+	// Introspect request the introspection endpoint to get the state of the given token.
+	Introspect(ctx context.Context, token, hint string) (*Introspection, liberr.Error)
+}
+
+// New return an OAuth instance for the given config.
+// The context is used for the token sources refreshing tokens in background.
+func New(ctx context.Context, cfg Config) (OAuth, liberr.Error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return &oa{
+		m: sync.RWMutex{},
+		x: ctx,
+		c: cfg,
+		s: cfg.NewStore(),
+	}, nil
+}
@@ -0,0 +1,211 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	liberr "github.com/nabbar/golib/errors"
+)
+
+const (
+	// TokenHintAccess is the token type hint for an access token.
+	TokenHintAccess = "access_token"
+	// TokenHintRefresh is the token type hint for a refresh token.
+	TokenHintRefresh = "refresh_token"
+
+	maxIntrospectSize = 1 << 20
+)
+
+// Introspection is the response of a token introspection endpoint (RFC 7662).
+// Only the Active field is mandatory, other fields are given by the server if the token is active.
+type Introspection struct {
+	Active    bool     `json:"active"`
+	Scope     string   `json:"scope,omitempty"`
+	ClientID  string   `json:"client_id,omitempty"`
+	Username  string   `json:"username,omitempty"`
+	TokenType string   `json:"token_type,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  []string `json:"aud,omitempty"`
+	Issuer    string   `json:"iss,omitempty"`
+	JwtID     string   `json:"jti,omitempty"`
+
+	// Extra hold all other members of the response.
+	Extra map[string]interface{} `json:"-"`
+}
+
+func (i *Introspection) UnmarshalJSON(p []byte) error {
+	type alias Introspection
+
+	var (
+		a = struct {
+			*alias
+			Aud json.RawMessage `json:"aud,omitempty"`
+		}{
+			alias: (*alias)(i),
+		}
+		m = make(map[string]interface{})
+	)
+
+	if e := json.Unmarshal(p, &a); e != nil {
+		return e
+	} else if e = json.Unmarshal(p, &m); e != nil {
+		return e
+	}
+
+	i.Audience = nil
+
+	if len(a.Aud) > 0 && string(a.Aud) != "null" {
+		var s string
+		if e := json.Unmarshal(a.Aud, &s); e == nil {
+			i.Audience = []string{s}
+		} else if e = json.Unmarshal(a.Aud, &i.Audience); e != nil {
+			return e
+		}
+	}
+
+	for _, k := range []string{"active", "scope", "client_id", "username", "token_type", "exp", "iat", "nbf", "sub", "aud", "iss", "jti"} {
+		delete(m, k)
+	}
+
+	if len(m) > 0 {
+		i.Extra = m
+	} else {
+		i.Extra = nil
+	}
+
+	return nil
+}
+
+// Scopes return the list of scopes of the token.
+func (i Introspection) Scopes() []string {
+	return strings.Fields(i.Scope)
+}
+
+// HasScope return true if the token has all the given scopes.
+func (i Introspection) HasScope(scope ...string) bool {
+	var l = i.Scopes()
+
+	for _, s := range scope {
+		var f bool
+
+		for _, v := range l {
+			if v == s {
+				f = true
+				break
+			}
+		}
+
+		if !f {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Expiry return the expiration time of the token or zero time if not given.
+func (i Introspection) Expiry() time.Time {
+	if i.ExpiresAt < 1 {
+		return time.Time{}
+	}
+
+	return time.Unix(i.ExpiresAt, 0)
+}
+
+// IsValid return true if the token is active and not expired.
+func (i Introspection) IsValid() bool {
+	if !i.Active {
+		return false
+	} else if e := i.Expiry(); !e.IsZero() && e.Before(time.Now()) {
+		return false
+	}
+
+	return true
+}
+
+// Introspect request the introspection endpoint (RFC 7662) to get the state of the given token.
+// The client id and secret are used to authenticate to the endpoint with the basic authentication.
+// The hint is optional and can be TokenHintAccess or TokenHintRefresh.
+func Introspect(ctx context.Context, httpcli *http.Client, endpoint, clientID, clientSecret, token, hint string) (*Introspection, liberr.Error) {
+	if len(endpoint) < 1 || len(token) < 1 {
+		return nil, ErrorEmptyParams.Error(nil)
+	}
+
+	if httpcli == nil {
+		httpcli = http.DefaultClient
+	}
+
+	var (
+		err error
+		req *http.Request
+		rsp *http.Response
+		res = &Introspection{}
+		frm = url.Values{"token": {token}}
+	)
+
+	if len(hint) > 0 {
+		frm.Set("token_type_hint", hint)
+	}
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(frm.Encode())); err != nil {
+		return nil, ErrorIntrospect.Error(err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	if len(clientID) > 0 {
+		req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+	}
+
+	if rsp, err = httpcli.Do(req); err != nil {
+		return nil, ErrorIntrospect.Error(err)
+	}
+
+	defer func() {
+		_ = rsp.Body.Close()
+	}()
+
+	if rsp.StatusCode != http.StatusOK {
+		//nolint #goerr113
+		return nil, ErrorIntrospectStatus.Error(fmt.Errorf("status '%s'", rsp.Status))
+	} else if err = json.NewDecoder(io.LimitReader(rsp.Body, maxIntrospectSize)).Decode(res); err != nil {
+		return nil, ErrorIntrospect.Error(err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,205 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	liberr "github.com/nabbar/golib/errors"
+	"golang.org/x/oauth2"
+)
+
+type oa struct {
+	m sync.RWMutex
+	x context.Context
+	c Config
+	h *http.Client
+	s TokenStore
+	t oauth2.TokenSource // cached client credentials token source
+}
+
+func (o *oa) Config() Config {
+	o.m.RLock()
+	defer o.m.RUnlock()
+
+	return o.c
+}
+
+func (o *oa) SetHTTPClient(cli *http.Client) {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	o.h = cli
+	o.t = nil
+}
+
+func (o *oa) SetStore(store TokenStore) {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	o.s = store
+	o.t = nil
+}
+
+func (o *oa) Store() TokenStore {
+	o.m.RLock()
+	defer o.m.RUnlock()
+
+	return o.s
+}
+
+func (o *oa) getClient() *http.Client {
+	o.m.RLock()
+	defer o.m.RUnlock()
+
+	return o.h
+}
+
+func (o *oa) save(key string, tok *oauth2.Token) liberr.Error {
+	var s = o.Store()
+
+	if s == nil || len(key) < 1 || tok == nil {
+		return nil
+	} else if e := s.Save(key, tok); e != nil {
+		return ErrorStoreSave.Error(e)
+	}
+
+	return nil
+}
+
+func (o *oa) AuthCodeURL(state string, online bool) (string, PKCE) {
+	var (
+		c = o.Config()
+		p PKCE
+	)
+
+	if c.PKCE {
+		p = NewPKCE()
+	}
+
+	return ConfigGetAuthCodeUrlPKCE(c.OAuth2(), state, online, p), p
+}
+
+func (o *oa) Exchange(ctx context.Context, key, code, verifier string) (*oauth2.Token, liberr.Error) {
+	var p = PKCE{Verifier: verifier}
+
+	if tok, err := ConfigExchangeToken(o.Config().OAuth2(), ctx, o.getClient(), code, p.ExchangeOptions()...); err != nil {
+		return nil, err
+	} else if err = o.save(key, tok); err != nil {
+		return nil, err
+	} else {
+		return tok, nil
+	}
+}
+
+func (o *oa) ClientCredentials() oauth2.TokenSource {
+	o.m.RLock()
+	if t := o.t; t != nil {
+		o.m.RUnlock()
+		return t
+	}
+	o.m.RUnlock()
+
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if o.t != nil {
+		return o.t
+	}
+
+	var (
+		k = KeyClientCredentials + o.c.ClientID
+		t *oauth2.Token
+	)
+
+	if o.s != nil {
+		t, _ = o.s.Load(k)
+	}
+
+	o.t = newClientCredentialsSource(o.x, o.h, o.c.ClientCredentials(), t, o.c.ExpiryDelta.Time())
+
+	if o.s != nil {
+		o.t = NewTokenSourceStore(k, o.s, o.t)
+	}
+
+	return o.t
+}
+
+func (o *oa) DeviceAuth(ctx context.Context) (*oauth2.DeviceAuthResponse, liberr.Error) {
+	return ConfigDeviceAuth(o.Config().OAuth2(), ctx, o.getClient())
+}
+
+func (o *oa) DeviceToken(ctx context.Context, key string, da *oauth2.DeviceAuthResponse) (*oauth2.Token, liberr.Error) {
+	if tok, err := ConfigDeviceToken(o.Config().OAuth2(), ctx, o.getClient(), da); err != nil {
+		return nil, err
+	} else if err = o.save(key, tok); err != nil {
+		return nil, err
+	} else {
+		return tok, nil
+	}
+}
+
+func (o *oa) TokenSource(key string) (oauth2.TokenSource, liberr.Error) {
+	var (
+		s = o.Store()
+		x = o.x
+	)
+
+	if s == nil || len(key) < 1 {
+		return nil, ErrorEmptyParams.Error(nil)
+	}
+
+	tok, err := s.Load(key)
+
+	if err != nil {
+		if e, k := err.(liberr.Error); k && e.IsCode(ErrorStoreNotFound) {
+			return nil, e
+		}
+
+		return nil, ErrorStoreLoad.Error(err)
+	}
+
+	if h := o.getClient(); h != nil {
+		x = context.WithValue(x, oauth2.HTTPClient, h)
+	}
+
+	return NewTokenSourceStore(key, s, o.Config().OAuth2().TokenSource(x, tok)), nil
+}
+
+func (o *oa) Client(key string) (*http.Client, liberr.Error) {
+	if t, e := o.TokenSource(key); e != nil {
+		return nil, e
+	} else {
+		return NewClientFromTokenSource(o.x, o.getClient(), t), nil
+	}
+}
+
+func (o *oa) Introspect(ctx context.Context, token, hint string) (*Introspection, liberr.Error) {
+	var c = o.Config()
+	return Introspect(ctx, o.getClient(), c.IntrospectURL, c.ClientID, c.ClientSecret, token, hint)
+}
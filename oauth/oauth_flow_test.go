@@ -0,0 +1,194 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2020 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package oauth_test
+
+import (
+	"context"
+	"time"
+
+	libdur "github.com/nabbar/golib/duration"
+	liboau "github.com/nabbar/golib/oauth"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OAuth Client Credentials", func() {
+	var srv *testServer
+
+	BeforeEach(func() {
+		srv = newServer()
+		DeferCleanup(srv.Close)
+	})
+
+	It("Must keep the token in cache until its expiration", func() {
+		o, e := liboau.New(context.Background(), srv.Config(liboau.StoreMemory))
+		Expect(e).ToNot(HaveOccurred())
+
+		src := o.ClientCredentials()
+		Expect(o.ClientCredentials()).To(BeIdenticalTo(src))
+
+		for i := 0; i < 3; i++ {
+			tok, err := src.Token()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tok.AccessToken).To(Equal("cc-1"))
+		}
+
+		Expect(srv.ClientCredentialsCount()).To(Equal(1))
+	})
+
+	It("Must request a new token within the expiry delta", func() {
+		cfg := srv.Config(liboau.StoreNone)
+		cfg.ExpiryDelta = libdur.ParseDuration(time.Minute)
+		srv.SetClientCredentialsExpires(30)
+
+		o, e := liboau.New(context.Background(), cfg)
+		Expect(e).ToNot(HaveOccurred())
+
+		tok, err := o.ClientCredentials().Token()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tok.AccessToken).To(Equal("cc-1"))
+
+		tok, err = o.ClientCredentials().Token()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tok.AccessToken).To(Equal("cc-2"))
+		Expect(srv.ClientCredentialsCount()).To(Equal(2))
+	})
+
+	It("Must reuse the token saved into the store", func() {
+		o, e := liboau.New(context.Background(), srv.Config(liboau.StoreMemory))
+		Expect(e).ToNot(HaveOccurred())
+
+		_, err := o.ClientCredentials().Token()
+		Expect(err).ToNot(HaveOccurred())
+
+		tok, err := o.Store().Load(liboau.KeyClientCredentials + testClientID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tok.AccessToken).To(Equal("cc-1"))
+
+		n, e := liboau.New(context.Background(), srv.Config(liboau.StoreNone))
+		Expect(e).ToNot(HaveOccurred())
+		n.SetStore(o.Store())
+
+		tok, err = n.ClientCredentials().Token()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tok.AccessToken).To(Equal("cc-1"))
+		Expect(srv.ClientCredentialsCount()).To(Equal(1))
+	})
+
+	It("Must fail with invalid credentials", func() {
+		cfg := srv.Config(liboau.StoreNone)
+		cfg.ClientSecret = "wrong"
+
+		o, e := liboau.New(context.Background(), cfg)
+		Expect(e).ToNot(HaveOccurred())
+
+		_, err := o.ClientCredentials().Token()
+		Expect(err).To(HaveOccurred())
+		Expect(srv.ClientCredentialsCount()).To(Equal(0))
+	})
+})
+
+var _ = Describe("OAuth Device Code", func() {
+	var (
+		srv *testServer
+		cli liboau.OAuth
+	)
+
+	BeforeEach(func() {
+		var e error
+
+		srv = newServer()
+		DeferCleanup(srv.Close)
+
+		cli, e = liboau.New(context.Background(), srv.Config(liboau.StoreMemory))
+		Expect(e).ToNot(HaveOccurred())
+	})
+
+	It("Must poll until the authorization is granted", func() {
+		srv.SetDevice(1, false)
+
+		da, e := cli.DeviceAuth(context.Background())
+		Expect(e).ToNot(HaveOccurred())
+		Expect(da.DeviceCode).To(Equal(testDeviceCode))
+		Expect(da.UserCode).To(Equal("ABCD-EFGH"))
+		Expect(da.Interval).To(BeEquivalentTo(1))
+
+		tok, e := cli.DeviceToken(context.Background(), "device", da)
+		Expect(e).ToNot(HaveOccurred())
+		Expect(tok.AccessToken).To(Equal("access-1"))
+		Expect(srv.Polls()).To(Equal(2))
+
+		s, err := cli.Store().Load("device")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(s.RefreshToken).To(Equal("refresh-1"))
+	})
+
+	It("Must stop polling when the authorization is denied", func() {
+		srv.SetDevice(0, true)
+
+		da, e := cli.DeviceAuth(context.Background())
+		Expect(e).ToNot(HaveOccurred())
+
+		_, e = cli.DeviceToken(context.Background(), "device", da)
+		Expect(e).To(HaveOccurred())
+		Expect(e.IsCode(liboau.ErrorDeviceToken)).To(BeTrue())
+		Expect(srv.Polls()).To(Equal(1))
+
+		_, err := cli.Store().Load("device")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Must stop polling when the context is done", func() {
+		srv.SetDevice(100, false)
+
+		da, e := cli.DeviceAuth(context.Background())
+		Expect(e).ToNot(HaveOccurred())
+
+		ctx, cnl := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cnl()
+
+		_, e = cli.DeviceToken(ctx, "device", da)
+		Expect(e).To(HaveOccurred())
+		Expect(e.IsCode(liboau.ErrorDeviceToken)).To(BeTrue())
+		Expect(srv.Polls()).To(Equal(0))
+	})
+
+	It("Must refuse empty parameters", func() {
+		_, e := cli.DeviceToken(context.Background(), "device", nil)
+		Expect(e).To(HaveOccurred())
+		Expect(e.IsCode(liboau.ErrorEmptyParams)).To(BeTrue())
+
+		cfg := srv.Config(liboau.StoreMemory)
+		cfg.DeviceAuthURL = ""
+
+		o, e := liboau.New(context.Background(), cfg)
+		Expect(e).ToNot(HaveOccurred())
+
+		_, e = o.DeviceAuth(context.Background())
+		Expect(e).To(HaveOccurred())
+		Expect(e.IsCode(liboau.ErrorEmptyParams)).To(BeTrue())
+	})
+})
@@ -0,0 +1,110 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2020 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package oauth_test
+
+import (
+	"context"
+	"time"
+
+	liboau "github.com/nabbar/golib/oauth"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OAuth Introspection", func() {
+	var (
+		srv *testServer
+		cli liboau.OAuth
+	)
+
+	BeforeEach(func() {
+		var e error
+
+		srv = newServer()
+		DeferCleanup(srv.Close)
+
+		cli, e = liboau.New(context.Background(), srv.Config(liboau.StoreNone))
+		Expect(e).ToNot(HaveOccurred())
+	})
+
+	It("Must return the state of an active token", func() {
+		res, e := cli.Introspect(context.Background(), testActiveToken, liboau.TokenHintAccess)
+		Expect(e).ToNot(HaveOccurred())
+		Expect(res.Active).To(BeTrue())
+		Expect(res.IsValid()).To(BeTrue())
+		Expect(res.ClientID).To(Equal(testClientID))
+		Expect(res.Username).To(Equal("bob"))
+		Expect(res.Subject).To(Equal("user-1"))
+		Expect(res.TokenType).To(Equal(liboau.TokenHintAccess))
+		Expect(res.Audience).To(Equal([]string{"api"}))
+		Expect(res.Expiry()).To(Equal(time.Unix(4102444800, 0)))
+		Expect(res.Extra).To(Equal(map[string]interface{}{"tenant": "acme"}))
+
+		Expect(res.Scopes()).To(Equal([]string{"read", "write"}))
+		Expect(res.HasScope("read")).To(BeTrue())
+		Expect(res.HasScope("read", "write")).To(BeTrue())
+		Expect(res.HasScope("read", "admin")).To(BeFalse())
+	})
+
+	It("Must detect an expired token", func() {
+		res, e := cli.Introspect(context.Background(), "expired-token", "")
+		Expect(e).ToNot(HaveOccurred())
+		Expect(res.Active).To(BeTrue())
+		Expect(res.IsValid()).To(BeFalse())
+		Expect(res.Audience).To(Equal([]string{"api", "web"}))
+		Expect(res.Extra).To(BeNil())
+	})
+
+	It("Must return an inactive token", func() {
+		res, e := cli.Introspect(context.Background(), "revoked-token", liboau.TokenHintRefresh)
+		Expect(e).ToNot(HaveOccurred())
+		Expect(res.Active).To(BeFalse())
+		Expect(res.IsValid()).To(BeFalse())
+		Expect(res.Expiry().IsZero()).To(BeTrue())
+	})
+
+	It("Must fail on an error status", func() {
+		cfg := srv.Config(liboau.StoreNone)
+		cfg.ClientSecret = "wrong"
+
+		o, e := liboau.New(context.Background(), cfg)
+		Expect(e).ToNot(HaveOccurred())
+
+		_, e = o.Introspect(context.Background(), testActiveToken, "")
+		Expect(e).To(HaveOccurred())
+		Expect(e.IsCode(liboau.ErrorIntrospectStatus)).To(BeTrue())
+	})
+
+	It("Must refuse empty parameters", func() {
+		_, e := cli.Introspect(context.Background(), "", "")
+		Expect(e).To(HaveOccurred())
+		Expect(e.IsCode(liboau.ErrorEmptyParams)).To(BeTrue())
+
+		_, e = liboau.Introspect(context.Background(), nil, "", testClientID, testClientSecret, testActiveToken, "")
+		Expect(e).To(HaveOccurred())
+		Expect(e.IsCode(liboau.ErrorEmptyParams)).To(BeTrue())
+	})
+})
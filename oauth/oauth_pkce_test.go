@@ -0,0 +1,163 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2020 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package oauth_test
+
+import (
+	"context"
+	"net/url"
+
+	liboau "github.com/nabbar/golib/oauth"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/oauth2"
+)
+
+var _ = Describe("OAuth PKCE", func() {
+	Context("Generate and verify a code challenge", func() {
+		It("Must generate a verifier with its S256 challenge", func() {
+			p := liboau.NewPKCE()
+			Expect(p.IsEmpty()).To(BeFalse())
+			Expect(p.Method).To(Equal(liboau.PKCEMethodS256))
+			Expect(len(p.Verifier)).To(BeNumerically(">=", 43))
+
+			c, e := liboau.PKCEChallenge(p.Verifier, liboau.PKCEMethodS256)
+			Expect(e).ToNot(HaveOccurred())
+			Expect(c).To(Equal(p.Challenge))
+			Expect(liboau.PKCEVerify(p.Verifier, p.Challenge, p.Method)).ToNot(HaveOccurred())
+			Expect(liboau.NewPKCE().Verifier).ToNot(Equal(p.Verifier))
+		})
+
+		It("Must compute the same S256 challenge as the oauth2 package", func() {
+			for i := 0; i < 10; i++ {
+				v := oauth2.GenerateVerifier()
+				c, e := liboau.PKCEChallenge(v, "")
+				Expect(e).ToNot(HaveOccurred())
+				Expect(c).To(Equal(oauth2.S256ChallengeFromVerifier(v)))
+				Expect(liboau.PKCEVerify(v, c, liboau.PKCEMethodS256)).ToNot(HaveOccurred())
+			}
+		})
+
+		It("Must verify the plain method", func() {
+			p := liboau.NewPKCE()
+			Expect(liboau.PKCEVerify(p.Verifier, p.Verifier, liboau.PKCEMethodPlain)).ToNot(HaveOccurred())
+			Expect(liboau.PKCEVerify(p.Verifier, p.Challenge, liboau.PKCEMethodPlain)).To(HaveOccurred())
+		})
+
+		It("Must refuse an invalid verifier", func() {
+			p := liboau.NewPKCE()
+
+			e := liboau.PKCEVerify(liboau.NewPKCE().Verifier, p.Challenge, p.Method)
+			Expect(e).To(HaveOccurred())
+			Expect(e.IsCode(liboau.ErrorPKCEVerifier)).To(BeTrue())
+
+			e = liboau.PKCEVerify("too-short", p.Challenge, p.Method)
+			Expect(e).To(HaveOccurred())
+			Expect(e.IsCode(liboau.ErrorPKCEVerifier)).To(BeTrue())
+
+			e = liboau.PKCEVerify(p.Verifier[:42]+"+", p.Challenge, p.Method)
+			Expect(e).To(HaveOccurred())
+			Expect(e.IsCode(liboau.ErrorPKCEVerifier)).To(BeTrue())
+
+			e = liboau.PKCEVerify(p.Verifier, p.Challenge, "S512")
+			Expect(e).To(HaveOccurred())
+			Expect(e.IsCode(liboau.ErrorParamInvalid)).To(BeTrue())
+		})
+	})
+
+	Context("Authorization code grant", func() {
+		var srv *testServer
+
+		BeforeEach(func() {
+			srv = newServer()
+			DeferCleanup(srv.Close)
+		})
+
+		It("Must add the code challenge to the authorization url", func() {
+			cfg := srv.Config(liboau.StoreMemory)
+			cfg.PKCE = true
+
+			o, e := liboau.New(context.Background(), cfg)
+			Expect(e).ToNot(HaveOccurred())
+
+			u, p := o.AuthCodeURL("state-1", false)
+			Expect(p.IsEmpty()).To(BeFalse())
+
+			r, err := url.Parse(u)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Query().Get("client_id")).To(Equal(testClientID))
+			Expect(r.Query().Get("state")).To(Equal("state-1"))
+			Expect(r.Query().Get("access_type")).To(Equal("offline"))
+			Expect(r.Query().Get("code_challenge")).To(Equal(p.Challenge))
+			Expect(r.Query().Get("code_challenge_method")).To(Equal(liboau.PKCEMethodS256))
+		})
+
+		It("Must not add a code challenge without PKCE", func() {
+			o, e := liboau.New(context.Background(), srv.Config(liboau.StoreMemory))
+			Expect(e).ToNot(HaveOccurred())
+
+			u, p := o.AuthCodeURL("state-1", true)
+			Expect(p.IsEmpty()).To(BeTrue())
+
+			r, err := url.Parse(u)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Query().Get("access_type")).To(Equal("online"))
+			Expect(r.Query().Has("code_challenge")).To(BeFalse())
+		})
+
+		It("Must exchange the code with the verifier and save the token", func() {
+			cfg := srv.Config(liboau.StoreMemory)
+			cfg.PKCE = true
+
+			o, e := liboau.New(context.Background(), cfg)
+			Expect(e).ToNot(HaveOccurred())
+
+			_, p := o.AuthCodeURL("state-1", false)
+			srv.SetChallenge(p.Challenge, p.Method)
+
+			_, e = o.Exchange(context.Background(), "user", testAuthCode, liboau.NewPKCE().Verifier)
+			Expect(e).To(HaveOccurred())
+			Expect(e.IsCode(liboau.ErrorOAuthExchange)).To(BeTrue())
+
+			tok, e := o.Exchange(context.Background(), "user", testAuthCode, p.Verifier)
+			Expect(e).ToNot(HaveOccurred())
+			Expect(tok.AccessToken).To(Equal("access-1"))
+			Expect(tok.RefreshToken).To(Equal("refresh-1"))
+
+			s, err := o.Store().Load("user")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(s.AccessToken).To(Equal("access-1"))
+		})
+
+		It("Must refuse an empty code", func() {
+			o, e := liboau.New(context.Background(), srv.Config(liboau.StoreMemory))
+			Expect(e).ToNot(HaveOccurred())
+
+			_, e = o.Exchange(context.Background(), "user", "", "")
+			Expect(e).To(HaveOccurred())
+			Expect(e.IsCode(liboau.ErrorEmptyParams)).To(BeTrue())
+		})
+	})
+})
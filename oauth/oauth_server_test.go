@@ -0,0 +1,269 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2020 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package oauth_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	liboau "github.com/nabbar/golib/oauth"
+)
+
+const (
+	testClientID     = "my-client"
+	testClientSecret = "my-secret"
+	testAuthCode     = "code-ok"
+	testDeviceCode   = "device-ok"
+	testActiveToken  = "active-token"
+)
+
+// testServer is a minimal authorization server for the token, device and introspection endpoints,
+// with an api endpoint returning the authorization header received.
+type testServer struct {
+	m sync.Mutex
+	s *httptest.Server
+
+	challenge string // code challenge received with the authorization request
+	method    string // code challenge method
+	refresh   string // current refresh token, rotated on each use
+	rotate    int    // number of rotated tokens
+	ccCount   int    // client credentials tokens issued
+	ccExpires int    // lifetime of the client credentials tokens in seconds
+	polls     int    // device token requests
+	pending   int    // device token requests answered with authorization pending
+	denied    bool   // device authorization denied by the user
+}
+
+func newServer() *testServer {
+	var (
+		o = &testServer{ccExpires: 3600}
+		m = http.NewServeMux()
+	)
+
+	m.HandleFunc("/token", o.token)
+	m.HandleFunc("/device", o.device)
+	m.HandleFunc("/introspect", o.introspect)
+	m.HandleFunc("/api", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+	})
+
+	o.s = httptest.NewServer(m)
+	return o
+}
+
+func (o *testServer) Close() {
+	o.s.Close()
+}
+
+// Config return a config of the server with the header auth style and the given store type.
+func (o *testServer) Config(store string) liboau.Config {
+	return liboau.Config{
+		ClientID:      testClientID,
+		ClientSecret:  testClientSecret,
+		AuthURL:       o.s.URL + "/authorize",
+		TokenURL:      o.s.URL + "/token",
+		DeviceAuthURL: o.s.URL + "/device",
+		IntrospectURL: o.s.URL + "/introspect",
+		RedirectURL:   "https://app.example.com/callback",
+		Scopes:        []string{"read", "write"},
+		AuthStyle:     liboau.AuthStyleHeader,
+		Store:         liboau.ConfigStore{Type: store},
+	}
+}
+
+// SetChallenge register the code challenge of an authorization request.
+func (o *testServer) SetChallenge(challenge, method string) {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	o.challenge = challenge
+	o.method = method
+}
+
+func (o *testServer) SetClientCredentialsExpires(sec int) {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	o.ccExpires = sec
+}
+
+func (o *testServer) SetDevice(pending int, denied bool) {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	o.pending = pending
+	o.denied = denied
+}
+
+func (o *testServer) ClientCredentialsCount() int {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	return o.ccCount
+}
+
+func (o *testServer) Polls() int {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	return o.polls
+}
+
+func (o *testServer) Refresh() string {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	return o.refresh
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, err string) {
+	writeJSON(w, code, map[string]string{"error": err})
+}
+
+// newToken issue a new access token with a rotated refresh token, the lock must be held.
+func (o *testServer) newToken(w http.ResponseWriter) {
+	o.rotate++
+	o.refresh = fmt.Sprintf("refresh-%d", o.rotate)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token":  fmt.Sprintf("access-%d", o.rotate),
+		"refresh_token": o.refresh,
+		"token_type":    "Bearer",
+		"expires_in":    3600,
+	})
+}
+
+func (o *testServer) token(w http.ResponseWriter, r *http.Request) {
+	if u, p, k := r.BasicAuth(); !k || u != testClientID || p != testClientSecret {
+		writeError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	} else if e := r.ParseForm(); e != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		if r.PostForm.Get("code") != testAuthCode {
+			writeError(w, http.StatusBadRequest, "invalid_grant")
+		} else if len(o.challenge) > 0 && liboau.PKCEVerify(r.PostForm.Get("code_verifier"), o.challenge, o.method) != nil {
+			writeError(w, http.StatusBadRequest, "invalid_grant")
+		} else {
+			o.newToken(w)
+		}
+
+	case "refresh_token":
+		if len(o.refresh) < 1 || r.PostForm.Get("refresh_token") != o.refresh {
+			writeError(w, http.StatusBadRequest, "invalid_grant")
+		} else {
+			o.newToken(w)
+		}
+
+	case "client_credentials":
+		o.ccCount++
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"access_token": fmt.Sprintf("cc-%d", o.ccCount),
+			"token_type":   "Bearer",
+			"expires_in":   o.ccExpires,
+		})
+
+	case "urn:ietf:params:oauth:grant-type:device_code":
+		o.polls++
+
+		if r.PostForm.Get("device_code") != testDeviceCode {
+			writeError(w, http.StatusBadRequest, "invalid_grant")
+		} else if o.denied {
+			writeError(w, http.StatusBadRequest, "access_denied")
+		} else if o.polls <= o.pending {
+			writeError(w, http.StatusBadRequest, "authorization_pending")
+		} else {
+			o.newToken(w)
+		}
+
+	default:
+		writeError(w, http.StatusBadRequest, "unsupported_grant_type")
+	}
+}
+
+func (o *testServer) device(w http.ResponseWriter, r *http.Request) {
+	if e := r.ParseForm(); e != nil || r.PostForm.Get("client_id") != testClientID {
+		writeError(w, http.StatusBadRequest, "invalid_client")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"device_code":      testDeviceCode,
+		"user_code":        "ABCD-EFGH",
+		"verification_uri": o.s.URL + "/activate",
+		"expires_in":       60,
+		"interval":         1,
+	})
+}
+
+func (o *testServer) introspect(w http.ResponseWriter, r *http.Request) {
+	if u, p, k := r.BasicAuth(); !k || u != testClientID || p != testClientSecret {
+		writeError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	} else if e := r.ParseForm(); e != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	switch r.PostForm.Get("token") {
+	case testActiveToken:
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"active":     true,
+			"scope":      "read write",
+			"client_id":  testClientID,
+			"username":   "bob",
+			"token_type": r.PostForm.Get("token_type_hint"),
+			"exp":        4102444800,
+			"sub":        "user-1",
+			"aud":        "api",
+			"tenant":     "acme",
+		})
+	case "expired-token":
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"active": true,
+			"exp":    946684800,
+			"aud":    []string{"api", "web"},
+		})
+	default:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"active": false})
+	}
+}
@@ -0,0 +1,304 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2020 Nicolas JUHEL
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ *
+ */
+
+package oauth_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	libkvd "github.com/nabbar/golib/database/kvdriver"
+	libkvf "github.com/nabbar/golib/database/kvfile"
+	kvtps "github.com/nabbar/golib/database/kvtypes"
+	liberr "github.com/nabbar/golib/errors"
+	liboau "github.com/nabbar/golib/oauth"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/oauth2"
+)
+
+var _ = Describe("OAuth Token Store", func() {
+	Context("Memory store", func() {
+		It("Must keep a copy of the tokens", func() {
+			s := liboau.NewStoreMemory()
+			t := &oauth2.Token{AccessToken: "a1", RefreshToken: "r1"}
+
+			Expect(s.Save("k", t)).ToNot(HaveOccurred())
+			t.AccessToken = "changed"
+
+			l, e := s.Load("k")
+			Expect(e).ToNot(HaveOccurred())
+			Expect(l.AccessToken).To(Equal("a1"))
+
+			Expect(s.Delete("k")).ToNot(HaveOccurred())
+			_, e = s.Load("k")
+			Expect(liberr.IsCode(e, liboau.ErrorStoreNotFound)).To(BeTrue())
+			Expect(liberr.IsCode(s.Save("k", nil), liboau.ErrorParamInvalid)).To(BeTrue())
+		})
+	})
+
+	Context("File store", func() {
+		var path string
+
+		BeforeEach(func() {
+			path = filepath.Join(GinkgoT().TempDir(), "store", "tokens.json")
+		})
+
+		It("Must write the tokens atomically with a restricted permission", func() {
+			s := liboau.NewStoreFile(path)
+
+			_, e := s.Load("k1")
+			Expect(liberr.IsCode(e, liboau.ErrorStoreNotFound)).To(BeTrue())
+
+			Expect(s.Save("k1", &oauth2.Token{AccessToken: "a1", RefreshToken: "r1"})).ToNot(HaveOccurred())
+
+			i1, err := os.Stat(path)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(i1.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+			Expect(s.Save("k2", &oauth2.Token{AccessToken: "a2"})).ToNot(HaveOccurred())
+
+			i2, err := os.Stat(path)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(i2.Mode().Perm()).To(Equal(os.FileMode(0600)))
+			Expect(os.SameFile(i1, i2)).To(BeFalse(), "the file must be replaced and not rewritten in place")
+
+			l, err := os.ReadDir(filepath.Dir(path))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(l).To(HaveLen(1), "no temporary file must be left")
+
+			var m map[string]*oauth2.Token
+			p, err := os.ReadFile(path)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(json.Unmarshal(p, &m)).To(Succeed())
+			Expect(m).To(HaveKey("k1"))
+			Expect(m).To(HaveKey("k2"))
+		})
+
+		It("Must load the tokens saved by another instance", func() {
+			Expect(liboau.NewStoreFile(path).Save("k1", &oauth2.Token{AccessToken: "a1", RefreshToken: "r1"})).ToNot(HaveOccurred())
+
+			s := liboau.NewStoreFile(path)
+			t, e := s.Load("k1")
+			Expect(e).ToNot(HaveOccurred())
+			Expect(t.RefreshToken).To(Equal("r1"))
+
+			Expect(s.Delete("k1")).ToNot(HaveOccurred())
+			Expect(s.Delete("k1")).ToNot(HaveOccurred())
+
+			_, e = liboau.NewStoreFile(path).Load("k1")
+			Expect(liberr.IsCode(e, liboau.ErrorStoreNotFound)).To(BeTrue())
+		})
+
+		It("Must fail on a corrupted file", func() {
+			Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+			Expect(os.WriteFile(path, []byte("{not json"), 0600)).To(Succeed())
+
+			s := liboau.NewStoreFile(path)
+
+			_, e := s.Load("k1")
+			Expect(liberr.IsCode(e, liboau.ErrorStoreLoad)).To(BeTrue())
+			Expect(s.Save("k1", &oauth2.Token{AccessToken: "a1"})).To(HaveOccurred())
+
+			p, err := os.ReadFile(path)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(p)).To(Equal("{not json"))
+		})
+	})
+
+	Context("KV store", func() {
+		It("Must map the not found error of the kvfile driver", func() {
+			d, err := libkvf.New[string, oauth2.Token](filepath.Join(GinkgoT().TempDir(), "tokens.kv"))
+			Expect(err).ToNot(HaveOccurred())
+			DeferCleanup(d.Close)
+
+			s := liboau.NewStoreKV(d)
+
+			_, e := s.Load("k1")
+			Expect(liberr.IsCode(e, liboau.ErrorStoreNotFound)).To(BeTrue())
+
+			Expect(s.Save("k1", &oauth2.Token{AccessToken: "a1", RefreshToken: "r1"})).ToNot(HaveOccurred())
+
+			t, e := s.Load("k1")
+			Expect(e).ToNot(HaveOccurred())
+			Expect(t.RefreshToken).To(Equal("r1"))
+
+			Expect(s.Delete("k1")).ToNot(HaveOccurred())
+
+			_, e = s.Load("k1")
+			Expect(liberr.IsCode(e, liboau.ErrorStoreNotFound)).To(BeTrue())
+		})
+
+		It("Must map a not found error of a driver", func() {
+			var fail = errors.New("connection lost")
+
+			get := func(err error) liboau.TokenStore {
+				return liboau.NewStoreKV(libkvd.New[string, oauth2.Token](nil, func(key string) (oauth2.Token, error) {
+					return oauth2.Token{}, err
+				}, nil, nil, nil, nil))
+			}
+
+			_, e := get(os.ErrNotExist).Load("k1")
+			Expect(liberr.IsCode(e, liboau.ErrorStoreNotFound)).To(BeTrue())
+
+			_, e = get(kvtps.ErrKeyNotFound).Load("k1")
+			Expect(liberr.IsCode(e, liboau.ErrorStoreNotFound)).To(BeTrue())
+
+			_, e = get(fail).Load("k1")
+			Expect(liberr.IsCode(e, liboau.ErrorStoreLoad)).To(BeTrue())
+
+			_, e = get(nil).Load("k1")
+			Expect(liberr.IsCode(e, liboau.ErrorStoreNotFound)).To(BeTrue())
+		})
+	})
+
+	Context("Persist the rotated refresh tokens", func() {
+		var srv *testServer
+
+		// expire mark the token stored with the given key as expired.
+		expire := func(s liboau.TokenStore, key string) {
+			t, e := s.Load(key)
+			Expect(e).ToNot(HaveOccurred())
+
+			t.Expiry = time.Now().Add(-time.Hour)
+			Expect(s.Save(key, t)).ToNot(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			srv = newServer()
+			DeferCleanup(srv.Close)
+		})
+
+		It("Must save each rotated refresh token into the store", func() {
+			o, e := liboau.New(context.Background(), srv.Config(liboau.StoreMemory))
+			Expect(e).ToNot(HaveOccurred())
+
+			_, e = o.Exchange(context.Background(), "user", testAuthCode, "")
+			Expect(e).ToNot(HaveOccurred())
+
+			for i, k := range []string{"2", "3"} {
+				expire(o.Store(), "user")
+
+				src, e := o.TokenSource("user")
+				Expect(e).ToNot(HaveOccurred())
+
+				tok, err := src.Token()
+				Expect(err).ToNot(HaveOccurred(), "refresh %d", i)
+				Expect(tok.AccessToken).To(Equal("access-" + k))
+
+				s, err := o.Store().Load("user")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(s.RefreshToken).To(Equal("refresh-" + k))
+				Expect(s.RefreshToken).To(Equal(srv.Refresh()))
+			}
+		})
+
+		It("Must reuse the rotated refresh token after a restart with the file store", func() {
+			cfg := srv.Config(liboau.StoreFile)
+			cfg.Store.Path = filepath.Join(GinkgoT().TempDir(), "tokens.json")
+
+			o, e := liboau.New(context.Background(), cfg)
+			Expect(e).ToNot(HaveOccurred())
+
+			_, e = o.Exchange(context.Background(), "user", testAuthCode, "")
+			Expect(e).ToNot(HaveOccurred())
+			expire(o.Store(), "user")
+
+			cli, e := o.Client("user")
+			Expect(e).ToNot(HaveOccurred())
+
+			rsp, err := cli.Get(srv.s.URL + "/api")
+			Expect(err).ToNot(HaveOccurred())
+			p, _ := io.ReadAll(rsp.Body)
+			_ = rsp.Body.Close()
+			Expect(rsp.StatusCode).To(Equal(http.StatusOK))
+			Expect(string(p)).To(Equal("Bearer access-2"))
+
+			// restart with a new instance on the same file
+			n, e := liboau.New(context.Background(), cfg)
+			Expect(e).ToNot(HaveOccurred())
+			expire(n.Store(), "user")
+
+			src, e := n.TokenSource("user")
+			Expect(e).ToNot(HaveOccurred())
+
+			tok, err := src.Token()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tok.AccessToken).To(Equal("access-3"))
+
+			s, err := liboau.NewStoreFile(cfg.Store.Path).Load("user")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(s.RefreshToken).To(Equal("refresh-3"))
+		})
+
+		It("Must return the error of the store on save", func() {
+			var fail = errors.New("read only")
+
+			o, e := liboau.New(context.Background(), srv.Config(liboau.StoreMemory))
+			Expect(e).ToNot(HaveOccurred())
+
+			tok, e := o.Exchange(context.Background(), "", testAuthCode, "")
+			Expect(e).ToNot(HaveOccurred())
+			tok.Expiry = time.Now().Add(-time.Hour)
+
+			s := liboau.NewStoreKV(libkvd.New[string, oauth2.Token](nil, func(key string) (oauth2.Token, error) {
+				return *tok, nil
+			}, func(key string, model oauth2.Token) error {
+				return fail
+			}, nil, nil, nil))
+
+			o.SetStore(s)
+
+			src, e := o.TokenSource("user")
+			Expect(e).ToNot(HaveOccurred())
+
+			_, err := src.Token()
+			Expect(err).To(HaveOccurred())
+			Expect(liberr.IsCode(err, liboau.ErrorStoreSave)).To(BeTrue())
+		})
+
+		It("Must fail on a token not stored", func() {
+			o, e := liboau.New(context.Background(), srv.Config(liboau.StoreMemory))
+			Expect(e).ToNot(HaveOccurred())
+
+			_, e = o.TokenSource("unknown")
+			Expect(e).To(HaveOccurred())
+			Expect(e.IsCode(liboau.ErrorStoreNotFound)).To(BeTrue())
+
+			n, e := liboau.New(context.Background(), srv.Config(liboau.StoreNone))
+			Expect(e).ToNot(HaveOccurred())
+
+			_, e = n.Client("user")
+			Expect(e).To(HaveOccurred())
+			Expect(e.IsCode(liboau.ErrorEmptyParams)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,173 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	liberr "github.com/nabbar/golib/errors"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// PKCEMethodS256 is the code challenge method using a SHA-256 hash of the verifier (RFC 7636).
+	PKCEMethodS256 = "S256"
+	// PKCEMethodPlain is the code challenge method using the verifier as challenge.
+	PKCEMethodPlain = "plain"
+
+	pkceVerifierMin = 43
+	pkceVerifierMax = 128
+)
+
+// PKCE hold the code verifier and the code challenge of a Proof Key for Code Exchange (RFC 7636).
+// The Verifier must be kept by the client between the authorization request and the code exchange.
+type PKCE struct {
+	Verifier  string `json:"verifier"`
+	Challenge string `json:"challenge"`
+	Method    string `json:"method"`
+}
+
+// NewPKCE generate a new random code verifier and its S256 code challenge.
+func NewPKCE() PKCE {
+	v := oauth2.GenerateVerifier()
+
+	return PKCE{
+		Verifier:  v,
+		Challenge: oauth2.S256ChallengeFromVerifier(v),
+		Method:    PKCEMethodS256,
+	}
+}
+
+// IsEmpty return true if no verifier is defined.
+func (p PKCE) IsEmpty() bool {
+	return len(p.Verifier) < 1
+}
+
+// AuthCodeOptions return the options to add the code challenge to the authorization url.
+func (p PKCE) AuthCodeOptions() []oauth2.AuthCodeOption {
+	if p.IsEmpty() {
+		return nil
+	}
+
+	return []oauth2.AuthCodeOption{
+		oauth2.SetAuthURLParam("code_challenge", p.Challenge),
+		oauth2.SetAuthURLParam("code_challenge_method", p.Method),
+	}
+}
+
+// ExchangeOptions return the options to add the code verifier to the code exchange request.
+func (p PKCE) ExchangeOptions() []oauth2.AuthCodeOption {
+	if p.IsEmpty() {
+		return nil
+	}
+
+	return []oauth2.AuthCodeOption{oauth2.VerifierOption(p.Verifier)}
+}
+
+// PKCEChallenge compute the code challenge of the verifier with the given method (S256 if empty).
+func PKCEChallenge(verifier, method string) (string, liberr.Error) {
+	switch method {
+	case "", PKCEMethodS256:
+		h := sha256.Sum256([]byte(verifier))
+		return base64.RawURLEncoding.EncodeToString(h[:]), nil
+	case PKCEMethodPlain:
+		return verifier, nil
+	default:
+		//nolint #goerr113
+		return "", ErrorParamInvalid.Error(fmt.Errorf("unsupported code challenge method '%s'", method))
+	}
+}
+
+// PKCEVerify check on the authorization server side that the code verifier
+// received with the code exchange match the code challenge received with the authorization request.
+func PKCEVerify(verifier, challenge, method string) liberr.Error {
+	if len(verifier) < pkceVerifierMin || len(verifier) > pkceVerifierMax {
+		//nolint #goerr113
+		return ErrorPKCEVerifier.Error(fmt.Errorf("code verifier length must be between %d and %d", pkceVerifierMin, pkceVerifierMax))
+	}
+
+	for _, r := range verifier {
+		if !isPKCEUnreserved(r) {
+			//nolint #goerr113
+			return ErrorPKCEVerifier.Error(fmt.Errorf("code verifier contains invalid character '%c'", r))
+		}
+	}
+
+	if c, e := PKCEChallenge(verifier, method); e != nil {
+		return e
+	} else if subtle.ConstantTimeCompare([]byte(c), []byte(challenge)) != 1 {
+		return ErrorPKCEVerifier.Error(nil)
+	}
+
+	return nil
+}
+
+func isPKCEUnreserved(r rune) bool {
+	switch {
+	case r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+		return true
+	case r == '-', r == '.', r == '_', r == '~':
+		return true
+	default:
+		return false
+	}
+}
+
+// ConfigGetAuthCodeUrlPKCE is like ConfigGetAuthCodeUrl but add the code challenge of the given PKCE.
+func ConfigGetAuthCodeUrlPKCE(oa *oauth2.Config, state string, online bool, pkce PKCE) string {
+	var opt = pkce.AuthCodeOptions()
+
+	if online {
+		opt = append(opt, oauth2.AccessTypeOnline)
+	} else {
+		opt = append(opt, oauth2.AccessTypeOffline)
+	}
+
+	return oa.AuthCodeURL(state, opt...)
+}
+
+// ConfigExchangeToken exchange the authorization code and return the token.
+// Use PKCE.ExchangeOptions as options to send the code verifier.
+func ConfigExchangeToken(oa *oauth2.Config, ctx context.Context, httpcli *http.Client, code string, opts ...oauth2.AuthCodeOption) (*oauth2.Token, liberr.Error) {
+	if oa == nil || len(code) < 1 {
+		return nil, ErrorEmptyParams.Error(nil)
+	}
+
+	if httpcli != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, httpcli)
+	}
+
+	if tok, err := oa.Exchange(ctx, code, opts...); err != nil {
+		return nil, ErrorOAuthExchange.Error(err)
+	} else {
+		return tok, nil
+	}
+}
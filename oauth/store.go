@@ -0,0 +1,302 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ *
+ */
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	kvtps "github.com/nabbar/golib/database/kvtypes"
+	"golang.org/x/oauth2"
+)
+
+// TokenStore is used to persist tokens (and so refresh tokens) identified by a key.
+// The Load func must return an error of code ErrorStoreNotFound if no token is stored for the key.
+// The file and KV stores persist only the fields of oauth2.Token, so the values given by
+// its Extra func (like the id_token of OpenID Connect) are not kept.
+type TokenStore interface {
+	Load(key string) (*oauth2.Token, error)
+	Save(key string, tok *oauth2.Token) error
+	Delete(key string) error
+}
+
+func copyToken(tok *oauth2.Token) *oauth2.Token {
+	if tok == nil {
+		return nil
+	}
+
+	var t = *tok
+	return &t
+}
+
+// NewStoreMemory return a token store keeping tokens into memory.
+func NewStoreMemory() TokenStore {
+	return &stMem{
+		m: sync.Map{},
+	}
+}
+
+type stMem struct {
+	m sync.Map
+}
+
+func (o *stMem) Load(key string) (*oauth2.Token, error) {
+	if i, l := o.m.Load(key); !l {
+		return nil, ErrorStoreNotFound.Error(nil)
+	} else if t, k := i.(*oauth2.Token); !k {
+		return nil, ErrorStoreNotFound.Error(nil)
+	} else {
+		return copyToken(t), nil
+	}
+}
+
+func (o *stMem) Save(key string, tok *oauth2.Token) error {
+	if tok == nil {
+		return ErrorParamInvalid.Error(nil)
+	}
+
+	o.m.Store(key, copyToken(tok))
+	return nil
+}
+
+func (o *stMem) Delete(key string) error {
+	o.m.Delete(key)
+	return nil
+}
+
+// NewStoreFile return a token store keeping all tokens into a json file.
+// The file is written with permission 0600 and replaced atomically on each change.
+// The extra values of the tokens are not written.
+func NewStoreFile(path string) TokenStore {
+	return &stFile{
+		m: sync.Mutex{},
+		p: filepath.Clean(path),
+	}
+}
+
+type stFile struct {
+	m sync.Mutex
+	p string
+}
+
+func (o *stFile) read() (map[string]*oauth2.Token, error) {
+	var res = make(map[string]*oauth2.Token)
+
+	if p, e := os.ReadFile(o.p); e != nil && errors.Is(e, os.ErrNotExist) {
+		return res, nil
+	} else if e != nil {
+		return nil, ErrorStoreLoad.Error(e)
+	} else if len(p) < 1 {
+		return res, nil
+	} else if e = json.Unmarshal(p, &res); e != nil {
+		return nil, ErrorStoreLoad.Error(e)
+	}
+
+	return res, nil
+}
+
+func (o *stFile) write(lst map[string]*oauth2.Token) error {
+	var (
+		e error
+		p []byte
+		f *os.File
+	)
+
+	if p, e = json.MarshalIndent(lst, "", "  "); e != nil {
+		return ErrorStoreSave.Error(e)
+	} else if e = os.MkdirAll(filepath.Dir(o.p), 0700); e != nil {
+		return ErrorStoreSave.Error(e)
+	} else if f, e = os.CreateTemp(filepath.Dir(o.p), filepath.Base(o.p)+".*"); e != nil {
+		return ErrorStoreSave.Error(e)
+	}
+
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+
+	if _, e = f.Write(p); e != nil {
+		_ = f.Close()
+		return ErrorStoreSave.Error(e)
+	} else if e = f.Close(); e != nil {
+		return ErrorStoreSave.Error(e)
+	} else if e = os.Chmod(f.Name(), 0600); e != nil {
+		return ErrorStoreSave.Error(e)
+	} else if e = os.Rename(f.Name(), o.p); e != nil {
+		return ErrorStoreSave.Error(e)
+	}
+
+	return nil
+}
+
+func (o *stFile) Load(key string) (*oauth2.Token, error) {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if l, e := o.read(); e != nil {
+		return nil, e
+	} else if t, k := l[key]; !k || t == nil {
+		return nil, ErrorStoreNotFound.Error(nil)
+	} else {
+		return t, nil
+	}
+}
+
+func (o *stFile) Save(key string, tok *oauth2.Token) error {
+	if tok == nil {
+		return ErrorParamInvalid.Error(nil)
+	}
+
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if l, e := o.read(); e != nil {
+		return e
+	} else {
+		l[key] = tok
+		return o.write(l)
+	}
+}
+
+func (o *stFile) Delete(key string) error {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if l, e := o.read(); e != nil {
+		return e
+	} else if _, k := l[key]; !k {
+		return nil
+	} else {
+		delete(l, key)
+		return o.write(l)
+	}
+}
+
+// NewStoreKV return a token store using the given KV driver (gorm, nutsdb, ...).
+// The not found error of the driver (see kvtypes.IsNotFound) is returned as an error of code ErrorStoreNotFound.
+// The extra values of the tokens are not stored, as the model of the driver is oauth2.Token.
+func NewStoreKV(drv kvtps.KVDriver[string, oauth2.Token]) TokenStore {
+	return &stKV{
+		d: drv,
+	}
+}
+
+type stKV struct {
+	d kvtps.KVDriver[string, oauth2.Token]
+}
+
+func (o *stKV) Load(key string) (*oauth2.Token, error) {
+	var tok oauth2.Token
+
+	if o.d == nil {
+		return nil, ErrorEmptyParams.Error(nil)
+	} else if e := o.d.Get(key, &tok); e != nil && kvtps.IsNotFound(e) {
+		return nil, ErrorStoreNotFound.Error(e)
+	} else if e != nil {
+		return nil, ErrorStoreLoad.Error(e)
+	} else if len(tok.AccessToken) < 1 && len(tok.RefreshToken) < 1 {
+		return nil, ErrorStoreNotFound.Error(nil)
+	}
+
+	return &tok, nil
+}
+
+func (o *stKV) Save(key string, tok *oauth2.Token) error {
+	if o.d == nil {
+		return ErrorEmptyParams.Error(nil)
+	} else if tok == nil {
+		return ErrorParamInvalid.Error(nil)
+	} else if e := o.d.Set(key, *tok); e != nil {
+		return ErrorStoreSave.Error(e)
+	}
+
+	return nil
+}
+
+func (o *stKV) Delete(key string) error {
+	if o.d == nil {
+		return ErrorEmptyParams.Error(nil)
+	} else if e := o.d.Del(key); e != nil {
+		return ErrorStoreSave.Error(e)
+	}
+
+	return nil
+}
+
+// NewTokenSourceStore return a token source saving into the store each new token
+// (refreshed or rotated) given by the source with the given key.
+// If the token cannot be saved, the error is returned instead of the token.
+func NewTokenSourceStore(key string, store TokenStore, src oauth2.TokenSource) oauth2.TokenSource {
+	var last *oauth2.Token
+
+	if store != nil {
+		last, _ = store.Load(key)
+	}
+
+	return &stSrc{
+		m: sync.Mutex{},
+		k: key,
+		s: store,
+		t: src,
+		l: last,
+	}
+}
+
+type stSrc struct {
+	m sync.Mutex
+	k string
+	s TokenStore
+	t oauth2.TokenSource
+	l *oauth2.Token
+}
+
+func (o *stSrc) Token() (*oauth2.Token, error) {
+	if o.t == nil {
+		return nil, ErrorEmptyParams.Error(nil)
+	}
+
+	tok, err := o.t.Token()
+
+	if err != nil {
+		return nil, err
+	} else if o.s == nil {
+		return tok, nil
+	}
+
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if o.l != nil && o.l.AccessToken == tok.AccessToken && o.l.RefreshToken == tok.RefreshToken {
+		return tok, nil
+	} else if err = o.s.Save(o.k, tok); err != nil {
+		return nil, err
+	}
+
+	o.l = copyToken(tok)
+	return tok, nil
+}
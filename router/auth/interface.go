@@ -39,6 +39,14 @@ type Authorization interface {
 }
 
 func NewAuthorization(log liblog.FuncLog, HeadAuthType string, authCheckFunc func(AuthHeader string) (rtrhdr.AuthCode, liberr.Error)) Authorization {
+	return NewAuthorizationContext(log, HeadAuthType, func(c *ginsdk.Context, AuthHeader string) (rtrhdr.AuthCode, liberr.Error) {
+		return authCheckFunc(AuthHeader)
+	})
+}
+
+// NewAuthorizationContext is like NewAuthorization but the check func receive also the gin context,
+// allowing it to store information about the authenticated client (user, claims, ...).
+func NewAuthorizationContext(log liblog.FuncLog, HeadAuthType string, authCheckFunc func(c *ginsdk.Context, AuthHeader string) (rtrhdr.AuthCode, liberr.Error)) Authorization {
 	return &authorization{
 		log:      log,
 		check:    authCheckFunc,
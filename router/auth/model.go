@@ -40,7 +40,7 @@ import (
 
 type authorization struct {
 	log      liblog.FuncLog
-	check    func(AuthHeader string) (rtrhdr.AuthCode, liberr.Error)
+	check    func(c *ginsdk.Context, AuthHeader string) (rtrhdr.AuthCode, liberr.Error)
 	router   []ginsdk.HandlerFunc
 	authType string
 }
@@ -82,7 +82,7 @@ func (a *authorization) Handler(c *ginsdk.Context) {
 		rtrhdr.AuthRequire(c, fmt.Errorf("%v", librtr.ErrorHeaderAuthEmpty.Error(nil).GetErrorSlice()))
 		return
 	} else {
-		code, err := a.check(authValue)
+		code, err := a.check(c, authValue)
 
 		switch code {
 		case rtrhdr.AuthCodeSuccess:
@@ -37,6 +37,7 @@ const (
 	AuthCodeSuccess = iota
 	AuthCodeRequire
 	AuthCodeForbidden
+	AuthCodeError
 )
 
 const (
@@ -0,0 +1,361 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package authjwt_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	ginsdk "github.com/gin-gonic/gin"
+	jwtsdk "github.com/golang-jwt/jwt/v5"
+	libdur "github.com/nabbar/golib/duration"
+	librtr "github.com/nabbar/golib/router"
+	rtrhdr "github.com/nabbar/golib/router/authheader"
+	rtrjwt "github.com/nabbar/golib/router/authjwt"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const testSecret = "a-shared-secret-long-enough-for-hmac"
+
+type jwkMap map[string]interface{}
+
+func b64(p []byte) string {
+	return base64.RawURLEncoding.EncodeToString(p)
+}
+
+func rsaJWK(kid string, k *rsa.PublicKey) jwkMap {
+	return jwkMap{"kty": "RSA", "kid": kid, "use": "sig", "alg": "RS256", "n": b64(k.N.Bytes()), "e": b64(big.NewInt(int64(k.E)).Bytes())}
+}
+
+func ecJWK(kid string, k *ecdsa.PublicKey) jwkMap {
+	return jwkMap{"kty": "EC", "kid": kid, "crv": "P-256", "x": b64(k.X.FillBytes(make([]byte, 32))), "y": b64(k.Y.FillBytes(make([]byte, 32)))}
+}
+
+func edJWK(kid string, k ed25519.PublicKey) jwkMap {
+	return jwkMap{"kty": "OKP", "kid": kid, "crv": "Ed25519", "x": b64(k)}
+}
+
+// jwksServer serve a JWKS and its discovery document, counting the JWKS requests.
+type jwksServer struct {
+	*httptest.Server
+
+	m    sync.Mutex
+	keys []jwkMap
+	hits atomic.Int32
+	fail atomic.Bool
+	slow time.Duration
+}
+
+func newJWKSServer(keys ...jwkMap) *jwksServer {
+	s := &jwksServer{keys: keys}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"jwks_uri": s.URL + "/jwks"})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		s.hits.Add(1)
+		time.Sleep(s.slow)
+
+		if s.fail.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		s.m.Lock()
+		defer s.m.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": s.keys})
+	})
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+func (s *jwksServer) setKeys(keys ...jwkMap) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.keys = keys
+}
+
+func sign(m jwtsdk.SigningMethod, kid string, key interface{}, clm jwtsdk.MapClaims) string {
+	t := jwtsdk.NewWithClaims(m, clm)
+
+	if len(kid) > 0 {
+		t.Header["kid"] = kid
+	}
+
+	s, e := t.SignedString(key)
+	Expect(e).ToNot(HaveOccurred())
+
+	return s
+}
+
+func claims(extra ...interface{}) jwtsdk.MapClaims {
+	c := jwtsdk.MapClaims{
+		"sub": "alice",
+		"iss": "https://issuer.example.com",
+		"aud": "api",
+		"iat": time.Now().Add(-time.Minute).Unix(),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+
+	for i := 0; i+1 < len(extra); i += 2 {
+		c[extra[i].(string)] = extra[i+1]
+	}
+
+	return c
+}
+
+func newValidator(cfg rtrjwt.Config) rtrjwt.Validator {
+	v, e := rtrjwt.New(cfg)
+	Expect(e).ToNot(HaveOccurred())
+	return v
+}
+
+func expectValid(v rtrjwt.Validator, tkn string) rtrjwt.Claims {
+	c, code, e := v.Validate(context.Background(), tkn)
+	Expect(e).ToNot(HaveOccurred())
+	Expect(code).To(BeEquivalentTo(rtrhdr.AuthCodeSuccess))
+	return c
+}
+
+func expectInvalid(v rtrjwt.Validator, tkn string, code rtrhdr.AuthCode) {
+	_, c, e := v.Validate(context.Background(), tkn)
+	Expect(e).To(HaveOccurred())
+	Expect(c).To(BeEquivalentTo(code))
+}
+
+var _ = Describe("Router Auth JWT", func() {
+	var (
+		rsaKey *rsa.PrivateKey
+		ecKey  *ecdsa.PrivateKey
+		edPub  ed25519.PublicKey
+		edKey  ed25519.PrivateKey
+	)
+
+	BeforeEach(func() {
+		var e error
+
+		rsaKey, e = rsa.GenerateKey(rand.Reader, 2048)
+		Expect(e).ToNot(HaveOccurred())
+
+		ecKey, e = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(e).ToNot(HaveOccurred())
+
+		edPub, edKey, e = ed25519.GenerateKey(rand.Reader)
+		Expect(e).ToNot(HaveOccurred())
+	})
+
+	Context("Validate the signature of a token", func() {
+		It("Must validate an HMAC token with the shared secret", func() {
+			v := newValidator(rtrjwt.Config{Secret: testSecret})
+
+			c := expectValid(v, sign(jwtsdk.SigningMethodHS256, "", []byte(testSecret), claims()))
+			Expect(c.Subject()).To(Equal("alice"))
+
+			expectInvalid(v, sign(jwtsdk.SigningMethodHS256, "", []byte("another secret"), claims()), rtrhdr.AuthCodeRequire)
+		})
+
+		It("Must validate RSA, ECDSA and EdDSA tokens with the JWKS", func() {
+			srv := newJWKSServer(rsaJWK("rsa", &rsaKey.PublicKey), ecJWK("ec", &ecKey.PublicKey), edJWK("ed", edPub))
+			defer srv.Close()
+
+			v := newValidator(rtrjwt.Config{JWKSUrl: srv.URL + "/jwks"})
+
+			expectValid(v, sign(jwtsdk.SigningMethodRS256, "rsa", rsaKey, claims()))
+			expectValid(v, sign(jwtsdk.SigningMethodES256, "ec", ecKey, claims()))
+			expectValid(v, sign(jwtsdk.SigningMethodEdDSA, "ed", edKey, claims()))
+			Expect(srv.hits.Load()).To(BeNumerically("==", 1))
+
+			// a key of another type with the same kid must not be used
+			expectInvalid(v, sign(jwtsdk.SigningMethodES256, "rsa", ecKey, claims()), rtrhdr.AuthCodeRequire)
+		})
+
+		It("Must find the JWKS with the discovery document", func() {
+			srv := newJWKSServer(rsaJWK("rsa", &rsaKey.PublicKey))
+			defer srv.Close()
+
+			v := newValidator(rtrjwt.Config{Discovery: srv.URL + "/.well-known/openid-configuration"})
+			expectValid(v, sign(jwtsdk.SigningMethodRS256, "rsa", rsaKey, claims()))
+		})
+
+		It("Must skip the unsupported keys of the JWKS", func() {
+			srv := newJWKSServer(
+				jwkMap{"kty": "EC", "kid": "old", "crv": "P-192", "x": "AA", "y": "AA"},
+				jwkMap{"kty": "unknown", "kid": "other"},
+				rsaJWK("rsa", &rsaKey.PublicKey),
+			)
+			defer srv.Close()
+
+			v := newValidator(rtrjwt.Config{JWKSUrl: srv.URL + "/jwks"})
+			expectValid(v, sign(jwtsdk.SigningMethodRS256, "rsa", rsaKey, claims()))
+		})
+
+		It("Must refuse an algorithm not allowed", func() {
+			srv := newJWKSServer(rsaJWK("rsa", &rsaKey.PublicKey))
+			defer srv.Close()
+
+			v := newValidator(rtrjwt.Config{Secret: testSecret, JWKSUrl: srv.URL + "/jwks", Algorithms: []string{"RS256"}})
+
+			expectValid(v, sign(jwtsdk.SigningMethodRS256, "rsa", rsaKey, claims()))
+			expectInvalid(v, sign(jwtsdk.SigningMethodHS256, "", []byte(testSecret), claims()), rtrhdr.AuthCodeRequire)
+		})
+	})
+
+	Context("Reload the JWKS", func() {
+		It("Must reload on an unknown key id to follow a rotation", func() {
+			srv := newJWKSServer(rsaJWK("k1", &rsaKey.PublicKey))
+			defer srv.Close()
+
+			v := newValidator(rtrjwt.Config{JWKSUrl: srv.URL + "/jwks"})
+			expectValid(v, sign(jwtsdk.SigningMethodRS256, "k1", rsaKey, claims()))
+
+			srv.setKeys(ecJWK("k2", &ecKey.PublicKey))
+			expectValid(v, sign(jwtsdk.SigningMethodES256, "k2", ecKey, claims()))
+			Expect(srv.hits.Load()).To(BeNumerically("==", 2))
+
+			// the reload on unknown key id is limited
+			expectInvalid(v, sign(jwtsdk.SigningMethodEdDSA, "k3", edKey, claims()), rtrhdr.AuthCodeRequire)
+			Expect(srv.hits.Load()).To(BeNumerically("==", 2))
+		})
+
+		It("Must keep the loaded keys when the reload fails", func() {
+			srv := newJWKSServer(rsaJWK("rsa", &rsaKey.PublicKey))
+			defer srv.Close()
+
+			v := newValidator(rtrjwt.Config{JWKSUrl: srv.URL + "/jwks", JWKSRefresh: libdur.ParseDuration(50 * time.Millisecond)})
+			tkn := sign(jwtsdk.SigningMethodRS256, "rsa", rsaKey, claims())
+			expectValid(v, tkn)
+
+			srv.fail.Store(true)
+			time.Sleep(100 * time.Millisecond)
+
+			expectValid(v, tkn)
+			Expect(srv.hits.Load()).To(BeNumerically("==", 2))
+
+			// no new request before the backoff delay
+			expectValid(v, tkn)
+			Expect(srv.hits.Load()).To(BeNumerically("==", 2))
+		})
+
+		It("Must return an error when no key could be loaded", func() {
+			srv := newJWKSServer()
+			srv.fail.Store(true)
+			defer srv.Close()
+
+			v := newValidator(rtrjwt.Config{JWKSUrl: srv.URL + "/jwks"})
+			expectInvalid(v, sign(jwtsdk.SigningMethodRS256, "rsa", rsaKey, claims()), rtrhdr.AuthCodeError)
+		})
+
+		It("Must load the JWKS once for concurrent requests", func() {
+			srv := newJWKSServer(rsaJWK("rsa", &rsaKey.PublicKey))
+			srv.slow = 100 * time.Millisecond
+			defer srv.Close()
+
+			var (
+				v   = newValidator(rtrjwt.Config{JWKSUrl: srv.URL + "/jwks"})
+				tkn = sign(jwtsdk.SigningMethodRS256, "rsa", rsaKey, claims())
+				wg  sync.WaitGroup
+				ok  atomic.Int32
+			)
+
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+
+					if _, c, e := v.Validate(context.Background(), tkn); e == nil && c == rtrhdr.AuthCodeSuccess {
+						ok.Add(1)
+					}
+				}()
+			}
+
+			wg.Wait()
+			Expect(ok.Load()).To(BeNumerically("==", 10))
+			Expect(srv.hits.Load()).To(BeNumerically("==", 1))
+		})
+	})
+
+	Context("Validate the claims of a token", func() {
+		var v rtrjwt.Validator
+
+		BeforeEach(func() {
+			v = newValidator(rtrjwt.Config{
+				Secret:    testSecret,
+				Issuer:    []string{"https://issuer.example.com"},
+				Audience:  []string{"api", "other"},
+				ClockSkew: libdur.ParseDuration(30 * time.Second),
+			})
+		})
+
+		It("Must check the issuer and the audience", func() {
+			expectValid(v, sign(jwtsdk.SigningMethodHS256, "", []byte(testSecret), claims("aud", []string{"web", "other"})))
+			expectInvalid(v, sign(jwtsdk.SigningMethodHS256, "", []byte(testSecret), claims("iss", "https://evil.example.com")), rtrhdr.AuthCodeForbidden)
+			expectInvalid(v, sign(jwtsdk.SigningMethodHS256, "", []byte(testSecret), claims("aud", "web")), rtrhdr.AuthCodeForbidden)
+		})
+
+		It("Must check the exp and nbf claims with the clock skew", func() {
+			expectValid(v, sign(jwtsdk.SigningMethodHS256, "", []byte(testSecret), claims("exp", time.Now().Add(-10*time.Second).Unix())))
+			expectValid(v, sign(jwtsdk.SigningMethodHS256, "", []byte(testSecret), claims("nbf", time.Now().Add(10*time.Second).Unix())))
+			expectInvalid(v, sign(jwtsdk.SigningMethodHS256, "", []byte(testSecret), claims("exp", time.Now().Add(-time.Minute).Unix())), rtrhdr.AuthCodeRequire)
+			expectInvalid(v, sign(jwtsdk.SigningMethodHS256, "", []byte(testSecret), claims("nbf", time.Now().Add(time.Minute).Unix())), rtrhdr.AuthCodeRequire)
+		})
+
+		It("Must refuse a token without subject", func() {
+			c := claims()
+			delete(c, "sub")
+			expectInvalid(v, sign(jwtsdk.SigningMethodHS256, "", []byte(testSecret), c), rtrhdr.AuthCodeForbidden)
+		})
+
+		It("Must store the subject and the claims into the gin context", func() {
+			ginsdk.SetMode(ginsdk.TestMode)
+			c, _ := ginsdk.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+			code, e := v.Check(c, sign(jwtsdk.SigningMethodHS256, "", []byte(testSecret), claims("roles", []string{"admin"})))
+			Expect(e).ToNot(HaveOccurred())
+			Expect(code).To(BeEquivalentTo(rtrhdr.AuthCodeSuccess))
+
+			Expect(c.GetString(librtr.GinContextRequestUser)).To(Equal("alice"))
+			Expect(rtrjwt.GetClaims(c)).ToNot(BeNil())
+			Expect(rtrjwt.GetClaims(c).Strings("roles")).To(Equal([]string{"admin"}))
+		})
+	})
+})
@@ -0,0 +1,78 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package authjwt
+
+import "strings"
+
+// Claims is the set of claims of a validated token.
+type Claims map[string]interface{}
+
+// String return the value of the given claim if it is a string.
+func (c Claims) String(name string) string {
+	if v, ok := c[name].(string); ok {
+		return v
+	}
+
+	return ""
+}
+
+// Strings return the values of the given claim. A string claim is split on spaces (like the scope claim),
+// a list claim return all of its string items.
+func (c Claims) Strings(name string) []string {
+	switch v := c[name].(type) {
+	case string:
+		return strings.Fields(v)
+	case []string:
+		return v
+	case []interface{}:
+		var res = make([]string, 0, len(v))
+
+		for _, i := range v {
+			if s, ok := i.(string); ok {
+				res = append(res, s)
+			}
+		}
+
+		return res
+	}
+
+	return nil
+}
+
+// Subject return the sub claim.
+func (c Claims) Subject() string {
+	return c.String("sub")
+}
+
+// Issuer return the iss claim.
+func (c Claims) Issuer() string {
+	return c.String("iss")
+}
+
+// Audience return the aud claim.
+func (c Claims) Audience() []string {
+	return c.Strings("aud")
+}
@@ -0,0 +1,153 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package authjwt
+
+import (
+	"fmt"
+	"time"
+
+	libval "github.com/go-playground/validator/v10"
+	libdur "github.com/nabbar/golib/duration"
+	liberr "github.com/nabbar/golib/errors"
+)
+
+const (
+	defaultRefresh   = time.Hour
+	defaultTimeout   = 10 * time.Second
+	defaultSubject   = "sub"
+	defaultRoles     = "roles"
+	defaultScope     = "scope"
+	defaultGroups    = "groups"
+	minRefreshOnMiss = time.Minute
+	retryOnFailure   = 10 * time.Second
+)
+
+type Config struct {
+	// Issuer define the list of allowed issuers (iss claim). If empty, the issuer is not checked.
+	Issuer []string `json:"issuer,omitempty" yaml:"issuer,omitempty" toml:"issuer,omitempty" mapstructure:"issuer,omitempty"`
+
+	// Audience define the list of allowed audiences : the token must contain at least one of them (aud claim).
+	// If empty, the audience is not checked.
+	Audience []string `json:"audience,omitempty" yaml:"audience,omitempty" toml:"audience,omitempty" mapstructure:"audience,omitempty"`
+
+	// Algorithms define the list of allowed signing algorithms (HS256, RS256, ES256, EdDSA, ...).
+	// If empty, all algorithms matching a known key are allowed.
+	Algorithms []string `json:"algorithms,omitempty" yaml:"algorithms,omitempty" toml:"algorithms,omitempty" mapstructure:"algorithms,omitempty"`
+
+	// Secret define the shared key used to validate HMAC signed tokens (HS256, HS384, HS512).
+	Secret string `json:"secret,omitempty" yaml:"secret,omitempty" toml:"secret,omitempty" mapstructure:"secret,omitempty"`
+
+	// Discovery define the OpenID Connect discovery url (.well-known/openid-configuration) used to find the JWKS url.
+	Discovery string `json:"discovery,omitempty" yaml:"discovery,omitempty" toml:"discovery,omitempty" mapstructure:"discovery,omitempty" validate:"omitempty,url"`
+
+	// JWKSUrl define the url of the JWKS used to validate the signature of tokens.
+	JWKSUrl string `json:"jwks-url,omitempty" yaml:"jwks-url,omitempty" toml:"jwks-url,omitempty" mapstructure:"jwks-url,omitempty" validate:"omitempty,url"`
+
+	// JWKSFile define the path of a local JWKS file used to validate the signature of tokens.
+	JWKSFile string `json:"jwks-file,omitempty" yaml:"jwks-file,omitempty" toml:"jwks-file,omitempty" mapstructure:"jwks-file,omitempty"`
+
+	// JWKSRefresh define the duration the JWKS is kept in cache before being reloaded. Default is 1 hour.
+	JWKSRefresh libdur.Duration `json:"jwks-refresh,omitempty" yaml:"jwks-refresh,omitempty" toml:"jwks-refresh,omitempty" mapstructure:"jwks-refresh,omitempty"`
+
+	// Timeout define the timeout of the requests sent to retrieve the JWKS. Default is 10 seconds.
+	Timeout libdur.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty" toml:"timeout,omitempty" mapstructure:"timeout,omitempty"`
+
+	// ClockSkew define the tolerance applied on the exp, nbf and iat claims.
+	ClockSkew libdur.Duration `json:"clock-skew,omitempty" yaml:"clock-skew,omitempty" toml:"clock-skew,omitempty" mapstructure:"clock-skew,omitempty"`
+
+	// RequireExpiration define if the exp claim is mandatory.
+	RequireExpiration bool `json:"require-expiration,omitempty" yaml:"require-expiration,omitempty" toml:"require-expiration,omitempty" mapstructure:"require-expiration,omitempty"`
+
+	// SubjectClaim define the claim used as the user of the request. Default is "sub".
+	SubjectClaim string `json:"subject-claim,omitempty" yaml:"subject-claim,omitempty" toml:"subject-claim,omitempty" mapstructure:"subject-claim,omitempty"`
+
+	// RolesClaim define the claim used as the roles of the caller for route policies. Default is "roles".
+	RolesClaim string `json:"roles-claim,omitempty" yaml:"roles-claim,omitempty" toml:"roles-claim,omitempty" mapstructure:"roles-claim,omitempty"`
+
+	// ScopeClaim define the claim used as the scopes of the caller for route policies. Default is "scope".
+	ScopeClaim string `json:"scope-claim,omitempty" yaml:"scope-claim,omitempty" toml:"scope-claim,omitempty" mapstructure:"scope-claim,omitempty"`
+
+	// GroupsClaim define the claim used as the groups of the caller for route policies. Default is "groups".
+	GroupsClaim string `json:"groups-claim,omitempty" yaml:"groups-claim,omitempty" toml:"groups-claim,omitempty" mapstructure:"groups-claim,omitempty"`
+}
+
+func (c Config) Validate() liberr.Error {
+	err := ErrorConfigValidator.Error(nil)
+
+	if er := libval.New().Struct(c); er != nil {
+		if e, ok := er.(*libval.InvalidValidationError); ok {
+			err.Add(e)
+		}
+
+		for _, e := range er.(libval.ValidationErrors) {
+			//nolint goerr113
+			err.Add(fmt.Errorf("config field '%s' is not validated by constraint '%s'", e.Namespace(), e.ActualTag()))
+		}
+	}
+
+	if len(c.Secret) < 1 && len(c.Discovery) < 1 && len(c.JWKSUrl) < 1 && len(c.JWKSFile) < 1 {
+		//nolint goerr113
+		err.Add(fmt.Errorf("at least one of secret, discovery, jwks-url or jwks-file must be set"))
+	}
+
+	if !err.HasParent() {
+		err = nil
+	}
+
+	return err
+}
+
+func (c Config) refresh() time.Duration {
+	if d := c.JWKSRefresh.Time(); d > 0 {
+		return d
+	}
+
+	return defaultRefresh
+}
+
+func (c Config) timeout() time.Duration {
+	if d := c.Timeout.Time(); d > 0 {
+		return d
+	}
+
+	return defaultTimeout
+}
+
+func (c Config) subject() string {
+	if len(c.SubjectClaim) > 0 {
+		return c.SubjectClaim
+	}
+
+	return defaultSubject
+}
+
+func (c Config) claim(val, def string) string {
+	if len(val) > 0 {
+		return val
+	}
+
+	return def
+}
@@ -0,0 +1,106 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package authjwt
+
+import (
+	"context"
+	"net/http"
+
+	ginsdk "github.com/gin-gonic/gin"
+	liberr "github.com/nabbar/golib/errors"
+	liblog "github.com/nabbar/golib/logger"
+	librtr "github.com/nabbar/golib/router"
+	rtraut "github.com/nabbar/golib/router/auth"
+	rtrhdr "github.com/nabbar/golib/router/authheader"
+)
+
+const (
+	// AuthType is the authorization scheme of bearer tokens.
+	AuthType = "BEARER"
+
+	// GinContextClaims is the gin context key used to store the claims of a validated token.
+	GinContextClaims = "gin-ctx-jwt-claims"
+)
+
+type Validator interface {
+	// Validate parse the given token, check its signature and its claims (exp, nbf, iat, iss, aud)
+	// and return the claims of the token. The code is the authorization code to return to the client.
+	Validate(ctx context.Context, token string) (Claims, rtrhdr.AuthCode, liberr.Error)
+
+	// Check validate the given token and store the subject under router.GinContextRequestUser
+	// and the claims under GinContextClaims into the gin context.
+	// This func can be used with auth.NewAuthorizationContext.
+	Check(c *ginsdk.Context, token string) (rtrhdr.AuthCode, liberr.Error)
+
+	// SetHTTPClient define the http client used to retrieve the discovery document and the JWKS.
+	SetHTTPClient(fct func() *http.Client)
+
+	// SetLogger define the logger used to report the JWKS reload failures and the skipped keys.
+	SetLogger(fct liblog.FuncLog)
+
+	// Authorization return a bearer authorization using this validator.
+	// The given logger is also used by the validator if not nil (see SetLogger).
+	Authorization(log liblog.FuncLog) rtraut.Authorization
+
+	// PolicyResolver return a resolver of the caller identity for the route policies of a router.RouterList.
+	// The identity is given by the claims of the token already validated for the request, or by the bearer
+	// token of the Authorization header validated by the resolver.
+	PolicyResolver() librtr.FuncPolicyResolver
+}
+
+// New return a validator of JWT for the given config.
+func New(cfg Config) (Validator, liberr.Error) {
+	if e := cfg.Validate(); e != nil {
+		return nil, e
+	}
+
+	return &val{
+		c: cfg,
+		k: newKeySet(cfg),
+	}, nil
+}
+
+// NewAuthorization return a bearer authorization validating JWT with the given config.
+func NewAuthorization(log liblog.FuncLog, cfg Config) (rtraut.Authorization, liberr.Error) {
+	if v, e := New(cfg); e != nil {
+		return nil, e
+	} else {
+		return v.Authorization(log), nil
+	}
+}
+
+// GetClaims return the claims of the token validated for the given gin context, or nil.
+func GetClaims(c *ginsdk.Context) Claims {
+	if c == nil {
+		return nil
+	} else if i, ok := c.Get(GinContextClaims); !ok {
+		return nil
+	} else if v, k := i.(Claims); !k {
+		return nil
+	} else {
+		return v
+	}
+}
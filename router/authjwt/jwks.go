@@ -0,0 +1,489 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package authjwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	liberr "github.com/nabbar/golib/errors"
+	liblog "github.com/nabbar/golib/logger"
+	loglvl "github.com/nabbar/golib/logger/level"
+)
+
+const maxJWKSSize = 1 << 20
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	K   string `json:"k,omitempty"`
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+type key struct {
+	i string      // key id
+	a string      // algorithm if given
+	k interface{} // public or shared key
+}
+
+type keySet struct {
+	m sync.Mutex
+	c Config
+	h func() *http.Client
+	l liblog.FuncLog
+
+	u string       // jwks url found with the discovery
+	k []key        // loaded keys
+	t time.Time    // time of the last successful load
+	f time.Time    // time of the last forced reload on unknown key id
+	e time.Time    // time of the last failed load
+	r liberr.Error // error of the last failed load
+	w *reload      // running load shared by all callers
+}
+
+// reload is a load of the keys shared by the concurrent callers.
+type reload struct {
+	d chan struct{}
+	e liberr.Error
+}
+
+func newKeySet(cfg Config) *keySet {
+	return &keySet{
+		m: sync.Mutex{},
+		c: cfg,
+	}
+}
+
+func (s *keySet) setClient(fct func() *http.Client) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.h = fct
+}
+
+func (s *keySet) setLogger(fct liblog.FuncLog) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.l = fct
+}
+
+func (s *keySet) logger() liblog.Logger {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.l == nil {
+		return nil
+	}
+
+	return s.l()
+}
+
+func (s *keySet) client() *http.Client {
+	s.m.Lock()
+	h := s.h
+	s.m.Unlock()
+
+	if h != nil {
+		if c := h(); c != nil {
+			return c
+		}
+	}
+
+	return &http.Client{
+		Timeout: s.c.timeout(),
+	}
+}
+
+func (s *keySet) hasRemote() bool {
+	return len(s.c.Discovery) > 0 || len(s.c.JWKSUrl) > 0 || len(s.c.JWKSFile) > 0
+}
+
+// get return the keys matching the given key id and algorithm.
+// The keys are reloaded if the cache is expired, or if no key is matching
+// (at most once per minute, to follow a key rotation). If the reload fails,
+// the keys already loaded are still used.
+func (s *keySet) get(ctx context.Context, kid, alg string) ([]interface{}, liberr.Error) {
+	var res []interface{}
+
+	if len(s.c.Secret) > 0 && strings.HasPrefix(alg, "HS") {
+		res = append(res, []byte(s.c.Secret))
+	}
+
+	if !s.hasRemote() {
+		return res, nil
+	}
+
+	if s.expired() {
+		if e := s.reload(ctx); e != nil && !s.loaded() {
+			return res, e
+		}
+	}
+
+	if r := s.find(kid, alg); len(r) > 0 {
+		return append(res, r...), nil
+	} else if len(kid) < 1 || !s.miss() {
+		return res, nil
+	}
+
+	if e := s.reload(ctx); e != nil && !s.loaded() {
+		return res, e
+	}
+
+	return append(res, s.find(kid, alg)...), nil
+}
+
+func (s *keySet) expired() bool {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return time.Since(s.t) > s.c.refresh()
+}
+
+func (s *keySet) loaded() bool {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return !s.t.IsZero()
+}
+
+// miss checks if a reload is allowed for an unknown key id and register it.
+func (s *keySet) miss() bool {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if time.Since(s.f) < minRefreshOnMiss {
+		return false
+	}
+
+	s.f = time.Now()
+	return true
+}
+
+// reload load the keys without holding the lock. Concurrent callers wait for the
+// same load, and no load is started before the backoff delay after a failure.
+func (s *keySet) reload(ctx context.Context) liberr.Error {
+	s.m.Lock()
+
+	if w := s.w; w != nil {
+		s.m.Unlock()
+
+		select {
+		case <-w.d:
+			return w.e
+		case <-ctx.Done():
+			return ErrorJWKSFetch.Error(ctx.Err())
+		}
+	} else if !s.e.IsZero() && time.Since(s.e) < retryOnFailure {
+		e := s.r
+		s.m.Unlock()
+		return e
+	}
+
+	w := &reload{
+		d: make(chan struct{}),
+	}
+
+	s.w = w
+	s.m.Unlock()
+
+	// the load is shared : it must not be canceled with the request starting it
+	k, e := s.load(context.WithoutCancel(ctx))
+
+	if e != nil {
+		if l := s.logger(); l != nil {
+			l.Entry(loglvl.ErrorLevel, "cannot reload JWKS").ErrorAdd(true, e).Log()
+		}
+	}
+
+	s.m.Lock()
+
+	if e != nil {
+		s.e = time.Now()
+		s.r = e
+	} else {
+		s.k = k
+		s.t = time.Now()
+		s.e = time.Time{}
+		s.r = nil
+	}
+
+	s.w = nil
+	w.e = e
+	s.m.Unlock()
+
+	close(w.d)
+	return e
+}
+
+func (s *keySet) find(kid, alg string) []interface{} {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var res = make([]interface{}, 0)
+
+	for _, k := range s.k {
+		if len(kid) > 0 && k.i != kid {
+			continue
+		} else if len(k.a) > 0 && len(alg) > 0 && k.a != alg {
+			continue
+		} else if !keyMatchAlg(k.k, alg) {
+			continue
+		}
+
+		res = append(res, k.k)
+	}
+
+	return res
+}
+
+// load retrieve and decode the JWKS. Keys not used for signature or of an unsupported type are skipped.
+func (s *keySet) load(ctx context.Context) ([]key, liberr.Error) {
+	var (
+		buf []byte
+		err liberr.Error
+	)
+
+	if len(s.c.JWKSFile) > 0 {
+		if p, e := os.ReadFile(s.c.JWKSFile); e != nil {
+			return nil, ErrorJWKSFetch.Error(e)
+		} else {
+			buf = p
+		}
+	} else if u, e := s.url(ctx); e != nil {
+		return nil, e
+	} else if buf, err = s.fetch(ctx, u); err != nil {
+		return nil, err
+	}
+
+	var set = jwks{}
+
+	if e := json.Unmarshal(buf, &set); e != nil {
+		return nil, ErrorJWKSDecode.Error(e)
+	}
+
+	var (
+		res = make([]key, 0, len(set.Keys))
+		log = s.logger()
+	)
+
+	for _, k := range set.Keys {
+		if len(k.Use) > 0 && k.Use != "sig" {
+			continue
+		} else if p, e := k.key(); e != nil {
+			if log != nil {
+				log.Entry(loglvl.WarnLevel, "skipping JWKS key").FieldAdd("jwks.kid", k.Kid).FieldAdd("jwks.kty", k.Kty).ErrorAdd(true, e).Log()
+			}
+			continue
+		} else {
+			res = append(res, key{i: k.Kid, a: k.Alg, k: p})
+		}
+	}
+
+	return res, nil
+}
+
+func (s *keySet) url(ctx context.Context) (string, liberr.Error) {
+	if len(s.c.JWKSUrl) > 0 {
+		return s.c.JWKSUrl, nil
+	}
+
+	s.m.Lock()
+	u := s.u
+	s.m.Unlock()
+
+	if len(u) > 0 {
+		return u, nil
+	}
+
+	buf, err := s.fetch(ctx, s.c.Discovery)
+	if err != nil {
+		return "", err
+	}
+
+	var dsc = struct {
+		JWKSUri string `json:"jwks_uri"`
+	}{}
+
+	if e := json.Unmarshal(buf, &dsc); e != nil {
+		return "", ErrorJWKSDecode.Error(e)
+	} else if len(dsc.JWKSUri) < 1 {
+		//nolint goerr113
+		return "", ErrorJWKSDecode.Error(fmt.Errorf("missing jwks_uri into discovery document"))
+	}
+
+	s.m.Lock()
+	s.u = dsc.JWKSUri
+	s.m.Unlock()
+
+	return dsc.JWKSUri, nil
+}
+
+func (s *keySet) fetch(ctx context.Context, uri string) ([]byte, liberr.Error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, ErrorJWKSFetch.Error(err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	rsp, err := s.client().Do(req)
+	if err != nil {
+		return nil, ErrorJWKSFetch.Error(err)
+	}
+
+	defer func() {
+		_ = rsp.Body.Close()
+	}()
+
+	if rsp.StatusCode != http.StatusOK {
+		//nolint goerr113
+		return nil, ErrorJWKSFetch.Error(fmt.Errorf("unexpected status '%s' from '%s'", rsp.Status, uri))
+	}
+
+	buf, err := io.ReadAll(io.LimitReader(rsp.Body, maxJWKSSize))
+	if err != nil {
+		return nil, ErrorJWKSFetch.Error(err)
+	}
+
+	return buf, nil
+}
+
+func (k jwk) key() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, e := decodeInt(k.N)
+		if e != nil {
+			return nil, e
+		}
+
+		x, e := decodeInt(k.E)
+		if e != nil {
+			return nil, e
+		} else if !x.IsInt64() {
+			//nolint goerr113
+			return nil, fmt.Errorf("invalid RSA exponent for key '%s'", k.Kid)
+		}
+
+		return &rsa.PublicKey{N: n, E: int(x.Int64())}, nil
+
+	case "EC":
+		var c elliptic.Curve
+
+		switch k.Crv {
+		case "P-256":
+			c = elliptic.P256()
+		case "P-384":
+			c = elliptic.P384()
+		case "P-521":
+			c = elliptic.P521()
+		default:
+			//nolint goerr113
+			return nil, fmt.Errorf("unsupported curve '%s' for key '%s'", k.Crv, k.Kid)
+		}
+
+		x, e := decodeInt(k.X)
+		if e != nil {
+			return nil, e
+		}
+
+		y, e := decodeInt(k.Y)
+		if e != nil {
+			return nil, e
+		}
+
+		return &ecdsa.PublicKey{Curve: c, X: x, Y: y}, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			//nolint goerr113
+			return nil, fmt.Errorf("unsupported curve '%s' for key '%s'", k.Crv, k.Kid)
+		}
+
+		p, e := base64.RawURLEncoding.DecodeString(k.X)
+		if e != nil {
+			return nil, e
+		} else if len(p) != ed25519.PublicKeySize {
+			//nolint goerr113
+			return nil, fmt.Errorf("invalid Ed25519 key size for key '%s'", k.Kid)
+		}
+
+		return ed25519.PublicKey(p), nil
+
+	case "oct":
+		return base64.RawURLEncoding.DecodeString(k.K)
+	}
+
+	//nolint goerr113
+	return nil, fmt.Errorf("unsupported key type '%s' for key '%s'", k.Kty, k.Kid)
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	p, e := base64.RawURLEncoding.DecodeString(s)
+	if e != nil {
+		return nil, e
+	}
+
+	return new(big.Int).SetBytes(p), nil
+}
+
+func keyMatchAlg(k interface{}, alg string) bool {
+	switch k.(type) {
+	case []byte:
+		return strings.HasPrefix(alg, "HS")
+	case *rsa.PublicKey:
+		return strings.HasPrefix(alg, "RS") || strings.HasPrefix(alg, "PS")
+	case *ecdsa.PublicKey:
+		return strings.HasPrefix(alg, "ES")
+	case ed25519.PublicKey:
+		return alg == "EdDSA"
+	}
+
+	return false
+}
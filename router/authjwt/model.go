@@ -0,0 +1,154 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package authjwt
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	ginsdk "github.com/gin-gonic/gin"
+	jwtsdk "github.com/golang-jwt/jwt/v5"
+	liberr "github.com/nabbar/golib/errors"
+	liblog "github.com/nabbar/golib/logger"
+	librtr "github.com/nabbar/golib/router"
+	rtraut "github.com/nabbar/golib/router/auth"
+	rtrhdr "github.com/nabbar/golib/router/authheader"
+)
+
+type val struct {
+	c Config
+	k *keySet
+}
+
+func (o *val) SetHTTPClient(fct func() *http.Client) {
+	o.k.setClient(fct)
+}
+
+func (o *val) SetLogger(fct liblog.FuncLog) {
+	o.k.setLogger(fct)
+}
+
+func (o *val) Authorization(log liblog.FuncLog) rtraut.Authorization {
+	if log != nil {
+		o.k.setLogger(log)
+	}
+
+	return rtraut.NewAuthorizationContext(log, AuthType, o.Check)
+}
+
+func (o *val) Check(c *ginsdk.Context, token string) (rtrhdr.AuthCode, liberr.Error) {
+	var ctx context.Context = c
+
+	if c != nil && c.Request != nil {
+		ctx = c.Request.Context()
+	}
+
+	clm, code, err := o.Validate(ctx, token)
+
+	if code != rtrhdr.AuthCodeSuccess || c == nil {
+		return code, err
+	}
+
+	sub := clm.String(o.c.subject())
+	c.Set(librtr.GinContextRequestUser, sub)
+	c.Set(GinContextClaims, clm)
+
+	return code, nil
+}
+
+func (o *val) Validate(ctx context.Context, token string) (Claims, rtrhdr.AuthCode, liberr.Error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var (
+		fet liberr.Error
+		clm = jwtsdk.MapClaims{}
+		opt = []jwtsdk.ParserOption{
+			jwtsdk.WithLeeway(o.c.ClockSkew.Time()),
+			jwtsdk.WithIssuedAt(),
+		}
+	)
+
+	if len(o.c.Algorithms) > 0 {
+		opt = append(opt, jwtsdk.WithValidMethods(o.c.Algorithms))
+	}
+
+	if o.c.RequireExpiration {
+		opt = append(opt, jwtsdk.WithExpirationRequired())
+	}
+
+	_, err := jwtsdk.NewParser(opt...).ParseWithClaims(token, clm, func(t *jwtsdk.Token) (interface{}, error) {
+		var (
+			kid, _ = t.Header["kid"].(string)
+			alg    = t.Method.Alg()
+		)
+
+		lst, e := o.k.get(ctx, kid, alg)
+
+		if e != nil {
+			fet = e
+			return nil, e
+		} else if len(lst) < 1 {
+			return nil, jwtsdk.ErrTokenUnverifiable
+		}
+
+		var res = jwtsdk.VerificationKeySet{
+			Keys: make([]jwtsdk.VerificationKey, 0, len(lst)),
+		}
+
+		for _, k := range lst {
+			res.Keys = append(res.Keys, k)
+		}
+
+		return res, nil
+	})
+
+	if fet != nil {
+		return nil, rtrhdr.AuthCodeError, fet
+	} else if err != nil {
+		return nil, rtrhdr.AuthCodeRequire, ErrorTokenInvalid.Error(err)
+	}
+
+	var res = Claims(clm)
+
+	if len(o.c.Issuer) > 0 && !slices.Contains(o.c.Issuer, res.Issuer()) {
+		return nil, rtrhdr.AuthCodeForbidden, ErrorTokenIssuer.Error(nil)
+	}
+
+	if len(o.c.Audience) > 0 && !slices.ContainsFunc(res.Audience(), func(s string) bool {
+		return slices.Contains(o.c.Audience, s)
+	}) {
+		return nil, rtrhdr.AuthCodeForbidden, ErrorTokenAudience.Error(nil)
+	}
+
+	if len(res.String(o.c.subject())) < 1 {
+		return nil, rtrhdr.AuthCodeForbidden, ErrorTokenSubject.Error(nil)
+	}
+
+	return res, rtrhdr.AuthCodeSuccess, nil
+}
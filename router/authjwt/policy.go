@@ -0,0 +1,64 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package authjwt
+
+import (
+	"strings"
+
+	ginsdk "github.com/gin-gonic/gin"
+	liberr "github.com/nabbar/golib/errors"
+	librtr "github.com/nabbar/golib/router"
+	rtrhdr "github.com/nabbar/golib/router/authheader"
+)
+
+func (o *val) PolicyResolver() librtr.FuncPolicyResolver {
+	return func(c *ginsdk.Context) (librtr.Identity, liberr.Error) {
+		var clm = GetClaims(c)
+
+		if clm == nil {
+			var tkn string
+
+			if s := strings.SplitN(c.Request.Header.Get(rtrhdr.HeaderAuthSend), " ", 2); len(s) == 2 && strings.ToUpper(s[0]) == AuthType {
+				tkn = strings.TrimSpace(s[1])
+			}
+
+			if len(tkn) < 1 {
+				return librtr.Identity{}, librtr.ErrorHeaderAuthMissing.Error(nil)
+			} else if _, e := o.Check(c, tkn); e != nil {
+				return librtr.Identity{}, e
+			} else if clm = GetClaims(c); clm == nil {
+				return librtr.Identity{}, ErrorTokenInvalid.Error(nil)
+			}
+		}
+
+		return librtr.Identity{
+			User:   clm.String(o.c.subject()),
+			Roles:  clm.Strings(o.c.claim(o.c.RolesClaim, defaultRoles)),
+			Scopes: clm.Strings(o.c.claim(o.c.ScopeClaim, defaultScope)),
+			Groups: clm.Strings(o.c.claim(o.c.GroupsClaim, defaultGroups)),
+		}, nil
+	}
+}
@@ -40,6 +40,9 @@ const (
 	ErrorHeaderAuthEmpty
 	ErrorHeaderAuthRequire
 	ErrorHeaderAuthForbidden
+	ErrorPolicyResolver
+	ErrorPolicyIdentity
+	ErrorPolicyForbidden
 )
 
 func init() {
@@ -65,6 +68,12 @@ func getMessage(code liberr.CodeError) (message string) {
 		return "authorization check success but unauthorized client"
 	case ErrorHeaderAuth:
 		return "authorization check return an invalid response code"
+	case ErrorPolicyResolver:
+		return "missing policy resolver to check the route policy"
+	case ErrorPolicyIdentity:
+		return "cannot resolve the identity of the caller"
+	case ErrorPolicyForbidden:
+		return "caller is not allowed by the route policy"
 	}
 
 	return liberr.NullMessage
@@ -0,0 +1,60 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package router
+
+import (
+	ginsdk "github.com/gin-gonic/gin"
+	liberr "github.com/nabbar/golib/errors"
+)
+
+// ErrorReturn return a new ReturnGin instance to render an error for the current request :
+// the one defined on the RouterList of the route with SetErrorReturn or the global default
+// of errors package (see liberr.SetDefaultReturnGin).
+func ErrorReturn(c *ginsdk.Context) liberr.ReturnGin {
+	if c != nil {
+		if i, ok := c.Get(GinContextErrorReturn); ok {
+			if f, k := i.(liberr.FuncReturnGin); k && f != nil {
+				if r := f(); r != nil {
+					return r
+				}
+			}
+		}
+	}
+
+	return liberr.NewReturnGin()
+}
+
+// ErrorAbort render the given error with the ReturnGin of the current request (see ErrorReturn)
+// and abort the request with the given http status code (0 for internal server error).
+func ErrorAbort(c *ginsdk.Context, httpCode int, err liberr.Error) {
+	if err == nil {
+		return
+	}
+
+	r := ErrorReturn(c)
+	err.Return(r)
+	r.GinTonicErrorAbort(c, httpCode)
+}
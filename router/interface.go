@@ -29,6 +29,7 @@ import (
 	"os"
 
 	ginsdk "github.com/gin-gonic/gin"
+	liberr "github.com/nabbar/golib/errors"
 )
 
 const (
@@ -36,6 +37,12 @@ const (
 	GinContextStartUnixNanoTime = "gin-ctx-start-unix-nano-time"
 	GinContextRequestPath       = "gin-ctx-request-path"
 	GinContextRequestUser       = "gin-ctx-request-user"
+	GinContextRequestID         = "gin-ctx-request-id"
+	GinContextTraceParent       = "gin-ctx-trace-parent"
+	GinContextErrorReturn       = "gin-ctx-error-return"
+
+	HeaderRequestID   = "X-Request-ID"
+	HeaderTraceParent = "traceparent"
 )
 
 var (
@@ -57,6 +64,27 @@ type RouterList interface {
 	RegisterMergeInGroup(group, method string, relativePath string, router ...ginsdk.HandlerFunc)
 	Handler(engine *ginsdk.Engine)
 	Engine() *ginsdk.Engine
+
+	// SetErrorReturn define the ReturnGin implementation used to render errors for all
+	// routes of this list (see ErrorReturn). If not set, the global default of errors package is used.
+	SetErrorReturn(fct liberr.FuncReturnGin)
+
+	// SetPolicyResolver define the func used to resolve the identity (user, roles, scopes, groups)
+	// of the caller of routes having a policy. The policy is checked before the handlers of the route,
+	// so an authenticator registered as route handler (auth.Authorization) is not run yet : the resolver
+	// must authenticate the caller itself, or rely on an authenticator run as middleware of the engine.
+	SetPolicyResolver(fct FuncPolicyResolver)
+
+	// SetGroupPolicy attach the given policy to all routes of the given group ("" for routes without group).
+	SetGroupPolicy(group string, pol Policy)
+
+	// SetRoutePolicy attach the given policy to the given route. This policy and the policy of its group
+	// are checked separately and both must allow the caller, so a route policy could only restrict the access.
+	// A caller not allowed is rejected with a 403 status rendered with the ErrorReturn of the request.
+	SetRoutePolicy(group, method, relativePath string, pol Policy)
+
+	// PolicyTable return the effective policy of all registered routes, sorted by path and method.
+	PolicyTable() PolicyTable
 }
 
 func NewRouterList(initGin func() *ginsdk.Engine) RouterList {
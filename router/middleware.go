@@ -150,6 +150,10 @@ func GinErrorLog(log liblog.FuncLog) ginsdk.HandlerFunc {
 			} else if l := log(); l == nil {
 				return
 			} else {
+				if len(c.Errors) > 0 || rec != nil {
+					// add request ID and trace ID of the request if any
+					l = l.WithContext(c)
+				}
 				if len(c.Errors) > 0 {
 					for _, e := range c.Errors {
 						ent := l.Entry(loglvl.ErrorLevel, "error on request \"%s %s %s\"", c.Request.Method, path, c.Request.Proto)
@@ -27,28 +27,65 @@ package router
 
 import (
 	ginsdk "github.com/gin-gonic/gin"
+	liberr "github.com/nabbar/golib/errors"
 )
 
 type rtr struct {
 	init func() *ginsdk.Engine
 	list map[string][]itm
+	ret  liberr.FuncReturnGin
+	res  FuncPolicyResolver
+	grp  map[string]Policy
+	pol  map[string]Policy
 }
 
 func (l *rtr) Handler(engine *ginsdk.Engine) {
 	for grpRoute, grpList := range l.list {
 		if grpRoute == EmptyHandlerGroup {
 			for _, r := range grpList {
-				engine.Handle(r.method, r.relative, r.router...)
+				engine.Handle(r.method, r.relative, l.handlers(grpRoute, r)...)
 			}
 		} else {
 			var grp = engine.Group(grpRoute)
 			for _, r := range grpList {
-				grp.Handle(r.method, r.relative, r.router...)
+				grp.Handle(r.method, r.relative, l.handlers(grpRoute, r)...)
 			}
 		}
 	}
 }
 
+func (l *rtr) SetErrorReturn(fct liberr.FuncReturnGin) {
+	l.ret = fct
+}
+
+// handlers prepend to the route handlers a handler storing the error return of the list into the gin context
+// and a handler checking the policies of the route. The policy is checked before the route handlers,
+// including their authenticator : the policy resolver must not trust an identity not authenticated yet.
+func (l *rtr) handlers(group string, r itm) []ginsdk.HandlerFunc {
+	var (
+		pol = l.policy(group, r)
+		res = make([]ginsdk.HandlerFunc, 0, len(r.router)+2)
+	)
+
+	if l.ret == nil && len(pol) < 1 {
+		return r.router
+	}
+
+	if l.ret != nil {
+		var fct = l.ret
+
+		res = append(res, func(c *ginsdk.Context) {
+			c.Set(GinContextErrorReturn, fct)
+		})
+	}
+
+	if len(pol) > 0 {
+		res = append(res, l.policyHandler(pol))
+	}
+
+	return append(res, r.router...)
+}
+
 func (l *rtr) RegisterInGroup(group, method, relativePath string, router ...ginsdk.HandlerFunc) {
 	if group == "" {
 		group = EmptyHandlerGroup
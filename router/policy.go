@@ -0,0 +1,336 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package router
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	ginsdk "github.com/gin-gonic/gin"
+	liberr "github.com/nabbar/golib/errors"
+)
+
+// Policy define the authorization required to call a route.
+// Each non empty list is a condition and all conditions must be satisfied :
+//   - Roles : the caller must have at least one of the roles
+//   - Scopes : the caller must have all the scopes
+//   - Groups : the caller must be member of at least one of the groups (case insensitive)
+type Policy struct {
+	Roles  []string `json:"roles,omitempty" yaml:"roles,omitempty" toml:"roles,omitempty" mapstructure:"roles,omitempty"`
+	Scopes []string `json:"scopes,omitempty" yaml:"scopes,omitempty" toml:"scopes,omitempty" mapstructure:"scopes,omitempty"`
+	Groups []string `json:"groups,omitempty" yaml:"groups,omitempty" toml:"groups,omitempty" mapstructure:"groups,omitempty"`
+}
+
+// Identity is the caller of a route as given by a FuncPolicyResolver.
+type Identity struct {
+	User   string   `json:"user,omitempty"`
+	Roles  []string `json:"roles,omitempty"`
+	Scopes []string `json:"scopes,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+}
+
+// FuncPolicyResolver is used to resolve the identity of the caller of a route having a policy.
+// An error will abort the request with a 401 status.
+type FuncPolicyResolver func(c *ginsdk.Context) (Identity, liberr.Error)
+
+// PolicyRule is the effective policy of a registered route : the policy of its group and its own policy,
+// each one must allow the caller. A route without policy is public.
+type PolicyRule struct {
+	Group    string   `json:"group,omitempty"`
+	Method   string   `json:"method"`
+	Path     string   `json:"path"`
+	Policies []Policy `json:"policies,omitempty"`
+}
+
+// PolicyTable is the list of the effective policy of all registered routes.
+type PolicyTable []PolicyRule
+
+func (p Policy) IsEmpty() bool {
+	return len(p.Roles) < 1 && len(p.Scopes) < 1 && len(p.Groups) < 1
+}
+
+// Allow return true if the given identity satisfy the policy.
+func (p Policy) Allow(id Identity) bool {
+	if len(p.Roles) > 0 && !slices.ContainsFunc(p.Roles, func(s string) bool {
+		return slices.Contains(id.Roles, s)
+	}) {
+		return false
+	}
+
+	for _, s := range p.Scopes {
+		if !slices.Contains(id.Scopes, s) {
+			return false
+		}
+	}
+
+	if len(p.Groups) > 0 && !slices.ContainsFunc(p.Groups, func(s string) bool {
+		return slices.ContainsFunc(id.Groups, func(g string) bool {
+			return strings.EqualFold(s, g)
+		})
+	}) {
+		return false
+	}
+
+	return true
+}
+
+func (p Policy) String() string {
+	if p.IsEmpty() {
+		return "public"
+	}
+
+	var res = make([]string, 0, 3)
+
+	if len(p.Roles) > 0 {
+		res = append(res, "roles="+strings.Join(p.Roles, ","))
+	}
+
+	if len(p.Scopes) > 0 {
+		res = append(res, "scopes="+strings.Join(p.Scopes, ","))
+	}
+
+	if len(p.Groups) > 0 {
+		res = append(res, "groups="+strings.Join(p.Groups, ","))
+	}
+
+	return strings.Join(res, " ")
+}
+
+func (r PolicyRule) String() string {
+	if len(r.Policies) < 1 {
+		return "public"
+	}
+
+	var res = make([]string, 0, len(r.Policies))
+
+	for _, p := range r.Policies {
+		res = append(res, p.String())
+	}
+
+	return strings.Join(res, " and ")
+}
+
+func (t PolicyTable) MarshalText() ([]byte, error) {
+	var (
+		buf = bytes.NewBuffer(make([]byte, 0))
+		siz = 0
+	)
+
+	for _, r := range t {
+		if n := len(r.Method) + 1 + len(r.Path); n > siz {
+			siz = n
+		}
+	}
+
+	for _, r := range t {
+		_, _ = fmt.Fprintf(buf, "%-*s  %s\n", siz, r.Method+" "+r.Path, r.String())
+	}
+
+	return buf.Bytes(), nil
+}
+
+// MergePolicyResolver return a resolver merging the identities given by all the given resolvers.
+// The user is the first non empty one. An error of any resolver is returned.
+func MergePolicyResolver(fct ...FuncPolicyResolver) FuncPolicyResolver {
+	return func(c *ginsdk.Context) (Identity, liberr.Error) {
+		var res = Identity{}
+
+		for _, f := range fct {
+			if f == nil {
+				continue
+			} else if i, e := f(c); e != nil {
+				return Identity{}, e
+			} else {
+				if len(res.User) < 1 {
+					res.User = i.User
+				}
+
+				res.Roles = mergeList(res.Roles, i.Roles)
+				res.Scopes = mergeList(res.Scopes, i.Scopes)
+				res.Groups = mergeList(res.Groups, i.Groups)
+			}
+		}
+
+		return res, nil
+	}
+}
+
+// NewGroupResolver return a resolver giving as groups the result of the given func for the user of the request.
+// The user is the one stored under GinContextRequestUser by an authenticator already run for the request : a
+// middleware of the engine or a resolver merged before this one (see MergePolicyResolver). Without user, the
+// request is rejected. The func could be the UserMemberOf func of a ldap.HelperLDAP.
+func NewGroupResolver(fct func(user string) ([]string, liberr.Error)) FuncPolicyResolver {
+	return func(c *ginsdk.Context) (Identity, liberr.Error) {
+		var usr = c.GetString(GinContextRequestUser)
+
+		if len(usr) < 1 {
+			return Identity{}, ErrorHeaderAuthMissing.Error(nil)
+		} else if fct == nil {
+			return Identity{User: usr}, nil
+		} else if grp, err := fct(usr); err != nil {
+			return Identity{}, err
+		} else {
+			return Identity{User: usr, Groups: grp}, nil
+		}
+	}
+}
+
+func mergeList(a, b []string) []string {
+	if len(a) < 1 && len(b) < 1 {
+		return nil
+	}
+
+	var res = make([]string, 0, len(a)+len(b))
+
+	for _, s := range append(append(make([]string, 0, len(a)+len(b)), a...), b...) {
+		if !slices.Contains(res, s) {
+			res = append(res, s)
+		}
+	}
+
+	return res
+}
+
+func policyKey(group, method, relative string) string {
+	if group == "" {
+		group = EmptyHandlerGroup
+	}
+
+	return group + " " + strings.ToUpper(method) + " " + relative
+}
+
+func (l *rtr) SetPolicyResolver(fct FuncPolicyResolver) {
+	l.res = fct
+}
+
+func (l *rtr) SetGroupPolicy(group string, pol Policy) {
+	if group == "" {
+		group = EmptyHandlerGroup
+	}
+
+	if l.grp == nil {
+		l.grp = make(map[string]Policy)
+	}
+
+	l.grp[group] = pol
+}
+
+func (l *rtr) SetRoutePolicy(group, method, relativePath string, pol Policy) {
+	if l.pol == nil {
+		l.pol = make(map[string]Policy)
+	}
+
+	l.pol[policyKey(group, method, relativePath)] = pol
+}
+
+// policy return the non empty policies of the given route : the policy of its group and its own policy.
+// They are not merged, as a route policy must only restrict the access given by its group policy.
+func (l *rtr) policy(group string, r itm) []Policy {
+	var res = make([]Policy, 0, 2)
+
+	if p, ok := l.grp[group]; ok && !p.IsEmpty() {
+		res = append(res, p)
+	}
+
+	if p, ok := l.pol[policyKey(group, r.method, r.relative)]; ok && !p.IsEmpty() {
+		res = append(res, p)
+	}
+
+	if len(res) < 1 {
+		return nil
+	}
+
+	return res
+}
+
+func (l *rtr) PolicyTable() PolicyTable {
+	var res = make(PolicyTable, 0)
+
+	for grp, lst := range l.list {
+		for _, r := range lst {
+			var (
+				g = grp
+				p = r.relative
+			)
+
+			if g == EmptyHandlerGroup {
+				g = ""
+			} else {
+				p = path.Join(g, r.relative)
+			}
+
+			res = append(res, PolicyRule{
+				Group:    g,
+				Method:   strings.ToUpper(r.method),
+				Path:     p,
+				Policies: l.policy(grp, r),
+			})
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Path != res[j].Path {
+			return res[i].Path < res[j].Path
+		}
+
+		return res[i].Method < res[j].Method
+	})
+
+	return res
+}
+
+// policyHandler return a handler checking the identity of the caller with each of the given policies.
+func (l *rtr) policyHandler(pol []Policy) ginsdk.HandlerFunc {
+	return func(c *ginsdk.Context) {
+		if l.res == nil {
+			ErrorAbort(c, http.StatusInternalServerError, ErrorPolicyResolver.Error(nil))
+			return
+		}
+
+		id, err := l.res(c)
+
+		if err != nil {
+			ErrorAbort(c, http.StatusUnauthorized, ErrorPolicyIdentity.Error(err))
+			return
+		}
+
+		if len(id.User) > 0 {
+			c.Set(GinContextRequestUser, sanitizeString(id.User))
+		}
+
+		for _, p := range pol {
+			if !p.Allow(id) {
+				ErrorAbort(c, http.StatusForbidden, ErrorPolicyForbidden.Error(nil))
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2019 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	ginsdk "github.com/gin-gonic/gin"
+	liberr "github.com/nabbar/golib/errors"
+	librtr "github.com/nabbar/golib/router"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func newEngine() *ginsdk.Engine {
+	ginsdk.SetMode(ginsdk.TestMode)
+	return ginsdk.New()
+}
+
+// serve register the route on the router list and return the response of a request to this route.
+func serve(l librtr.RouterList, method, path string, req *http.Request, h ...ginsdk.HandlerFunc) *httptest.ResponseRecorder {
+	var (
+		e = l.Engine()
+		w = httptest.NewRecorder()
+	)
+
+	l.Register(method, path, h...)
+	l.Handler(e)
+	e.ServeHTTP(w, req)
+
+	return w
+}
+
+func errorHandler(c *ginsdk.Context) {
+	librtr.ErrorAbort(c, http.StatusNotFound, liberr.New(60200, "item not found", liberr.New(60201, "missing key")))
+}
+
+var _ = Describe("Router Error Return", func() {
+	AfterEach(func() {
+		liberr.SetDefaultReturnGin(nil)
+	})
+
+	Context("Render an error with the default return", func() {
+		It("Must write a json body", func() {
+			w := serve(librtr.NewRouterList(newEngine), http.MethodGet, "/item", httptest.NewRequest(http.MethodGet, "/item", nil), errorHandler)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+			Expect(w.Header().Get("Content-Type")).ToNot(ContainSubstring(liberr.ContentTypeProblemJSON))
+
+			var r map[string]interface{}
+			Expect(json.Unmarshal(w.Body.Bytes(), &r)).ToNot(HaveOccurred())
+			Expect(r["Code"]).To(Equal("60200"))
+			Expect(r["Message"]).To(Equal("item not found"))
+		})
+	})
+
+	Context("Render an error with the problem return selected globally", func() {
+		It("Must write a problem+json body", func() {
+			liberr.SetDefaultReturnGin(func() liberr.ReturnGin {
+				return liberr.NewProblemReturn()
+			})
+
+			w := serve(librtr.NewRouterList(newEngine), http.MethodGet, "/item", httptest.NewRequest(http.MethodGet, "/item", nil), errorHandler)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+			Expect(w.Header().Get("Content-Type")).To(Equal(liberr.ContentTypeProblemJSON))
+
+			var r liberr.ProblemReturn
+			Expect(json.Unmarshal(w.Body.Bytes(), &r)).ToNot(HaveOccurred())
+			Expect(r.Type).To(Equal("urn:golib:error:60200"))
+			Expect(r.Title).To(Equal("item not found"))
+			Expect(r.Detail).To(Equal("missing key"))
+			Expect(r.Status).To(Equal(http.StatusNotFound))
+			Expect(r.Instance).To(Equal("/item"))
+		})
+	})
+
+	Context("Render an error with the problem return selected by router list", func() {
+		It("Must write a problem+json body only for the routes of the list", func() {
+			var (
+				prb = librtr.NewRouterList(newEngine)
+				def = librtr.NewRouterList(newEngine)
+			)
+
+			prb.SetErrorReturn(func() liberr.ReturnGin {
+				return liberr.NewProblemReturn()
+			})
+
+			w := serve(prb, http.MethodGet, "/item", httptest.NewRequest(http.MethodGet, "/item", nil), errorHandler)
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+			Expect(w.Header().Get("Content-Type")).To(Equal(liberr.ContentTypeProblemJSON))
+
+			var r liberr.ProblemReturn
+			Expect(json.Unmarshal(w.Body.Bytes(), &r)).ToNot(HaveOccurred())
+			Expect(r.Code).To(Equal(60200))
+			Expect(r.Errors).To(HaveLen(1))
+
+			w = serve(def, http.MethodGet, "/item", httptest.NewRequest(http.MethodGet, "/item", nil), errorHandler)
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+			Expect(w.Header().Get("Content-Type")).ToNot(ContainSubstring(liberr.ContentTypeProblemJSON))
+		})
+	})
+})
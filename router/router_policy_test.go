@@ -0,0 +1,216 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2019 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	ginsdk "github.com/gin-gonic/gin"
+	liberr "github.com/nabbar/golib/errors"
+	librtr "github.com/nabbar/golib/router"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const headerUser = "X-Test-User"
+
+// userMiddleware simulate an authenticator run as middleware of the engine.
+func userMiddleware(c *ginsdk.Context) {
+	if u := c.GetHeader(headerUser); len(u) > 0 {
+		c.Set(librtr.GinContextRequestUser, u)
+	}
+}
+
+func memberOf(user string) ([]string, liberr.Error) {
+	switch user {
+	case "bob":
+		return []string{"admins", "users"}, nil
+	default:
+		return []string{"users"}, nil
+	}
+}
+
+// policyEngine return an engine with a route '/admin/users' allowed to the group 'Admins'.
+func policyEngine(res librtr.FuncPolicyResolver, middleware bool) *ginsdk.Engine {
+	l := librtr.NewRouterList(func() *ginsdk.Engine {
+		e := newEngine()
+
+		if middleware {
+			e.Use(userMiddleware)
+		}
+
+		return e
+	})
+
+	if res != nil {
+		l.SetPolicyResolver(res)
+	}
+
+	l.SetGroupPolicy("/admin", librtr.Policy{Groups: []string{"Admins"}})
+	l.RegisterInGroup("/admin", http.MethodGet, "/users", func(c *ginsdk.Context) {
+		c.String(http.StatusOK, c.GetString(librtr.GinContextRequestUser))
+	})
+
+	e := l.Engine()
+	l.Handler(e)
+
+	return e
+}
+
+func policyRequest(e *ginsdk.Engine, fct func(r *http.Request)) *httptest.ResponseRecorder {
+	var (
+		w = httptest.NewRecorder()
+		r = httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+	)
+
+	if fct != nil {
+		fct(r)
+	}
+
+	e.ServeHTTP(w, r)
+	return w
+}
+
+var _ = Describe("Router Policy", func() {
+	Context("Resolve the groups of the caller", func() {
+		It("Must reject a basic authorization header not authenticated", func() {
+			e := policyEngine(librtr.NewGroupResolver(memberOf), false)
+
+			w := policyRequest(e, func(r *http.Request) {
+				r.SetBasicAuth("bob", "any password")
+			})
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("Must use the user authenticated by a middleware", func() {
+			e := policyEngine(librtr.NewGroupResolver(memberOf), true)
+
+			w := policyRequest(e, func(r *http.Request) {
+				r.Header.Set(headerUser, "bob")
+			})
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Body.String()).To(Equal("bob"))
+
+			w = policyRequest(e, func(r *http.Request) {
+				r.Header.Set(headerUser, "eve")
+			})
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+
+			w = policyRequest(e, nil)
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("Must use the user authenticated by a resolver merged before", func() {
+			auth := func(c *ginsdk.Context) (librtr.Identity, liberr.Error) {
+				if u := c.GetHeader(headerUser); len(u) < 1 {
+					return librtr.Identity{}, librtr.ErrorHeaderAuthMissing.Error(nil)
+				} else {
+					c.Set(librtr.GinContextRequestUser, u)
+					return librtr.Identity{User: u}, nil
+				}
+			}
+
+			e := policyEngine(librtr.MergePolicyResolver(auth, librtr.NewGroupResolver(memberOf)), false)
+
+			w := policyRequest(e, func(r *http.Request) {
+				r.Header.Set(headerUser, "bob")
+			})
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			w = policyRequest(e, func(r *http.Request) {
+				r.SetBasicAuth("bob", "any password")
+			})
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("Must fail without policy resolver", func() {
+			w := policyRequest(policyEngine(nil, true), func(r *http.Request) {
+				r.Header.Set(headerUser, "bob")
+			})
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Context("Check the group and route policies", func() {
+		It("Must not loosen the group policy with the route policy", func() {
+			l := librtr.NewRouterList(newEngine)
+			l.SetPolicyResolver(func(c *ginsdk.Context) (librtr.Identity, liberr.Error) {
+				switch c.GetHeader(headerUser) {
+				case "root":
+					return librtr.Identity{User: "root", Roles: []string{"admin", "superadmin"}}, nil
+				case "bob":
+					return librtr.Identity{User: "bob", Roles: []string{"admin"}}, nil
+				default:
+					return librtr.Identity{User: "eve", Roles: []string{"superadmin"}}, nil
+				}
+			})
+			l.SetGroupPolicy("/admin", librtr.Policy{Roles: []string{"admin"}})
+			l.SetRoutePolicy("/admin", http.MethodGet, "/users", librtr.Policy{Roles: []string{"superadmin"}})
+			l.RegisterInGroup("/admin", http.MethodGet, "/users", func(c *ginsdk.Context) {
+				c.String(http.StatusOK, c.GetString(librtr.GinContextRequestUser))
+			})
+
+			e := l.Engine()
+			l.Handler(e)
+
+			w := policyRequest(e, func(r *http.Request) {
+				r.Header.Set(headerUser, "bob")
+			})
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+
+			w = policyRequest(e, func(r *http.Request) {
+				r.Header.Set(headerUser, "eve")
+			})
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+
+			w = policyRequest(e, func(r *http.Request) {
+				r.Header.Set(headerUser, "root")
+			})
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Body.String()).To(Equal("root"))
+		})
+	})
+
+	Context("Dump the effective policies", func() {
+		It("Must list the group and route policies", func() {
+			l := librtr.NewRouterList(newEngine)
+			l.SetGroupPolicy("/admin", librtr.Policy{Roles: []string{"admin"}})
+			l.SetRoutePolicy("/admin", http.MethodDelete, "/users", librtr.Policy{Scopes: []string{"users:write"}})
+			l.RegisterInGroup("/admin", http.MethodDelete, "/users")
+			l.Register(http.MethodGet, "/health")
+
+			Expect(l.PolicyTable()).To(Equal(librtr.PolicyTable{
+				{Group: "/admin", Method: http.MethodDelete, Path: "/admin/users", Policies: []librtr.Policy{{Roles: []string{"admin"}}, {Scopes: []string{"users:write"}}}},
+				{Method: http.MethodGet, Path: "/health"},
+			}))
+
+			p, e := l.PolicyTable().MarshalText()
+			Expect(e).ToNot(HaveOccurred())
+			Expect(string(p)).To(Equal("DELETE /admin/users  roles=admin and scopes=users:write\nGET /health          public\n"))
+		})
+	})
+})
@@ -0,0 +1,71 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2019 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	ginsdk "github.com/gin-gonic/gin"
+	librtr "github.com/nabbar/golib/router"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Router Request Trace", func() {
+	Context("Propagate the request trace", func() {
+		It("Must keep the request ID and the trace ID of the caller", func() {
+			var (
+				tid = "4bf92f3577b34da6a3ce929d0e0e4736"
+				req = httptest.NewRequest(http.MethodGet, "/trace", nil)
+			)
+
+			req.Header.Set(librtr.HeaderRequestID, "my-request")
+			req.Header.Set(librtr.HeaderTraceParent, "00-"+tid+"-00f067aa0ba902b7-01")
+
+			w := serve(librtr.NewRouterList(newEngine), http.MethodGet, "/trace", req, librtr.GinRequestTrace, func(c *ginsdk.Context) {
+				c.String(http.StatusOK, c.GetString(librtr.GinContextRequestID))
+			})
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Body.String()).To(Equal("my-request"))
+			Expect(w.Header().Get(librtr.HeaderRequestID)).To(Equal("my-request"))
+			Expect(strings.HasPrefix(w.Header().Get(librtr.HeaderTraceParent), "00-"+tid+"-")).To(BeTrue())
+			Expect(strings.HasSuffix(w.Header().Get(librtr.HeaderTraceParent), "-01")).To(BeTrue())
+		})
+
+		It("Must not panic without context or request", func() {
+			Expect(func() {
+				librtr.GinRequestTrace(nil)
+			}).ToNot(Panic())
+
+			Expect(func() {
+				librtr.GinRequestTrace(&ginsdk.Context{})
+			}).ToNot(Panic())
+		})
+	})
+})
@@ -0,0 +1,133 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2024 Nicolas JUHEL
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package router
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	ginsdk "github.com/gin-gonic/gin"
+	lbuuid "github.com/hashicorp/go-uuid"
+	logfld "github.com/nabbar/golib/logger/fields"
+)
+
+const maxRequestIDLength = 128
+
+// GinRequestTrace propagate or generate the request ID (X-Request-ID header) and the
+// W3C trace context (traceparent header) of the request.
+// The request ID, trace ID and span ID are stored into the request context as logger fields
+// (see logger.WithContext) and into the gin context. The response headers contain the
+// request ID and the traceparent with the span of this request, to be used for downstream calls.
+func GinRequestTrace(c *ginsdk.Context) {
+	if c == nil {
+		return
+	} else if c.Request == nil {
+		c.Next()
+		return
+	}
+
+	var (
+		rid = sanitizeString(c.GetHeader(HeaderRequestID))
+		tid string
+		sid = randomHex(8)
+		flg = "00"
+	)
+
+	if len(rid) < 1 || len(rid) > maxRequestIDLength {
+		if u, e := lbuuid.GenerateUUID(); e == nil {
+			rid = u
+		} else {
+			rid = randomHex(16)
+		}
+	}
+
+	if t, _, f, ok := parseTraceParent(c.GetHeader(HeaderTraceParent)); ok {
+		tid, flg = t, f
+	} else {
+		tid = randomHex(16)
+	}
+
+	tpr := "00-" + tid + "-" + sid + "-" + flg
+
+	ctx := logfld.ContextWithRequestID(c.Request.Context(), rid)
+	ctx = logfld.ContextWithTrace(ctx, tid, sid)
+
+	c.Request = c.Request.WithContext(ctx)
+	c.Set(logfld.ContextKey, logfld.FromContext(ctx))
+	c.Set(GinContextRequestID, rid)
+	c.Set(GinContextTraceParent, tpr)
+
+	c.Header(HeaderRequestID, rid)
+	c.Header(HeaderTraceParent, tpr)
+
+	// Process request
+	c.Next()
+}
+
+// parseTraceParent parse a W3C traceparent header 'version-traceid-parentid-flags'
+// and return the trace ID, the parent span ID and the flags.
+func parseTraceParent(s string) (traceID, parentID, flags string, ok bool) {
+	p := strings.Split(strings.TrimSpace(s), "-")
+
+	if len(p) < 4 {
+		return "", "", "", false
+	} else if len(p[0]) != 2 || !isHex(p[0]) || p[0] == "ff" || (p[0] == "00" && len(p) != 4) {
+		return "", "", "", false
+	} else if len(p[1]) != 32 || !isHex(p[1]) || isZero(p[1]) {
+		return "", "", "", false
+	} else if len(p[2]) != 16 || !isHex(p[2]) || isZero(p[2]) {
+		return "", "", "", false
+	} else if len(p[3]) != 2 || !isHex(p[3]) {
+		return "", "", "", false
+	}
+
+	return p[1], p[2], p[3], true
+}
+
+func isHex(s string) bool {
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isZero(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
+
+func randomHex(n int) string {
+	var b = make([]byte, n)
+
+	if _, e := rand.Read(b); e != nil || isZero(hex.EncodeToString(b)) {
+		b[n-1] = 1
+	}
+
+	return hex.EncodeToString(b)
+}
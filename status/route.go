@@ -130,9 +130,9 @@ func (o *sts) getErrorReturn() liberr.ReturnGin {
 	defer o.m.RUnlock()
 
 	if o.r == nil {
-		return liberr.NewDefaultReturn()
+		return liberr.NewReturnGin()
 	} else if r := o.r(); r == nil {
-		return liberr.NewDefaultReturn()
+		return liberr.NewReturnGin()
 	} else {
 		return r
 	}